@@ -0,0 +1,303 @@
+package trisads
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/bbengfort/trisads/store"
+	"github.com/bbengfort/trisads/validate"
+	"github.com/golang/protobuf/proto"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Prefix of the full method names of the TRISAAdmin service RPCs.
+const adminServicePrefix = "/pb.TRISAAdmin/"
+
+// authenticate is a unary interceptor that requires requests to the TRISAAdmin service
+// to present the admin token as a bearer token in the authorization metadata. If no
+// admin token is configured, the admin service is disabled. Requests to the public
+// TRISADirectory service are passed through without authentication.
+func (s *Server) authenticate(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !strings.HasPrefix(info.FullMethod, adminServicePrefix) {
+		return handler(ctx, req)
+	}
+
+	if s.conf.AdminToken == "" {
+		return nil, status.Error(codes.PermissionDenied, "admin service is disabled")
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing admin credentials")
+	}
+
+	for _, auth := range md.Get("authorization") {
+		token := strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.conf.AdminToken)) == 1 {
+			return handler(ctx, req)
+		}
+	}
+
+	log.Warn().Str("method", info.FullMethod).Msg("unauthenticated admin request")
+	return nil, status.Error(codes.Unauthenticated, "invalid admin credentials")
+}
+
+// ListPending returns the VASPs whose contact email has been verified and that are
+// waiting for the TRISA admins to review their registration.
+func (s *Server) ListPending(ctx context.Context, in *pb.ListPendingRequest) (out *pb.ListPendingReply, err error) {
+	out = &pb.ListPendingReply{}
+
+	var vasps []pb.VASP
+	if vasps, err = s.Pending(); err != nil {
+		log.Error().Err(err).Msg("could not list pending VASPs")
+		return out, s.fail(&out.Error, err)
+	}
+
+	out.Vasps = make([]*pb.VASP, len(vasps))
+	for i := 0; i < len(vasps); i++ {
+		out.Vasps[i] = &vasps[i]
+	}
+	return out, nil
+}
+
+// Review approves the registration of a VASP that is pending review and queues the
+// issuance of its TRISA certificates.
+func (s *Server) Review(ctx context.Context, in *pb.ReviewRequest) (out *pb.ReviewReply, err error) {
+	out = &pb.ReviewReply{}
+
+	var vasp pb.VASP
+	vasp, err = s.Approve(in.Id, actor(ctx))
+	if vasp.Id > 0 {
+		out.Vasp = &vasp
+	}
+
+	if err != nil {
+		log.Warn().Err(err).Uint64("id", in.Id).Msg("could not approve VASP")
+		return out, s.fail(&out.Error, err, vaspResource(in.Id, ""))
+	}
+	return out, nil
+}
+
+// Reject the registration of a VASP during review, notifying the VASP contact. The
+// verification secrets are removed from the record since it can no longer be verified.
+func (s *Server) Reject(ctx context.Context, in *pb.RejectRequest) (out *pb.RejectReply, err error) {
+	out = &pb.RejectReply{}
+
+	var vasp pb.VASP
+	if vasp, err = s.db.Retrieve(in.Id); err != nil {
+		log.Warn().Err(err).Uint64("id", in.Id).Msg("could not retrieve VASP to reject")
+		return out, s.fail(&out.Error, err, vaspResource(in.Id, ""))
+	}
+
+	vasp.VerificationStatus = pb.VerificationState_REJECTED
+	vasp.VerificationToken = ""
+	vasp.Pkcs12Password = ""
+	if err = s.db.Update(vasp, actor(ctx)); err != nil {
+		log.Warn().Err(err).Uint64("id", in.Id).Msg("could not reject VASP")
+		return out, s.fail(&out.Error, err)
+	}
+	log.Info().Uint64("id", in.Id).Str("reason", in.Reason).Msg("VASP registration rejected")
+
+	// Failing to notify the VASP does not affect the rejection
+	if err = s.Notify(pb.Email{Vasp: vasp.Id, Type: pb.EmailType_REJECTION, Reason: in.Reason}); err != nil {
+		log.Error().Err(err).Uint64("id", in.Id).Msg("could not queue rejection email")
+	}
+
+	out.Vasp = &vasp
+	return out, nil
+}
+
+// Revoke the TRISA certificate of a VASP, e.g. if its private key was compromised or it
+// ceased operations. The VASP is moved into the revoked state.
+func (s *Server) Revoke(ctx context.Context, in *pb.RevokeRequest) (out *pb.RevokeReply, err error) {
+	out = &pb.RevokeReply{}
+
+	var vasp pb.VASP
+	if vasp, err = s.RevokeCertificate(in.Id, in.Reason, actor(ctx)); err != nil {
+		log.Warn().Err(err).Uint64("id", in.Id).Msg("could not revoke VASP certificate")
+		return out, s.fail(&out.Error, err, vaspResource(in.Id, ""))
+	}
+
+	out.Vasp = &vasp
+	return out, nil
+}
+
+// UpdateVASP allows the TRISA admins to edit a VASP record, e.g. to correct the entity
+// details submitted during registration. The verification state and secrets cannot be
+// modified directly, they are managed by the verification workflow; a full replacement
+// keeps the stored certificate and ids of the VASP. If an update mask is
+// specified, only the fields of the entity and certification in its paths are updated
+// and validated, and the other fields of the VASP are unchanged; the ids of the nested
+// records and the revocation of the certificate cannot be masked. The update must
+// specify the version of the VASP that was edited; if the VASP has been modified since,
+// the update is aborted so the admin can retry with the latest version.
+func (s *Server) UpdateVASP(ctx context.Context, in *pb.UpdateVASPRequest) (out *pb.UpdateVASPReply, err error) {
+	out = &pb.UpdateVASPReply{}
+	if in.Vasp == nil || in.Vasp.Id == 0 {
+		return out, s.fail(&out.Error, store.ErrIncompleteRecord, badRequest("vasp.id", "the id of the VASP to update is required"))
+	}
+
+	var vasp pb.VASP
+	if vasp, err = s.db.Retrieve(in.Vasp.Id); err != nil {
+		log.Warn().Err(err).Uint64("id", in.Vasp.Id).Msg("could not retrieve VASP to update")
+		return out, s.fail(&out.Error, err, vaspResource(in.Vasp.Id, ""))
+	}
+
+	// The tombstones of delisted VASPs cannot be edited
+	if vasp.VerificationStatus == pb.VerificationState_DELISTED {
+		log.Warn().Uint64("id", vasp.Id).Msg("cannot update delisted VASP")
+		return out, s.fail(&out.Error, ErrDelisted, vaspResource(vasp.Id, ""))
+	}
+
+	// The update must be made to the version of the VASP that was edited so that
+	// concurrent edits are not overwritten (VASPs stored before records were versioned
+	// are version zero)
+	if in.Vasp.Version == 0 && vasp.Version != 0 {
+		err = status.Error(codes.InvalidArgument, "the version of the VASP that was edited is required")
+		return out, s.fail(&out.Error, err, badRequest("vasp.version", "specify the version of the VASP that was edited"))
+	}
+
+	var update pb.VASP
+	if in.UpdateMask != nil {
+		update = *proto.Clone(&vasp).(*pb.VASP)
+		if err = applyMask(&update, in.Vasp, in.UpdateMask); err != nil {
+			log.Warn().Err(err).Uint64("id", vasp.Id).Msg("could not apply VASP update mask")
+			return out, s.fail(&out.Error, err, badRequest("updateMask", err.Error()))
+		}
+	} else {
+		update = *in.Vasp
+		update.FirstListed = vasp.FirstListed
+		update.VerificationStatus = vasp.VerificationStatus
+		update.VerificationToken = vasp.VerificationToken
+		update.Pkcs12Password = vasp.Pkcs12Password
+		update.VerifiedOn = vasp.VerifiedOn
+		update.DelistedOn = vasp.DelistedOn
+		update.DelistReason = vasp.DelistReason
+		update.PurgeAfter = vasp.PurgeAfter
+
+		// The certificate is issued and revoked by the verification workflow, so it can
+		// only be corrected with an update mask, and the ids of the records are kept
+		update.VaspTRISACertification = vasp.VaspTRISACertification
+		preserve(proto.MessageReflect(&update), proto.MessageReflect(&vasp), nil)
+	}
+	update.Version = in.Vasp.Version
+
+	// The entity is normalized and validated after the update mask is applied to the
+	// stored VASP, but only the violations of the masked fields are reported so that
+	// legacy records which fail newer validation rules can still be patched
+	validate.Normalize(update.VaspEntity)
+	if err = validate.Entity(update.VaspEntity); err != nil && in.UpdateMask != nil {
+		if violations := maskViolations(err.(validate.Violations), in.UpdateMask); len(violations) > 0 {
+			err = violations
+		} else {
+			err = nil
+		}
+	}
+
+	if err != nil {
+		log.Warn().Err(err).Uint64("id", update.Id).Msg("invalid VASP update")
+		return out, s.fail(&out.Error, err, fieldViolations("vasp.vaspEntity", err.(validate.Violations)))
+	}
+
+	if err = s.db.Update(update, actor(ctx)); err != nil {
+		log.Warn().Err(err).Uint64("id", update.Id).Msg("could not update VASP")
+		return out, s.fail(&out.Error, err, vaspResource(update.Id, ""))
+	}
+	log.Info().Uint64("id", update.Id).Strs("mask", in.UpdateMask.GetPaths()).Msg("VASP updated")
+
+	// Return the stored record to reflect any store managed fields
+	if vasp, err = s.db.Retrieve(update.Id); err != nil {
+		return out, s.fail(&out.Error, err)
+	}
+	redact(&vasp)
+	out.Vasp = &vasp
+	return out, nil
+}
+
+// DeleteVASP delists a VASP from the directory, leaving a tombstone with the reason it
+// was delisted that is purged by the Compactor after the grace period of the request or
+// the server default. Any certificate request or email that is queued for the VASP is
+// abandoned by the background workers.
+func (s *Server) DeleteVASP(ctx context.Context, in *pb.DeleteVASPRequest) (out *pb.DeleteVASPReply, err error) {
+	out = &pb.DeleteVASPReply{}
+
+	grace := s.conf.DelistGrace
+	if in.GracePeriod != "" {
+		if grace, err = time.ParseDuration(in.GracePeriod); err != nil {
+			log.Warn().Err(err).Uint64("id", in.Id).Msg("could not parse delisting grace period")
+			err = fmt.Errorf("%s: %w", err, ErrInvalidGracePeriod)
+			return out, s.fail(&out.Error, err, badRequest("gracePeriod", "specify a duration such as 720h"))
+		}
+	}
+
+	if _, err = s.Delist(in.Id, in.Reason, grace, actor(ctx)); err != nil {
+		log.Warn().Err(err).Uint64("id", in.Id).Msg("could not delete VASP")
+		if errors.Is(err, ErrInvalidGracePeriod) {
+			return out, s.fail(&out.Error, err, badRequest("gracePeriod", err.Error()))
+		}
+		return out, s.fail(&out.Error, err, vaspResource(in.Id, ""))
+	}
+	return out, nil
+}
+
+// History returns the audit history of a VASP: an entry for every change that was made
+// to the record, including its deletion, with the actor that made it and the changed
+// fields. Regulators may ask when the details or certificate of a counterparty changed.
+func (s *Server) History(ctx context.Context, in *pb.HistoryRequest) (out *pb.HistoryReply, err error) {
+	out = &pb.HistoryReply{}
+
+	var entries []pb.AuditEntry
+	if entries, err = s.db.History(in.Id); err != nil {
+		log.Error().Err(err).Uint64("id", in.Id).Msg("could not retrieve VASP history")
+		return out, s.fail(&out.Error, err)
+	}
+
+	// Deleted VASPs retain their history, but VASPs that were stored before the history
+	// was recorded have none, so only VASPs that have never been stored are not found.
+	if len(entries) == 0 {
+		if _, err = s.db.Retrieve(in.Id); err != nil {
+			return out, s.fail(&out.Error, err, vaspResource(in.Id, ""))
+		}
+	}
+
+	out.Entries = make([]*pb.AuditEntry, len(entries))
+	for i := range entries {
+		out.Entries[i] = &entries[i]
+	}
+	return out, nil
+}
+
+// ResendEmail queues the verification email to the VASP contact again, e.g. if the
+// original email was lost or could not be delivered after registration.
+func (s *Server) ResendEmail(ctx context.Context, in *pb.ResendEmailRequest) (out *pb.ResendEmailReply, err error) {
+	out = &pb.ResendEmailReply{}
+
+	var vasp pb.VASP
+	if vasp, err = s.db.Retrieve(in.Id); err != nil {
+		log.Warn().Err(err).Uint64("id", in.Id).Msg("could not retrieve VASP to resend email")
+		return out, s.fail(&out.Error, err, vaspResource(in.Id, ""))
+	}
+
+	// Only VASPs that have not yet verified their email have a verification token
+	if vasp.VerificationToken == "" || !vasp.VerificationStatus.CanTransition(pb.VerificationState_EMAILED) {
+		err = status.Errorf(codes.FailedPrecondition, "cannot resend verification email for VASP in %s state", vasp.VerificationStatus)
+		return out, s.fail(&out.Error, err)
+	}
+
+	// The VASP is moved into the emailed state when the email is delivered
+	if err = s.Notify(pb.Email{Vasp: vasp.Id, Type: pb.EmailType_VERIFY_CONTACT}); err != nil {
+		log.Error().Err(err).Uint64("id", in.Id).Msg("could not queue verification email")
+		return out, s.fail(&out.Error, err)
+	}
+	log.Info().Uint64("id", in.Id).Msg("verification email requeued")
+	return out, nil
+}
@@ -0,0 +1,123 @@
+package trisads
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/bbengfort/trisads/store"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUpdateVASPVersion(t *testing.T) {
+	s := testServer(t)
+	ctx := context.Background()
+
+	id, err := s.db.Create(pb.VASP{VaspEntity: &pb.Entity{VaspFullLegalName: "Versioned Exchange", VaspURL: "https://versioned.io"}}, testActor)
+	require.NoError(t, err)
+
+	vasp, err := s.db.Retrieve(id)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), vasp.Version)
+
+	// The version of the VASP that was edited is required
+	edit := vasp
+	edit.Version = 0
+	_, err = s.UpdateVASP(ctx, &pb.UpdateVASPRequest{Vasp: &edit})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	rep, err := s.UpdateVASP(ctx, &pb.UpdateVASPRequest{Vasp: &vasp})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), rep.Vasp.Version)
+
+	// Concurrent edits of the same version are aborted rather than overwritten
+	_, err = s.UpdateVASP(ctx, &pb.UpdateVASPRequest{Vasp: &vasp})
+	require.Equal(t, codes.Aborted, status.Code(err))
+}
+
+func TestUpdateVASPMaskLegacy(t *testing.T) {
+	s := testServer(t)
+	ctx := context.Background()
+
+	// A record stored before the country was validated
+	id, err := s.db.Create(pb.VASP{VaspEntity: &pb.Entity{VaspFullLegalName: "Legacy Exchange", VaspCountry: "Narnia"}}, testActor)
+	require.NoError(t, err)
+
+	vasp, err := s.db.Retrieve(id)
+	require.NoError(t, err)
+
+	// Only the masked fields are validated
+	patch := &pb.VASP{Id: id, Version: vasp.Version, VaspEntity: &pb.Entity{VaspURL: "https://legacy.io"}}
+	rep, err := s.UpdateVASP(ctx, &pb.UpdateVASPRequest{Vasp: patch, UpdateMask: &field_mask.FieldMask{Paths: []string{"vaspEntity.vaspURL"}}})
+	require.NoError(t, err)
+	require.Equal(t, "https://legacy.io", rep.Vasp.VaspEntity.VaspURL)
+	require.Equal(t, "Narnia", rep.Vasp.VaspEntity.VaspCountry)
+
+	patch.Version = rep.Vasp.Version
+	patch.VaspEntity.VaspURL = "not a url"
+	_, err = s.UpdateVASP(ctx, &pb.UpdateVASPRequest{Vasp: patch, UpdateMask: &field_mask.FieldMask{Paths: []string{"vaspEntity.vaspURL"}}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Identity fields cannot be masked
+	_, err = s.UpdateVASP(ctx, &pb.UpdateVASPRequest{Vasp: patch, UpdateMask: &field_mask.FieldMask{Paths: []string{"vaspEntity.id"}}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestUpdateVASPCertification(t *testing.T) {
+	s := testServer(t)
+	ctx := context.Background()
+
+	id, err := s.db.Create(pb.VASP{
+		VaspEntity:             &pb.Entity{VaspFullLegalName: "Revoked Exchange", VaspURL: "https://revoked.io"},
+		VaspTRISACertification: &pb.TRISACertification{SerialNumber: []byte{0x1a, 0x2b}, Revoked: true},
+	}, testActor)
+	require.NoError(t, err)
+
+	vasp, err := s.db.Retrieve(id)
+	require.NoError(t, err)
+
+	// A full replacement cannot un-revoke the certificate or swap its serial number
+	edit := vasp
+	edit.VaspEntity = &pb.Entity{VaspFullLegalName: "Revoked Exchange", VaspURL: "https://unrevoked.io"}
+	edit.VaspTRISACertification = &pb.TRISACertification{SerialNumber: []byte{0x3c, 0x4d}, Revoked: false}
+	rep, err := s.UpdateVASP(ctx, &pb.UpdateVASPRequest{Vasp: &edit})
+	require.NoError(t, err)
+	require.Equal(t, "https://unrevoked.io", rep.Vasp.VaspEntity.VaspURL)
+
+	vasp, err = s.db.Retrieve(id)
+	require.NoError(t, err)
+	require.True(t, vasp.VaspTRISACertification.Revoked)
+	require.Equal(t, []byte{0x1a, 0x2b}, vasp.VaspTRISACertification.SerialNumber)
+}
+
+func TestReviewRedactsSecrets(t *testing.T) {
+	s := testServer(t)
+	ctx := context.Background()
+
+	// A VASP that has not verified its email cannot be approved
+	id, err := s.db.Create(pb.VASP{
+		VaspEntity:             &pb.Entity{VaspFullLegalName: "Unverified Exchange", VaspURL: "https://unverified.io"},
+		VaspTRISACertification: &pb.TRISACertification{SerialNumber: []byte{0x1a, 0x2b}},
+		VerificationStatus:     pb.VerificationState_SUBMITTED,
+		VerificationToken:      "secret-token",
+		Pkcs12Password:         "secret-password",
+	}, testActor)
+	require.NoError(t, err)
+
+	rep, err := s.Review(ctx, &pb.ReviewRequest{Id: id})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.Equal(t, id, rep.Vasp.Id)
+	require.Empty(t, rep.Vasp.VerificationToken)
+	require.Empty(t, rep.Vasp.Pkcs12Password)
+
+	// Nor can its certificate be revoked
+	vasp, err := s.RevokeCertificate(id, "key compromise", testActor)
+	require.True(t, errors.Is(err, store.ErrInvalidTransition))
+	require.Equal(t, id, vasp.Id)
+	require.Empty(t, vasp.VerificationToken)
+	require.Empty(t, vasp.Pkcs12Password)
+}
@@ -0,0 +1,45 @@
+package trisads
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bbengfort/trisads/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Actors of the changes made to VASP records by the background processes of the server,
+// which are recorded in the audit history of the VASPs.
+var (
+	certManagerActor  = store.Actor{Name: "certman", RPC: "CertManager"}
+	emailManagerActor = store.Actor{Name: "outbox", RPC: "EmailManager"}
+	compactorActor    = store.Actor{Name: "compactor", RPC: "Compactor"}
+)
+
+// actor returns the identity of the client of the RPC for the audit history of the VASP
+// records it changes. Requests to the admin service are made by "admin" (since they are
+// authenticated by the admin token), clients that present a verified certificate are
+// identified by its common name, and all other clients are "anonymous".
+func actor(ctx context.Context) (a store.Actor) {
+	a.Name = "anonymous"
+	a.RPC, _ = grpc.Method(ctx)
+
+	if p, ok := peer.FromContext(ctx); ok {
+		if p.Addr != nil {
+			a.Address = p.Addr.String()
+		}
+
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			if chains := info.State.VerifiedChains; len(chains) > 0 && len(chains[0]) > 0 {
+				a.Name = chains[0][0].Subject.CommonName
+			}
+		}
+	}
+
+	if strings.HasPrefix(a.RPC, adminServicePrefix) {
+		a.Name = "admin"
+	}
+	return a
+}
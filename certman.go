@@ -0,0 +1,257 @@
+package trisads
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/bbengfort/trisads/pkcs"
+	"github.com/bbengfort/trisads/sectigo"
+	"github.com/bbengfort/trisads/store"
+	"github.com/rs/zerolog/log"
+)
+
+// Limits on retrying certificate requests that fail with transient errors.
+const (
+	maxAttempts = 10
+	maxBackoff  = time.Hour
+)
+
+// CertManager runs in its own go routine, periodically processing the queue of
+// certificate requests until the stop channel is closed. Each request is advanced one
+// step at a time: submitting the batch to Sectigo, checking the batch processing status,
+// and finally downloading the certificate and storing it on the VASP record. Because
+// the state of each request is persisted, the queue is resumed when the server restarts.
+func (s *Server) CertManager(stop <-chan struct{}) {
+	ticker := time.NewTicker(s.conf.CertPollEvery)
+	defer ticker.Stop()
+	log.Info().Dur("interval", s.conf.CertPollEvery).Msg("cert manager started")
+
+	for {
+		s.processCertReqs(stop)
+
+		select {
+		case <-stop:
+			log.Info().Msg("cert manager stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// process all of the certificate requests in the queue that are ready for an attempt.
+func (s *Server) processCertReqs(stop <-chan struct{}) {
+	reqs, err := s.db.ListCertReqs()
+	if err != nil {
+		log.Error().Err(err).Msg("could not list certificate requests")
+		return
+	}
+
+	now := time.Now()
+	for _, req := range reqs {
+		// Do not start processing another request if the server is shutting down
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		// Skip requests that are waiting to be retried
+		if req.NextAttempt != "" {
+			if next, err := time.Parse(time.RFC3339, req.NextAttempt); err == nil && now.Before(next) {
+				continue
+			}
+		}
+
+		if err = s.processCertReq(req); err != nil {
+			s.handleCertReqError(req, err)
+		}
+	}
+}
+
+// advances the certificate request by a single step.
+func (s *Server) processCertReq(req pb.CertificateRequest) (err error) {
+	// Abandon requests that have not completed within the issuance timeout
+	if created, err := time.Parse(time.RFC3339, req.Created); err == nil && time.Since(created) > s.conf.CertTimeout {
+		return ErrBatchTimeout
+	}
+
+	var vasp pb.VASP
+	if vasp, err = s.db.Retrieve(req.Vasp); err != nil {
+		return err
+	}
+
+	// The VASP may have been moved out of the issuing state since the request was made
+	if vasp.VerificationStatus != pb.VerificationState_ISSUING_CERTIFICATE {
+		log.Warn().Uint64("id", vasp.Id).Str("status", vasp.VerificationStatus.String()).Msg("dropping certificate request for VASP that is not issuing a certificate")
+		return s.db.DeleteCertReq(req.Id)
+	}
+
+	// Submit the batch to Sectigo if it hasn't been already
+	if req.BatchId == 0 {
+		if vasp.Pkcs12Password == "" {
+			return ErrNoPKCS12Password
+		}
+
+		params := map[string]string{
+			"commonName":     req.CommonName,
+			"pkcs12Password": vasp.Pkcs12Password,
+		}
+
+		var batch *sectigo.BatchResponse
+		req.BatchName = fmt.Sprintf("TRISA certificate for %s (VASP %d)", req.CommonName, vasp.Id)
+		if batch, err = s.certs.CreateSingleCertBatch(s.conf.SectigoProfile, req.BatchName, params); err != nil {
+			return err
+		}
+		log.Info().Uint64("id", vasp.Id).Int("batch", batch.BatchID).Msg("certificate batch created")
+
+		req.BatchId = int64(batch.BatchID)
+		req.Attempts = 0
+		req.NextAttempt = ""
+		req.LastError = ""
+		return s.db.UpdateCertReq(req)
+	}
+
+	// Check if the batch has been processed and is ready to download
+	var ready bool
+	if ready, err = s.batchReady(int(req.BatchId)); err != nil || !ready {
+		return err
+	}
+
+	return s.completeCertReq(req, vasp)
+}
+
+// checks the Sectigo processing info and batch detail to determine if the batch has
+// been processed and is ready for download, returning an error if the batch failed.
+func (s *Server) batchReady(id int) (_ bool, err error) {
+	var info *sectigo.ProcessingInfoResponse
+	if info, err = s.certs.ProcessingInfo(id); err != nil {
+		return false, err
+	}
+
+	if info.Failed > 0 {
+		return false, ErrBatchFailed
+	}
+
+	if info.Active > 0 || info.Success == 0 {
+		log.Debug().Int("batch", id).Int("active", info.Active).Msg("waiting for certificate batch")
+		return false, nil
+	}
+
+	var batch *sectigo.BatchResponse
+	if batch, err = s.certs.BatchDetail(id); err != nil {
+		return false, err
+	}
+
+	if batch.RejectReason != "" {
+		return false, fmt.Errorf("%s: %s", ErrBatchFailed, batch.RejectReason)
+	}
+	return batch.Downloadable, nil
+}
+
+// downloads the certificate batch and stores the certificate on the VASP, marking it as
+// verified, then removes the request from the queue and sends the certificates.
+func (s *Server) completeCertReq(req pb.CertificateRequest, vasp pb.VASP) (err error) {
+	// Download the batch into a temporary directory that is cleaned up after delivery
+	var dir, path string
+	if dir, err = ioutil.TempDir("", "trisads"); err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if path, err = s.certs.Download(int(req.BatchId), dir); err != nil {
+		return err
+	}
+
+	var bundle *pkcs.Bundle
+	if bundle, err = pkcs.Open(path, vasp.Pkcs12Password); err != nil {
+		return err
+	}
+	vasp.VaspTRISACertification = bundle.Certification()
+
+	vasp.VerificationStatus = pb.VerificationState_VERIFIED
+	vasp.VerifiedOn = time.Now().Format(time.RFC3339)
+	vasp.Pkcs12Password = ""
+	if err = s.db.Update(vasp, certManagerActor); err != nil {
+		return err
+	}
+	log.Info().Uint64("id", vasp.Id).Int64("batch", req.BatchId).Msg("VASP verified and certificate issued")
+
+	if err = s.db.DeleteCertReq(req.Id); err != nil {
+		log.Error().Err(err).Uint64("request", req.Id).Msg("could not delete completed certificate request")
+	}
+
+	// The VASP remains verified even if the certificates could not be delivered
+	email := pb.Email{Vasp: vasp.Id, Type: pb.EmailType_CERTIFICATES, AttachmentName: filepath.Base(path)}
+	if email.Attachment, err = ioutil.ReadFile(path); err == nil {
+		err = s.Notify(email)
+	}
+
+	if err != nil {
+		log.Error().Err(err).Uint64("id", vasp.Id).Msg("could not queue certificates email")
+	}
+	return nil
+}
+
+// schedules a retry of the certificate request with exponential backoff if the error is
+// transient, otherwise the request is abandoned and the VASP is moved to the errored state.
+func (s *Server) handleCertReqError(req pb.CertificateRequest, err error) {
+	req.Attempts++
+	req.LastError = err.Error()
+
+	if retryable(err) && req.Attempts < maxAttempts {
+		delay := backoff(s.conf.CertPollEvery, req.Attempts)
+		req.NextAttempt = time.Now().Add(delay).Format(time.RFC3339)
+		log.Warn().Err(err).Uint64("request", req.Id).Int32("attempts", req.Attempts).Dur("backoff", delay).Msg("certificate request failed, will retry")
+		if err = s.db.UpdateCertReq(req); err != nil {
+			log.Error().Err(err).Uint64("request", req.Id).Msg("could not update certificate request")
+		}
+		return
+	}
+
+	if retryable(err) {
+		err = ErrTooManyAttempts
+	}
+	log.Error().Err(err).Uint64("request", req.Id).Uint64("id", req.Vasp).Msg("could not issue certificate")
+
+	if vasp, verr := s.db.Retrieve(req.Vasp); verr == nil && vasp.VerificationStatus == pb.VerificationState_ISSUING_CERTIFICATE {
+		vasp.VerificationStatus = pb.VerificationState_ERRORED
+		if uerr := s.db.Update(vasp, certManagerActor); uerr != nil {
+			log.Error().Err(uerr).Uint64("id", vasp.Id).Msg("could not update VASP verification state")
+		}
+	}
+
+	if err = s.db.DeleteCertReq(req.Id); err != nil {
+		log.Error().Err(err).Uint64("request", req.Id).Msg("could not delete failed certificate request")
+	}
+}
+
+// returns the exponential backoff delay after the specified number of failed attempts,
+// doubling the interval with each attempt up to the maximum backoff.
+func backoff(interval time.Duration, attempts int32) time.Duration {
+	delay := interval * time.Duration(1<<uint(attempts-1))
+	if delay > maxBackoff || delay <= 0 {
+		return maxBackoff
+	}
+	return delay
+}
+
+// returns true if the error is a Sectigo API or network error or the VASP was modified
+// by a concurrent update, which may succeed on retry.
+func retryable(err error) bool {
+	if errors.Is(err, store.ErrConflict) {
+		return true
+	}
+
+	switch err.(type) {
+	case *sectigo.APIError, net.Error:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,329 @@
+package trisads
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/bbengfort/trisads/sectigo"
+	"github.com/bbengfort/trisads/store"
+	"github.com/rs/zerolog/log"
+)
+
+// Length of the generated password used to encrypt the PKCS12 certificates.
+const pkcs12PasswordLength = 16
+
+// Errors that may occur during certificate issuance, verification and revocation.
+var (
+	ErrNoCommonName     = errors.New("could not determine certificate common name from vasp url")
+	ErrNoPKCS12Password = errors.New("vasp record has no pkcs12 password for certificate issuance")
+	ErrBatchFailed      = errors.New("sectigo certificate batch failed")
+	ErrBatchTimeout     = errors.New("timed out waiting for sectigo certificate batch")
+	ErrTooManyAttempts  = errors.New("too many failed attempts to issue certificate")
+	ErrNoCertificate    = errors.New("vasp has not been issued a certificate")
+	ErrNoPEMCertificate = errors.New("no certificate found in pem data")
+	ErrInvalidReason    = errors.New("invalid certificate revocation reason")
+)
+
+// Pending returns the VASPs that have verified their contact email address and are
+// waiting for review by the TRISA admins.
+func (s *Server) Pending() (vasps []pb.VASP, err error) {
+	var all []pb.VASP
+	if all, err = s.db.List(); err != nil {
+		return nil, err
+	}
+
+	vasps = make([]pb.VASP, 0, len(all))
+	for _, vasp := range all {
+		if vasp.VerificationStatus == pb.VerificationState_PENDING_REVIEW {
+			redact(&vasp)
+			vasps = append(vasps, vasp)
+		}
+	}
+	return vasps, nil
+}
+
+// Approve marks a VASP that is pending review as reviewed, then queues the issuance of
+// its TRISA certificates, which is performed in the background by the CertManager. The
+// changes are recorded in the audit history of the VASP as made by the actor. The secrets
+// of the VASP are removed from the returned record, even if it could not be approved.
+func (s *Server) Approve(id uint64, a store.Actor) (vasp pb.VASP, err error) {
+	defer redact(&vasp)
+	if vasp, err = s.db.Retrieve(id); err != nil {
+		return vasp, err
+	}
+
+	if !vasp.VerificationStatus.CanTransition(pb.VerificationState_REVIEWED) {
+		return vasp, fmt.Errorf("cannot approve VASP in %s state: %w", vasp.VerificationStatus, store.ErrInvalidTransition)
+	}
+
+	vasp.VerificationStatus = pb.VerificationState_REVIEWED
+	vasp.VerificationToken = ""
+	if err = s.db.Update(vasp, a); err != nil {
+		return vasp, err
+	}
+	log.Info().Uint64("id", id).Msg("VASP registration approved")
+
+	// Return the latest version of the record to reflect the issuance state
+	ierr := s.IssueCertificate(id, a)
+	if vasp, err = s.db.Retrieve(id); err != nil {
+		return vasp, err
+	}
+	return vasp, ierr
+}
+
+// IssueCertificate moves a reviewed VASP into the issuing certificate state and adds a
+// certificate request to the queue that is processed by the CertManager.
+func (s *Server) IssueCertificate(id uint64, a store.Actor) (err error) {
+	var vasp pb.VASP
+	if vasp, err = s.db.Retrieve(id); err != nil {
+		return err
+	}
+
+	if !vasp.VerificationStatus.CanTransition(pb.VerificationState_ISSUING_CERTIFICATE) {
+		return fmt.Errorf("cannot issue certificate for VASP in %s state: %w", vasp.VerificationStatus, store.ErrInvalidTransition)
+	}
+
+	// Check the request can be made before queuing it
+	req := pb.CertificateRequest{Vasp: id}
+	if req.CommonName, err = certCommonName(vasp); err != nil {
+		return err
+	}
+
+	if vasp.Pkcs12Password == "" {
+		return ErrNoPKCS12Password
+	}
+
+	vasp.VerificationStatus = pb.VerificationState_ISSUING_CERTIFICATE
+	if err = s.db.Update(vasp, a); err != nil {
+		return err
+	}
+
+	if req.Id, err = s.db.CreateCertReq(req); err != nil {
+		log.Error().Err(err).Uint64("id", id).Msg("could not queue certificate request")
+		vasp.VerificationStatus = pb.VerificationState_ERRORED
+		if uerr := s.db.Update(vasp, a); uerr != nil {
+			log.Error().Err(uerr).Uint64("id", id).Msg("could not update VASP verification state")
+		}
+		return err
+	}
+
+	log.Info().Uint64("id", id).Uint64("request", req.Id).Msg("certificate request queued")
+	return nil
+}
+
+// determines the common name of the certificate from the domain of the VASP URL.
+func certCommonName(vasp pb.VASP) (_ string, err error) {
+	if vasp.VaspEntity == nil || vasp.VaspEntity.VaspURL == "" {
+		return "", ErrNoCommonName
+	}
+
+	// Handle URLs that are specified without a scheme, e.g. example.com/about
+	rawurl := vasp.VaspEntity.VaspURL
+	if !strings.Contains(rawurl, "://") {
+		rawurl = "https://" + rawurl
+	}
+
+	var u *url.URL
+	if u, err = url.Parse(rawurl); err != nil || u.Hostname() == "" {
+		return "", ErrNoCommonName
+	}
+	return u.Hostname(), nil
+}
+
+// Number of attempts to record a revocation after the certificate has been revoked with
+// Sectigo, and the delay between them.
+const (
+	revokeAttempts = 5
+	revokeBackoff  = 200 * time.Millisecond
+)
+
+// RevokeCertificate revokes the TRISA certificate of a verified VASP with Sectigo using
+// an RFC 5280 reason, e.g. "key compromise", then marks the certificate and the VASP as
+// revoked so that counterparties verifying the certificate will reject it. The VASP is
+// moved into the revoking state before the certificate is revoked with Sectigo, so that
+// if the revocation cannot be recorded the VASP is not left verified, and retrying the
+// revocation of a VASP in the revoking state does not revoke the certificate twice. If
+// Sectigo cannot revoke the certificate, the VASP remains in the revoking state until the
+// revocation is retried. As with Approve, the secrets of the returned VASP are removed.
+func (s *Server) RevokeCertificate(id uint64, reason string, a store.Actor) (vasp pb.VASP, err error) {
+	defer redact(&vasp)
+	if vasp, err = s.db.Retrieve(id); err != nil {
+		return vasp, err
+	}
+
+	cert := vasp.VaspTRISACertification
+	if cert == nil || len(cert.SerialNumber) == 0 {
+		return vasp, ErrNoCertificate
+	}
+
+	retry := vasp.VerificationStatus == pb.VerificationState_REVOKING
+	if !retry && !vasp.VerificationStatus.CanTransition(pb.VerificationState_REVOKING) {
+		return vasp, fmt.Errorf("cannot revoke certificate of VASP in %s state: %w", vasp.VerificationStatus, store.ErrInvalidTransition)
+	}
+
+	var code sectigo.CRLReason
+	if code, err = sectigo.RevokeReasonCode(reason); err != nil {
+		return vasp, fmt.Errorf("%s: %w", err, ErrInvalidReason)
+	}
+
+	if !retry {
+		vasp.VerificationStatus = pb.VerificationState_REVOKING
+		if err = s.db.Update(vasp, a); err != nil {
+			return vasp, err
+		}
+	}
+
+	serial := strings.ToUpper(hex.EncodeToString(cert.SerialNumber))
+	if !retry || !s.revokedBySectigo(cert, serial) {
+		if err = s.certs.RevokeCertificate(s.conf.SectigoProfile, int(code), serial); err != nil {
+			return vasp, err
+		}
+	}
+
+	if vasp, err = s.revoked(id, a); err != nil {
+		log.Error().Err(err).Uint64("id", id).Str("serial", serial).Msg("certificate revoked by Sectigo but the VASP could not be updated, retry the revocation")
+		return vasp, err
+	}
+	log.Info().Uint64("id", id).Str("serial", serial).Str("reason", reason).Msg("VASP certificate revoked")
+	return vasp, nil
+}
+
+// records that the certificate of a VASP in the revoking state has been revoked, retrying
+// the update if it fails, e.g. because the VASP was modified concurrently.
+func (s *Server) revoked(id uint64, a store.Actor) (vasp pb.VASP, err error) {
+	for attempt := 1; attempt <= revokeAttempts; attempt++ {
+		if attempt > 1 {
+			log.Warn().Err(err).Uint64("id", id).Int("attempt", attempt).Msg("retrying VASP revocation update")
+			time.Sleep(time.Duration(attempt-1) * revokeBackoff)
+		}
+
+		if vasp, err = s.db.Retrieve(id); err != nil {
+			continue
+		}
+
+		// The VASP may have been delisted after it was moved into the revoking state
+		if vasp.VerificationStatus != pb.VerificationState_REVOKING {
+			return vasp, nil
+		}
+
+		if vasp.VaspTRISACertification != nil {
+			vasp.VaspTRISACertification.Revoked = true
+		}
+		vasp.VerificationStatus = pb.VerificationState_REVOKED
+		if err = s.db.Update(vasp, a); err == nil {
+			return vasp, nil
+		}
+	}
+	return vasp, err
+}
+
+// returns true if Sectigo reports that the certificate has already been revoked, e.g.
+// when a revocation is retried after the revocation could not be recorded.
+func (s *Server) revokedBySectigo(cert *pb.TRISACertification, serial string) bool {
+	var name string
+	if cert.SubjectName != nil {
+		name = cert.SubjectName.CommonName
+	}
+
+	certs, err := s.certs.FindCertificate(name, serial)
+	if err != nil {
+		log.Warn().Err(err).Str("serial", serial).Msg("could not find certificate with Sectigo")
+		return false
+	}
+
+	for _, item := range certs.Items {
+		if strings.EqualFold(item.SerialNumber, serial) && strings.EqualFold(item.Status, "revoked") {
+			return true
+		}
+	}
+	return false
+}
+
+// parses a PEM or DER encoded certificate. The first certificate in PEM data is the leaf
+// certificate and any other certificates are returned as intermediates of its chain.
+func parseCertificate(data []byte) (cert *x509.Certificate, intermediates *x509.CertPool, err error) {
+	intermediates = x509.NewCertPool()
+	if !bytes.Contains(data, []byte("-----BEGIN")) {
+		if cert, err = x509.ParseCertificate(data); err != nil {
+			return nil, nil, err
+		}
+		return cert, intermediates, nil
+	}
+
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		var c *x509.Certificate
+		if c, err = x509.ParseCertificate(block.Bytes); err != nil {
+			return nil, nil, err
+		}
+
+		if cert == nil {
+			cert = c
+		} else {
+			intermediates.AddCert(c)
+		}
+	}
+
+	if cert == nil {
+		return nil, nil, ErrNoPEMCertificate
+	}
+	return cert, intermediates, nil
+}
+
+// returns the reason the certificate issued to the VASP is not valid at the specified
+// time, or an empty string if it is valid. If the certificate was presented by a client
+// it must match the issued certificate and be trusted by the directory service.
+func (s *Server) verifyCertificate(vasp *pb.VASP, issued *pb.TRISACertification, cert *x509.Certificate, intermediates *x509.CertPool, now time.Time) string {
+	if vasp.VerificationStatus == pb.VerificationState_DELISTED {
+		return "vasp has been delisted"
+	}
+
+	if issued.Revoked || vasp.VerificationStatus == pb.VerificationState_REVOKING {
+		return "certificate has been revoked"
+	}
+
+	if vasp.VerificationStatus != pb.VerificationState_VERIFIED {
+		return fmt.Sprintf("vasp is not verified (%s)", vasp.VerificationStatus)
+	}
+
+	if notBefore, err := time.Parse(time.RFC3339, issued.NotValidBefore); err != nil || now.Before(notBefore) {
+		return "certificate is not yet valid"
+	}
+
+	if notAfter, err := time.Parse(time.RFC3339, issued.NotValidAfter); err != nil || now.After(notAfter) {
+		return "certificate has expired"
+	}
+
+	if cert == nil {
+		return ""
+	}
+
+	if issued.PublicKeyInfo == nil || !bytes.Equal(issued.PublicKeyInfo.PublicKey, cert.RawSubjectPublicKeyInfo) {
+		return "public key does not match the issued certificate"
+	}
+
+	// Chain verification is skipped if the issuing authorities are not configured
+	if s.trust != nil {
+		opts := x509.VerifyOptions{
+			Roots:         s.trust,
+			Intermediates: intermediates,
+			CurrentTime:   now,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		}
+		if _, err := cert.Verify(opts); err != nil {
+			return fmt.Sprintf("certificate is not trusted: %s", err)
+		}
+	}
+	return ""
+}
@@ -3,11 +3,10 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"math/rand"
 	"os"
-	"time"
 
 	"github.com/bbengfort/trisads"
+	"github.com/bbengfort/trisads/pkcs"
 	"github.com/bbengfort/trisads/sectigo"
 	"github.com/urfave/cli"
 )
@@ -103,6 +102,14 @@ func main() {
 					Name:  "o, outdir",
 					Usage: "the directory to download the zip file to",
 				},
+				cli.BoolFlag{
+					Name:  "P, parse",
+					Usage: "decrypt the downloaded certificate and print its TRISA certification record",
+				},
+				cli.StringFlag{
+					Name:  "p, password",
+					Usage: "the pkcs12 password the batch was created with (required to parse)",
+				},
 			},
 		},
 		{
@@ -241,7 +248,7 @@ func createSingle(c *cli.Context) (err error) {
 	params["pkcs12Password"] = c.String("password")
 
 	if params["pkcs12Password"] == "" {
-		params["pkcs12Password"] = randomPassword(10)
+		params["pkcs12Password"] = sectigo.RandomPassword(10)
 		fmt.Printf("pkcs12 password: %s\n", params["pkcs12Password"])
 	}
 
@@ -292,12 +299,27 @@ func download(c *cli.Context) (err error) {
 		return cli.NewExitError("must specify batch id for download", 1)
 	}
 
+	password := c.String("password")
+	if c.Bool("parse") && password == "" {
+		return cli.NewExitError("must specify pkcs12 password to parse the certificate", 1)
+	}
+
 	var path string
 	if path, err = api.Download(batch, outdir); err != nil {
 		return cli.NewExitError(err, 1)
 	}
 
 	fmt.Printf("downloaded batch %d to %s\n", batch, path)
+	if c.Bool("parse") {
+		var bundle *pkcs.Bundle
+		if bundle, err = pkcs.Open(path, password); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+
+		printJSON(bundle.Certification())
+		return nil
+	}
+
 	fmt.Println("after unzipping, unencrypt with your password using `openssl pkcs12 -in INFILE.p12 -out OUTFILE.crt -nodes`")
 	return nil
 }
@@ -396,14 +418,3 @@ func printJSON(data interface{}) (err error) {
 	}
 	return nil
 }
-
-const pwcharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789#$%&*-<>~"
-
-func randomPassword(length int) string {
-	seededRand := rand.New(rand.NewSource(time.Now().UnixNano()))
-	buf := make([]byte, length)
-	for i := range buf {
-		buf[i] = pwcharset[seededRand.Intn(len(pwcharset))]
-	}
-	return string(buf)
-}
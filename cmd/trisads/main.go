@@ -3,24 +3,31 @@ package main
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"os/user"
+	"strings"
 	"time"
 
 	"github.com/bbengfort/trisads"
 	"github.com/bbengfort/trisads/pb"
 	"github.com/bbengfort/trisads/store"
 	"github.com/urfave/cli"
+	"google.golang.org/genproto/protobuf/field_mask"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 )
 
 var (
 	client pb.TRISADirectoryClient
+	admin  pb.TRISAAdminClient
 )
 
 func main() {
@@ -40,6 +47,21 @@ func main() {
 			Name:  "S, no-secure",
 			Usage: "do not connect via TLS (e.g. for development)",
 		},
+		cli.StringFlag{
+			Name:   "c, cert",
+			Usage:  "client certificate to authenticate with the directory service (mTLS)",
+			EnvVar: "TRISA_CLIENT_CERT",
+		},
+		cli.StringFlag{
+			Name:   "k, key",
+			Usage:  "private key of the client certificate (mTLS)",
+			EnvVar: "TRISA_CLIENT_KEY",
+		},
+		cli.StringFlag{
+			Name:   "ca, ca-certs",
+			Usage:  "PEM file of certificate authorities to verify the server (default system pool)",
+			EnvVar: "TRISA_CA_CERTS",
+		},
 	}
 	app.Commands = []cli.Command{
 		{
@@ -69,15 +91,39 @@ func main() {
 				},
 			},
 		},
+		{
+			Name:     "db",
+			Usage:    "check and repair the integrity of the directory store",
+			Category: "server",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:   "d, db",
+					Usage:  "dsn to connect to trisa directory storage",
+					EnvVar: "TRISADS_DATABASE",
+				},
+			},
+			Subcommands: []cli.Command{
+				{
+					Name:   "check",
+					Usage:  "report corrupted records, divergent indices and the key sequence",
+					Action: dbCheck,
+				},
+				{
+					Name:   "reindex",
+					Usage:  "rewrite the indices and sequence from the stored records",
+					Action: dbReindex,
+				},
+			},
+		},
 		{
 			Name:     "verify",
-			Usage:    "mark a VASP entity as verified and create certificates",
+			Usage:    "approve a VASP pending review by opening the store while the server is stopped",
 			Category: "admin",
 			Action:   verify,
 			Flags: []cli.Flag{
 				cli.BoolFlag{
 					Name:  "l, list",
-					Usage: "list VASPs that require verification and exit",
+					Usage: "list VASPs that are pending review and exit",
 				},
 				cli.Uint64Flag{
 					Name:  "v, vasp",
@@ -85,6 +131,130 @@ func main() {
 				},
 			},
 		},
+		{
+			Name:     "admin",
+			Usage:    "review and manage VASP registrations using the admin API",
+			Category: "admin",
+			Before:   initAdminClient,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:   "t, token",
+					Usage:  "the admin token to authenticate with the directory service",
+					EnvVar: "TRISADS_ADMIN_TOKEN",
+				},
+			},
+			Subcommands: []cli.Command{
+				{
+					Name:   "pending",
+					Usage:  "list VASPs that are pending review",
+					Action: adminPending,
+				},
+				{
+					Name:   "review",
+					Usage:  "approve a VASP registration and issue certificates",
+					Action: adminReview,
+					Flags: []cli.Flag{
+						cli.Uint64Flag{
+							Name:  "v, vasp",
+							Usage: "the ID of the VASP to approve",
+						},
+					},
+				},
+				{
+					Name:   "reject",
+					Usage:  "reject a VASP registration",
+					Action: adminReject,
+					Flags: []cli.Flag{
+						cli.Uint64Flag{
+							Name:  "v, vasp",
+							Usage: "the ID of the VASP to reject",
+						},
+						cli.StringFlag{
+							Name:  "r, reason",
+							Usage: "the reason for the rejection, sent to the VASP contact",
+						},
+					},
+				},
+				{
+					Name:   "revoke",
+					Usage:  "revoke the TRISA certificate of a VASP",
+					Action: adminRevoke,
+					Flags: []cli.Flag{
+						cli.Uint64Flag{
+							Name:  "v, vasp",
+							Usage: "the ID of the VASP whose certificate to revoke",
+						},
+						cli.StringFlag{
+							Name:  "r, reason",
+							Usage: "the RFC 5280 revocation reason, e.g. \"key compromise\" (unspecified by default)",
+						},
+					},
+				},
+				{
+					Name:   "update",
+					Usage:  "update a VASP record using json data",
+					Action: adminUpdate,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "d, data",
+							Usage: "the json file containing the VASP data record (including id and version, e.g. from lookup)",
+						},
+						cli.StringSliceFlag{
+							Name:  "m, mask",
+							Usage: "only update the fields in the paths, e.g. vaspEntity.vaspURL",
+						},
+					},
+				},
+				{
+					Name:   "delete",
+					Usage:  "delist a VASP, leaving a tombstone that is purged after a grace period",
+					Action: adminDelete,
+					Flags: []cli.Flag{
+						cli.Uint64Flag{
+							Name:  "v, vasp",
+							Usage: "the ID of the VASP to delete",
+						},
+						cli.StringFlag{
+							Name:  "r, reason",
+							Usage: "the reason the VASP is delisted",
+						},
+						cli.StringFlag{
+							Name:  "g, grace",
+							Usage: "purge the VASP after the grace period, e.g. 720h (default the server setting)",
+						},
+					},
+				},
+				{
+					Name:   "resend",
+					Usage:  "resend the verification email to the VASP contact",
+					Action: adminResend,
+					Flags: []cli.Flag{
+						cli.Uint64Flag{
+							Name:  "v, vasp",
+							Usage: "the ID of the VASP to resend the email for",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:     "history",
+			Usage:    "view the audit history of changes to a VASP using the admin API",
+			Category: "admin",
+			Before:   initAdminClient,
+			Action:   history,
+			Flags: []cli.Flag{
+				cli.Uint64Flag{
+					Name:  "i, id",
+					Usage: "the ID of the VASP to view the history of",
+				},
+				cli.StringFlag{
+					Name:   "t, token",
+					Usage:  "the admin token to authenticate with the directory service",
+					EnvVar: "TRISADS_ADMIN_TOKEN",
+				},
+			},
+		},
 		{
 			Name:     "register",
 			Usage:    "register a VASP using json data",
@@ -104,7 +274,7 @@ func main() {
 		},
 		{
 			Name:     "lookup",
-			Usage:    "lookup VASPs using name or ID",
+			Usage:    "lookup VASPs using name, ID, LEI, domain or certificate common name",
 			Category: "client",
 			Action:   lookup,
 			Before:   initClient,
@@ -118,11 +288,40 @@ func main() {
 					Name:  "i, id",
 					Usage: "id of the VASP to lookup",
 				},
+				cli.StringFlag{
+					Name:  "l, lei",
+					Usage: "LEI number of the VASP to lookup",
+				},
+				cli.StringFlag{
+					Name:  "D, domain",
+					Usage: "web domain of the VASP to lookup",
+				},
+				cli.StringFlag{
+					Name:  "cn, common-name",
+					Usage: "common name of the TRISA certificate of the VASP to lookup",
+				},
+			},
+		},
+		{
+			Name:     "verify-cert",
+			Usage:    "verify a TRISA certificate and lookup the VASP it was issued to",
+			Category: "client",
+			Action:   verifyCert,
+			Before:   initClient,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "c, cert",
+					Usage: "path to the PEM or DER encoded certificate (and its chain) to verify",
+				},
+				cli.StringFlag{
+					Name:  "s, serial",
+					Usage: "hex serial number of the certificate to verify",
+				},
 			},
 		},
 		{
 			Name:     "search",
-			Usage:    "search for VASPs using name or country",
+			Usage:    "search for VASPs by name, country and other fields",
 			Category: "client",
 			Action:   search,
 			Before:   initClient,
@@ -135,6 +334,64 @@ func main() {
 					Name:  "c, country",
 					Usage: "one or more countries of the VASPs to search for",
 				},
+				cli.StringSliceFlag{
+					Name:  "C, category",
+					Usage: "one or more categories of the VASPs to search for",
+				},
+				cli.StringSliceFlag{
+					Name:  "l, lei",
+					Usage: "one or more LEI numbers of the VASPs to search for",
+				},
+				cli.StringSliceFlag{
+					Name:  "D, domain",
+					Usage: "one or more web domains of the VASPs to search for",
+				},
+				cli.StringSliceFlag{
+					Name:  "s, status",
+					Usage: "one or more verification states of the VASPs to search for",
+				},
+				cli.StringFlag{
+					Name:  "certificate",
+					Usage: "filter by certificate validity (valid, invalid or none)",
+				},
+				cli.BoolFlag{
+					Name:  "any",
+					Usage: "match VASPs that meet any rather than all of the conditions",
+				},
+				cli.UintFlag{
+					Name:  "P, page-size",
+					Usage: "the maximum number of results to return (100 by default)",
+				},
+				cli.StringFlag{
+					Name:  "t, page-token",
+					Usage: "the next page token returned by a previous search",
+				},
+			},
+		},
+		{
+			Name:     "list",
+			Usage:    "stream every VASP in the directory as JSON lines",
+			Category: "client",
+			Action:   list,
+			Before:   initClient,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "o, order",
+					Usage: "order VASPs by id, name, first-listed or last-updated",
+					Value: "id",
+				},
+				cli.BoolFlag{
+					Name:  "desc",
+					Usage: "list VASPs in descending order",
+				},
+				cli.StringFlag{
+					Name:  "C, cursor",
+					Usage: "resume the listing after the VASP with this cursor",
+				},
+				cli.UintFlag{
+					Name:  "l, limit",
+					Usage: "the maximum number of VASPs to list (all by default)",
+				},
 			},
 		},
 	}
@@ -215,7 +472,7 @@ func load(c *cli.Context) (err error) {
 			}
 
 			var id uint64
-			if id, err = db.Create(vasp); err != nil {
+			if id, err = db.Create(vasp, cliActor("load")); err != nil {
 				return cli.NewExitError(err, 1)
 			}
 
@@ -238,9 +495,231 @@ func load(c *cli.Context) (err error) {
 	return nil
 }
 
-// Verify a registered entity and assign keys (server-side CLI)
+// Check the integrity of the directory store, exiting with an error if it is not ok
+func dbCheck(c *cli.Context) (err error) {
+	return checkStore(c, false)
+}
+
+// Rewrite the indices of the directory store from the stored records
+func dbReindex(c *cli.Context) (err error) {
+	return checkStore(c, true)
+}
+
+// helper function to check or reindex the store specified by the parent db command
+func checkStore(c *cli.Context, reindex bool) (err error) {
+	dsn := c.Parent().String("db")
+	if dsn == "" {
+		return cli.NewExitError("please specify a dsn to connect to the directory store", 1)
+	}
+
+	var report *store.IntegrityReport
+	if report, err = store.CheckLevelDB(dsn, reindex); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	printJSON(report)
+	if !report.OK() {
+		return cli.NewExitError("directory store integrity check failed", 2)
+	}
+	return nil
+}
+
+// Approve a VASP pending review by opening the directory store directly (server-side CLI)
 func verify(c *cli.Context) (err error) {
-	return cli.NewExitError("not implemented", 7)
+	var conf *trisads.Settings
+	if conf, err = trisads.Config(); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	// Only the store is opened, which is locked by the server while it is running
+	var srv *trisads.Server
+	if srv, err = trisads.Open(conf); err != nil {
+		return cli.NewExitError(fmt.Errorf("could not open the directory store, use trisads admin review if the server is running: %s", err), 1)
+	}
+	defer srv.Shutdown()
+
+	// List the VASPs that are waiting for review and exit
+	if c.Bool("list") {
+		var vasps []pb.VASP
+		if vasps, err = srv.Pending(); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		return printJSON(vasps)
+	}
+
+	var id uint64
+	if id = c.Uint64("vasp"); id == 0 {
+		return cli.NewExitError("specify the id of the VASP to verify", 1)
+	}
+
+	// Approve the VASP and queue its certificate request, which the server processes
+	// once it is started
+	vasp, err := srv.Approve(id, cliActor("verify"))
+	if err != nil {
+		if vasp.Id > 0 {
+			printJSON(vasp)
+		}
+		return cli.NewExitError(err, 1)
+	}
+
+	return printJSON(vasp)
+}
+
+// List the VASPs that are pending review using the admin API
+func adminPending(c *cli.Context) (err error) {
+	ctx, cancel := adminContext(c)
+	defer cancel()
+
+	rep, err := admin.ListPending(ctx, &pb.ListPendingRequest{})
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return printJSON(rep)
+}
+
+// Approve a VASP registration using the admin API
+func adminReview(c *cli.Context) (err error) {
+	req := &pb.ReviewRequest{Id: c.Uint64("vasp")}
+	if req.Id == 0 {
+		return cli.NewExitError("specify the id of the VASP to approve", 1)
+	}
+
+	ctx, cancel := adminContext(c)
+	defer cancel()
+
+	rep, err := admin.Review(ctx, req)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return printJSON(rep)
+}
+
+// Reject a VASP registration using the admin API
+func adminReject(c *cli.Context) (err error) {
+	req := &pb.RejectRequest{Id: c.Uint64("vasp"), Reason: c.String("reason")}
+	if req.Id == 0 {
+		return cli.NewExitError("specify the id of the VASP to reject", 1)
+	}
+
+	ctx, cancel := adminContext(c)
+	defer cancel()
+
+	rep, err := admin.Reject(ctx, req)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return printJSON(rep)
+}
+
+// Revoke the certificate of a VASP using the admin API
+func adminRevoke(c *cli.Context) (err error) {
+	req := &pb.RevokeRequest{Id: c.Uint64("vasp"), Reason: c.String("reason")}
+	if req.Id == 0 {
+		return cli.NewExitError("specify the id of the VASP whose certificate to revoke", 1)
+	}
+
+	ctx, cancel := adminContext(c)
+	defer cancel()
+
+	rep, err := admin.Revoke(ctx, req)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return printJSON(rep)
+}
+
+// Update a VASP record from a JSON file using the admin API
+func adminUpdate(c *cli.Context) (err error) {
+	var path string
+	if path = c.String("data"); path == "" {
+		return cli.NewExitError("specify a json file to load the VASP data from", 1)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	req := &pb.UpdateVASPRequest{}
+	if err = json.Unmarshal(data, &req.Vasp); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	if paths := c.StringSlice("mask"); len(paths) > 0 {
+		req.UpdateMask = &field_mask.FieldMask{Paths: paths}
+	}
+
+	ctx, cancel := adminContext(c)
+	defer cancel()
+
+	rep, err := admin.UpdateVASP(ctx, req)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return printJSON(rep)
+}
+
+// Delete a VASP record using the admin API
+func adminDelete(c *cli.Context) (err error) {
+	req := &pb.DeleteVASPRequest{
+		Id:          c.Uint64("vasp"),
+		Reason:      c.String("reason"),
+		GracePeriod: c.String("grace"),
+	}
+	if req.Id == 0 {
+		return cli.NewExitError("specify the id of the VASP to delete", 1)
+	}
+
+	ctx, cancel := adminContext(c)
+	defer cancel()
+
+	rep, err := admin.DeleteVASP(ctx, req)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return printJSON(rep)
+}
+
+// Resend the verification email for a VASP using the admin API
+func adminResend(c *cli.Context) (err error) {
+	req := &pb.ResendEmailRequest{Id: c.Uint64("vasp")}
+	if req.Id == 0 {
+		return cli.NewExitError("specify the id of the VASP to resend the email for", 1)
+	}
+
+	ctx, cancel := adminContext(c)
+	defer cancel()
+
+	rep, err := admin.ResendEmail(ctx, req)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return printJSON(rep)
+}
+
+// View the audit history of a VASP using the admin API
+func history(c *cli.Context) (err error) {
+	req := &pb.HistoryRequest{Id: c.Uint64("id")}
+	if req.Id == 0 {
+		return cli.NewExitError("specify the id of the VASP to view the history of", 1)
+	}
+
+	ctx, cancel := adminContext(c)
+	defer cancel()
+
+	rep, err := admin.History(ctx, req)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return printJSON(rep)
 }
 
 // Register an entity using the API from a CLI client
@@ -276,20 +755,36 @@ func register(c *cli.Context) (err error) {
 
 // Lookup VASPs using the API from a CLI client
 func lookup(c *cli.Context) (err error) {
-	name := c.String("name")
-	id := c.Uint64("id")
+	req := &pb.LookupRequest{}
+	queries := 0
 
-	if name == "" && id == 0 {
-		return cli.NewExitError("specify either name or id for lookup", 1)
+	if id := c.Uint64("id"); id > 0 {
+		req.Query = &pb.LookupRequest_Id{Id: id}
+		queries++
 	}
 
-	if name != "" && id > 0 {
-		return cli.NewExitError("specify either name or id for lookup, not both", 1)
+	if name := c.String("name"); name != "" {
+		req.Query = &pb.LookupRequest_Name{Name: name}
+		queries++
 	}
 
-	req := &pb.LookupRequest{
-		Name: name,
-		Id:   id,
+	if lei := c.String("lei"); lei != "" {
+		req.Query = &pb.LookupRequest_Lei{Lei: lei}
+		queries++
+	}
+
+	if domain := c.String("domain"); domain != "" {
+		req.Query = &pb.LookupRequest_Domain{Domain: domain}
+		queries++
+	}
+
+	if cn := c.String("common-name"); cn != "" {
+		req.Query = &pb.LookupRequest_CommonName{CommonName: cn}
+		queries++
+	}
+
+	if queries != 1 {
+		return cli.NewExitError("specify exactly one of name, id, lei, domain or common name for lookup", 1)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -303,17 +798,81 @@ func lookup(c *cli.Context) (err error) {
 	return printJSON(rep)
 }
 
-// Search for VASPs by name or country using the API from a CLI client
+// Verify a certificate by its serial number or by the certificate itself using the API
+// from a CLI client
+func verifyCert(c *cli.Context) (err error) {
+	req := &pb.VerifyCertificateRequest{}
+	path, serial := c.String("cert"), c.String("serial")
+	switch {
+	case path != "" && serial != "":
+		return cli.NewExitError("specify either a certificate or a serial number to verify", 1)
+	case path != "":
+		var data []byte
+		if data, err = ioutil.ReadFile(path); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+		req.Query = &pb.VerifyCertificateRequest_Certificate{Certificate: data}
+	case serial != "":
+		req.Query = &pb.VerifyCertificateRequest_SerialNumber{SerialNumber: serial}
+	default:
+		return cli.NewExitError("specify a certificate or a serial number to verify", 1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rep, err := client.VerifyCertificate(ctx, req)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	return printJSON(rep)
+}
+
+// Search for VASPs by name, country or other fields using the API from a CLI client
 func search(c *cli.Context) (err error) {
-	req := &pb.SearchRequest{
-		Name:    c.StringSlice("name"),
-		Country: c.StringSlice("country"),
+	query := &pb.Query{
+		Name:     c.StringSlice("name"),
+		Country:  c.StringSlice("country"),
+		Category: c.StringSlice("category"),
+		Lei:      c.StringSlice("lei"),
+		Domain:   c.StringSlice("domain"),
+	}
+
+	if c.Bool("any") {
+		query.Operator = pb.Query_OR
 	}
 
-	if len(req.Name) == 0 && len(req.Country) == 0 {
+	for _, status := range c.StringSlice("status") {
+		state, ok := pb.VerificationState_value[strings.ToUpper(status)]
+		if !ok {
+			return cli.NewExitError(fmt.Errorf("unknown verification status %q", status), 1)
+		}
+		query.VerificationStatus = append(query.VerificationStatus, pb.VerificationState(state))
+	}
+
+	switch cert := strings.ToLower(c.String("certificate")); cert {
+	case "":
+	case "valid":
+		query.Certificate = pb.CertificateValidity_VALID_CERTIFICATE
+	case "invalid":
+		query.Certificate = pb.CertificateValidity_INVALID_CERTIFICATE
+	case "none":
+		query.Certificate = pb.CertificateValidity_NO_CERTIFICATE
+	default:
+		return cli.NewExitError(fmt.Errorf("unknown certificate validity %q", cert), 1)
+	}
+
+	if err = store.CheckQuery(query); err != nil {
 		return cli.NewExitError("specify search query", 1)
 	}
 
+	req := &pb.SearchRequest{
+		Query:     query,
+		PageSize:  uint32(c.Uint("page-size")),
+		PageToken: c.String("page-token"),
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -325,22 +884,134 @@ func search(c *cli.Context) (err error) {
 	return printJSON(rep)
 }
 
+// List every VASP in the directory using the API from a CLI client. Each VASP is printed
+// as a single line of JSON along with its cursor so that an interrupted listing can be
+// resumed with the cursor of the last line.
+func list(c *cli.Context) (err error) {
+	req := &pb.ListRequest{
+		Descending: c.Bool("desc"),
+		Cursor:     c.String("cursor"),
+		Limit:      uint32(c.Uint("limit")),
+	}
+
+	switch order := strings.ToLower(c.String("order")); order {
+	case "id":
+		req.Order = pb.ListOrder_ORDER_BY_ID
+	case "name":
+		req.Order = pb.ListOrder_ORDER_BY_NAME
+	case "first-listed":
+		req.Order = pb.ListOrder_ORDER_BY_FIRST_LISTED
+	case "last-updated":
+		req.Order = pb.ListOrder_ORDER_BY_LAST_UPDATED
+	default:
+		return cli.NewExitError(fmt.Errorf("unknown list order %q", order), 1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.List(ctx, req)
+	if err != nil {
+		return cli.NewExitError(err, 1)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	for {
+		var rep *pb.ListReply
+		if rep, err = stream.Recv(); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return cli.NewExitError(err, 1)
+		}
+
+		if err = encoder.Encode(rep); err != nil {
+			return cli.NewExitError(err, 1)
+		}
+	}
+}
+
+// helper function to create the GRPC admin client, which shares the connection options
+// of the directory client; the admin token is added to each request by adminContext.
+func initAdminClient(c *cli.Context) (err error) {
+	if c.String("token") == "" {
+		return cli.NewExitError("specify the admin token to authenticate with", 1)
+	}
+
+	var cc *grpc.ClientConn
+	if cc, err = dial(c); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	admin = pb.NewTRISAAdminClient(cc)
+	return nil
+}
+
+// helper function to create a request context with the admin bearer token. Subcommands
+// look up the token from the parent admin command's flags.
+func adminContext(c *cli.Context) (context.Context, context.CancelFunc) {
+	token := c.String("token")
+	if c.Parent() != nil && token == "" {
+		token = c.Parent().String("token")
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	return context.WithTimeout(ctx, 10*time.Second)
+}
+
+// helper function to identify the user of a server-side command that changes the
+// directory store directly in the audit history of the changed VASPs
+func cliActor(command string) store.Actor {
+	a := store.Actor{Name: "cli", RPC: "trisads " + command}
+	if u, err := user.Current(); err == nil {
+		a.Name = u.Username
+	}
+	return a
+}
+
 // helper function to create the GRPC client with default options
 func initClient(c *cli.Context) (err error) {
+	var cc *grpc.ClientConn
+	if cc, err = dial(c); err != nil {
+		return cli.NewExitError(err, 1)
+	}
+	client = pb.NewTRISADirectoryClient(cc)
+	return nil
+}
+
+// helper function to connect to the directory service using the global TLS options
+func dial(c *cli.Context) (_ *grpc.ClientConn, err error) {
 	var opts []grpc.DialOption
 	if c.GlobalBool("no-secure") {
 		opts = append(opts, grpc.WithInsecure())
 	} else {
 		config := &tls.Config{}
+
+		// Present a client certificate for mutual TLS authentication
+		if certFile, keyFile := c.GlobalString("cert"), c.GlobalString("key"); certFile != "" || keyFile != "" {
+			var cert tls.Certificate
+			if cert, err = tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+				return nil, err
+			}
+			config.Certificates = []tls.Certificate{cert}
+		}
+
+		// Verify the server with the specified certificate authorities
+		if path := c.GlobalString("ca-certs"); path != "" {
+			var data []byte
+			if data, err = ioutil.ReadFile(path); err != nil {
+				return nil, err
+			}
+
+			config.RootCAs = x509.NewCertPool()
+			if !config.RootCAs.AppendCertsFromPEM(data) {
+				return nil, errors.New("could not parse certificate authorities")
+			}
+		}
+
 		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(config)))
 	}
 
-	var cc *grpc.ClientConn
-	if cc, err = grpc.Dial(c.GlobalString("endpoint"), opts...); err != nil {
-		return cli.NewExitError(err, 1)
-	}
-	client = pb.NewTRISADirectoryClient(cc)
-	return nil
+	return grpc.Dial(c.GlobalString("endpoint"), opts...)
 }
 
 // helper function to print JSON response and exit
@@ -1,8 +1,10 @@
 package trisads
 
 import (
+	"crypto/tls"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 	"github.com/rs/zerolog"
@@ -11,14 +13,30 @@ import (
 // Settings uses envconfig to load required settings from the environment and
 // validate them in preparation for running the TRISA Directory Service.
 type Settings struct {
-	BindAddr        string          `envconfig:"TRISADS_BIND_ADDR" default:":4433"`
-	DatabaseDSN     string          `envconfig:"TRISADS_DATABASE" required:"true"`
-	SectigoUsername string          `envconfig:"SECTIGO_USERNAME" required:"false"`
-	SectigoPassword string          `envconfig:"SECTIGO_PASSWORD" required:"false"`
-	SendGridAPIKey  string          `envconfig:"SENDGRID_API_KEY" required:"false"`
-	ServiceEmail    string          `envconfig:"TRISADS_SERVICE_EMAIL" default:"admin@vaspdirectory.net"`
-	AdminEmail      string          `envconfig:"TRISADS_ADMIN_EMAIL" default:"admin@trisa.io"`
-	LogLevel        LogLevelDecoder `envconfig:"TRISADS_LOG_LEVEL" default:"info"`
+	BindAddr        string            `envconfig:"TRISADS_BIND_ADDR" default:":4433"`
+	DatabaseDSN     string            `envconfig:"TRISADS_DATABASE" required:"true"`
+	SectigoUsername string            `envconfig:"SECTIGO_USERNAME" required:"false"`
+	SectigoPassword string            `envconfig:"SECTIGO_PASSWORD" required:"false"`
+	SectigoProfile  int               `envconfig:"SECTIGO_PROFILE_ID" required:"false"`
+	SendGridAPIKey  string            `envconfig:"SENDGRID_API_KEY" required:"false"`
+	ServiceEmail    string            `envconfig:"TRISADS_SERVICE_EMAIL" default:"admin@vaspdirectory.net"`
+	AdminEmail      string            `envconfig:"TRISADS_ADMIN_EMAIL" default:"admin@trisa.io"`
+	AdminToken      string            `envconfig:"TRISADS_ADMIN_TOKEN" required:"false"`
+	VerifyURL       string            `envconfig:"TRISADS_VERIFY_URL" default:"https://vaspdirectory.net/verify"`
+	CertPollEvery   time.Duration     `envconfig:"TRISADS_CERT_POLL_INTERVAL" default:"30s"`
+	CertTimeout     time.Duration     `envconfig:"TRISADS_CERT_TIMEOUT" default:"24h"`
+	EmailPollEvery  time.Duration     `envconfig:"TRISADS_EMAIL_POLL_INTERVAL" default:"1m"`
+	DelistGrace     time.Duration     `envconfig:"TRISADS_DELIST_GRACE_PERIOD" default:"0"`
+	CompactEvery    time.Duration     `envconfig:"TRISADS_COMPACT_INTERVAL" default:"1h"`
+	CursorSecret    string            `envconfig:"TRISADS_CURSOR_SECRET" required:"false"`
+	CursorTTL       time.Duration     `envconfig:"TRISADS_CURSOR_TTL" default:"24h"`
+	Workers         bool              `envconfig:"TRISADS_WORKERS" default:"true"`
+	TLSCertFile     string            `envconfig:"TRISADS_TLS_CERT" required:"false"`
+	TLSKeyFile      string            `envconfig:"TRISADS_TLS_KEY" required:"false"`
+	TLSClientCAs    string            `envconfig:"TRISADS_TLS_CLIENT_CAS" required:"false"`
+	TLSClientAuth   ClientAuthDecoder `envconfig:"TRISADS_TLS_CLIENT_AUTH" default:"none"`
+	LegacyErrors    bool              `envconfig:"TRISADS_LEGACY_ERRORS" default:"false"`
+	LogLevel        LogLevelDecoder   `envconfig:"TRISADS_LOG_LEVEL" default:"info"`
 }
 
 // Config creates a new settings object, loading environment variables and defaults.
@@ -56,3 +74,25 @@ func (ll *LogLevelDecoder) Decode(value string) error {
 	}
 	return nil
 }
+
+// ClientAuthDecoder deserializes the mutual TLS client authentication policy from a
+// config string: "none" does not request client certificates, "optional" verifies
+// client certificates if they are presented, and "required" rejects clients that do
+// not present a certificate signed by one of the client CAs.
+type ClientAuthDecoder tls.ClientAuthType
+
+// Decode implements envconfig.Decoder
+func (ca *ClientAuthDecoder) Decode(value string) error {
+	value = strings.TrimSpace(strings.ToLower(value))
+	switch value {
+	case "none", "":
+		*ca = ClientAuthDecoder(tls.NoClientCert)
+	case "optional":
+		*ca = ClientAuthDecoder(tls.VerifyClientCertIfGiven)
+	case "required":
+		*ca = ClientAuthDecoder(tls.RequireAndVerifyClientCert)
+	default:
+		return fmt.Errorf("unknown client auth policy %q", value)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package trisads
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/bbengfort/trisads/store"
+	"github.com/rs/zerolog/log"
+)
+
+// Errors that may occur when delisting VASPs.
+var (
+	ErrDelisted           = errors.New("vasp has been delisted")
+	ErrInvalidGracePeriod = errors.New("invalid delisting grace period")
+)
+
+// Delist soft deletes a VASP, leaving a tombstone in the DELISTED state that records when
+// and why it was delisted. The tombstone keeps the unique keys of the VASP reserved and
+// allows Lookup to tell clients that the VASP was delisted rather than never listed. If
+// the grace period is positive, the tombstone is purged by the Compactor once it has
+// elapsed, otherwise it is kept indefinitely.
+func (s *Server) Delist(id uint64, reason string, grace time.Duration, a store.Actor) (vasp pb.VASP, err error) {
+	if grace < 0 {
+		return vasp, fmt.Errorf("grace period cannot be negative: %w", ErrInvalidGracePeriod)
+	}
+
+	if vasp, err = s.db.Retrieve(id); err != nil {
+		return vasp, err
+	}
+
+	if vasp.VerificationStatus == pb.VerificationState_DELISTED {
+		return vasp, ErrDelisted
+	}
+
+	now := time.Now()
+	vasp.VerificationStatus = pb.VerificationState_DELISTED
+	vasp.DelistedOn = now.Format(time.RFC3339)
+	vasp.DelistReason = reason
+	if grace > 0 {
+		vasp.PurgeAfter = now.Add(grace).Format(time.RFC3339)
+	}
+
+	// The verification secrets are removed since the VASP can no longer be verified
+	vasp.VerificationToken = ""
+	vasp.Pkcs12Password = ""
+	if err = s.db.Update(vasp, a); err != nil {
+		return vasp, err
+	}
+
+	log.Info().Uint64("id", id).Str("reason", reason).Str("purge_after", vasp.PurgeAfter).Msg("VASP delisted")
+	return vasp, nil
+}
+
+// Compactor runs in its own go routine, periodically purging the tombstones of delisted
+// VASPs whose grace period has elapsed until the stop channel is closed. The audit
+// history of purged VASPs is retained.
+func (s *Server) Compactor(stop <-chan struct{}) {
+	ticker := time.NewTicker(s.conf.CompactEvery)
+	defer ticker.Stop()
+	log.Info().Dur("interval", s.conf.CompactEvery).Msg("compactor started")
+
+	for {
+		s.compact(time.Now())
+
+		select {
+		case <-stop:
+			log.Info().Msg("compactor stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purges the tombstones of delisted VASPs that are due to be purged at the specified time.
+func (s *Server) compact(now time.Time) (purged int) {
+	vasps, err := s.db.List()
+	if err != nil {
+		log.Error().Err(err).Msg("could not list VASPs to compact")
+		return 0
+	}
+
+	for _, vasp := range vasps {
+		if vasp.VerificationStatus != pb.VerificationState_DELISTED || vasp.PurgeAfter == "" {
+			continue
+		}
+
+		after, err := time.Parse(time.RFC3339, vasp.PurgeAfter)
+		if err != nil {
+			log.Warn().Err(err).Uint64("id", vasp.Id).Msg("could not parse VASP purge after timestamp")
+			continue
+		}
+
+		if now.Before(after) {
+			continue
+		}
+
+		if err = s.db.Destroy(vasp.Id, compactorActor); err != nil {
+			log.Error().Err(err).Uint64("id", vasp.Id).Msg("could not purge delisted VASP")
+			continue
+		}
+		purged++
+	}
+
+	if purged > 0 {
+		log.Info().Int("purged", purged).Msg("delisted VASPs compacted")
+	}
+	return purged
+}
+
+// tombstone returns the public record of a delisted VASP, which identifies the VASP and
+// when and why it was delisted, without its entity details or certificate.
+func tombstone(vasp pb.VASP) *pb.VASP {
+	t := &pb.VASP{
+		Id:                 vasp.Id,
+		FirstListed:        vasp.FirstListed,
+		VerificationStatus: vasp.VerificationStatus,
+		DelistedOn:         vasp.DelistedOn,
+		DelistReason:       vasp.DelistReason,
+		PurgeAfter:         vasp.PurgeAfter,
+	}
+
+	if vasp.VaspEntity != nil {
+		t.VaspEntity = &pb.Entity{VaspFullLegalName: vasp.VaspEntity.VaspFullLegalName}
+	}
+	return t
+}
+
+// purged reconstructs the tombstone of a delisted VASP that has been purged by the
+// Compactor from its audit history, returning false if the VASP was never delisted.
+func (s *Server) purged(id uint64) (_ *pb.VASP, ok bool) {
+	entries, err := s.db.History(id)
+	if err != nil || len(entries) == 0 {
+		return nil, false
+	}
+
+	last := entries[len(entries)-1]
+	if last.Action != pb.AuditAction_DELETED {
+		return nil, false
+	}
+
+	// The deletion records the fields of the tombstone as they were before it was purged
+	fields := make(map[string]string, len(last.Changes))
+	for _, change := range last.Changes {
+		var value string
+		if err = json.Unmarshal([]byte(change.Before), &value); err == nil {
+			fields[change.Field] = value
+		}
+	}
+
+	if fields["verificationStatus"] != pb.VerificationState_DELISTED.String() {
+		return nil, false
+	}
+
+	vasp := pb.VASP{
+		Id:                 id,
+		FirstListed:        fields["firstListed"],
+		VerificationStatus: pb.VerificationState_DELISTED,
+		DelistedOn:         fields["delistedOn"],
+		DelistReason:       fields["delistReason"],
+		PurgeAfter:         fields["purgeAfter"],
+	}
+
+	if name := fields["vaspEntity.vaspFullLegalName"]; name != "" {
+		vasp.VaspEntity = &pb.Entity{VaspFullLegalName: name}
+	}
+	return &vasp, true
+}
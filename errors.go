@@ -0,0 +1,136 @@
+package trisads
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/bbengfort/trisads/store"
+	"github.com/bbengfort/trisads/validate"
+	"github.com/golang/protobuf/proto"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Resource type reported in the error details of VASP lookups.
+const resourceVASP = "VASP"
+
+// fail converts err into a gRPC status error with the specified error details, which the
+// handler should return in place of the reply. If legacy errors are enabled, the status
+// is instead stored as a pb.Error in the reply (dst) and a nil error is returned so that
+// clients that check the error field of the reply continue to work.
+func (s *Server) fail(dst **pb.Error, err error, details ...proto.Message) error {
+	if s.conf.LegacyErrors {
+		*dst = &pb.Error{Code: legacyCode(errorCode(err)), Message: errorMessage(err)}
+		return nil
+	}
+	return statusError(err, details...)
+}
+
+// statusError converts err into a gRPC status error with the specified error details.
+// Streaming RPCs have no reply to store a legacy error in, so they always return status
+// errors regardless of the legacy errors setting.
+func statusError(err error, details ...proto.Message) error {
+	st := status.New(errorCode(err), errorMessage(err))
+	if len(details) > 0 {
+		var derr error
+		if st, derr = st.WithDetails(details...); derr != nil {
+			log.Error().Err(derr).Msg("could not add details to status error")
+			st = status.New(errorCode(err), errorMessage(err))
+		}
+	}
+	return st.Err()
+}
+
+// returns the gRPC status code for the error, inspecting the sentinel errors of the
+// store and the directory service. Unknown errors are reported as internal errors.
+func errorCode(err error) codes.Code {
+	if st, ok := status.FromError(err); ok {
+		return st.Code()
+	}
+
+	var violations validate.Violations
+	switch {
+	case errors.As(err, &violations):
+		return codes.InvalidArgument
+	case errors.Is(err, store.ErrEntityNotFound):
+		return codes.NotFound
+	case errors.Is(err, store.ErrDuplicateEntity):
+		return codes.AlreadyExists
+	case errors.Is(err, store.ErrConflict):
+		return codes.Aborted
+	case errors.Is(err, store.ErrIncompleteRecord), errors.Is(err, store.ErrEmptyQuery),
+		errors.Is(err, ErrInvalidToken), errors.Is(err, ErrInvalidCursor),
+		errors.Is(err, ErrInvalidReason), errors.Is(err, ErrInvalidGracePeriod),
+		errors.Is(err, ErrInvalidMask):
+		return codes.InvalidArgument
+	case errors.Is(err, store.ErrInvalidTransition), errors.Is(err, ErrNoContactEmail),
+		errors.Is(err, ErrNoCommonName), errors.Is(err, ErrNoPKCS12Password),
+		errors.Is(err, ErrNoCertificate), errors.Is(err, ErrDelisted):
+		return codes.FailedPrecondition
+	default:
+		return codes.Internal
+	}
+}
+
+// returns the message of the error, unwrapping the message of status errors.
+func errorMessage(err error) string {
+	if st, ok := status.FromError(err); ok {
+		return st.Message()
+	}
+	return err.Error()
+}
+
+// converts a gRPC status code into the HTTP-like codes used by pb.Error.
+func legacyCode(code codes.Code) int32 {
+	switch code {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.AlreadyExists, codes.OutOfRange:
+		return 400
+	case codes.Unauthenticated:
+		return 401
+	case codes.PermissionDenied:
+		return 403
+	case codes.NotFound:
+		return 404
+	case codes.Aborted:
+		return 409
+	default:
+		return 500
+	}
+}
+
+// error detail describing the VASP that was requested by ID or by name.
+func vaspResource(id uint64, name string) *errdetails.ResourceInfo {
+	info := &errdetails.ResourceInfo{ResourceType: resourceVASP, ResourceName: name}
+	if id > 0 {
+		info.ResourceName = strconv.FormatUint(id, 10)
+	}
+	return info
+}
+
+// error detail describing a field of the request that was invalid.
+func badRequest(field, description string) *errdetails.BadRequest {
+	return &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: field, Description: description},
+		},
+	}
+}
+
+// error detail with the field violations of an invalid entity, prefixing the field names
+// with the path of the entity in the request.
+func fieldViolations(prefix string, violations validate.Violations) *errdetails.BadRequest {
+	details := &errdetails.BadRequest{
+		FieldViolations: make([]*errdetails.BadRequest_FieldViolation, 0, len(violations)),
+	}
+
+	for _, v := range violations {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       prefix + "." + v.Field,
+			Description: v.Description,
+		})
+	}
+	return details
+}
@@ -0,0 +1,137 @@
+package trisads
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/bbengfort/trisads/validate"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ErrInvalidMask is returned if the paths of an update mask cannot be applied to a VASP.
+var ErrInvalidMask = errors.New("invalid update mask")
+
+// The fields of a VASP that may be updated by the paths of an update mask, the other
+// fields of the VASP are managed by the directory service and the verification workflow.
+var maskable = map[string]bool{"vaspEntity": true, "vaspTRISACertification": true}
+
+// The paths of fields that cannot be updated by an update mask: the ids of the nested
+// records are assigned by the store and certificates are revoked by RevokeCertificate.
+// If the mask replaces a message that contains these fields, they are kept unchanged.
+func protected(fields []string) bool {
+	return fields[len(fields)-1] == "id" || strings.Join(fields, ".") == "vaspTRISACertification.revoked"
+}
+
+// applyMask copies the fields of the patch in the paths of the update mask into the VASP,
+// e.g. "vaspEntity.vaspURL" or "vaspTRISACertification.subjectName". Fields that are not
+// set on the patch are cleared, and a path to a message replaces the entire message.
+func applyMask(vasp, patch *pb.VASP, mask *field_mask.FieldMask) (err error) {
+	if len(mask.GetPaths()) == 0 {
+		return fmt.Errorf("%w: no paths specified", ErrInvalidMask)
+	}
+
+	// Check all of the paths before applying any of them
+	for _, path := range mask.Paths {
+		fields := strings.Split(path, ".")
+		if !maskable[fields[0]] {
+			return fmt.Errorf("%w: %q is not a field of the entity or certification", ErrInvalidMask, path)
+		}
+
+		if err = maskPath(proto.MessageReflect(patch).Descriptor(), fields); err != nil {
+			return fmt.Errorf("%w: %q %s", ErrInvalidMask, path, err)
+		}
+
+		if protected(fields) {
+			return fmt.Errorf("%w: %q cannot be updated", ErrInvalidMask, path)
+		}
+	}
+
+	orig := proto.MessageReflect(proto.Clone(vasp))
+	dst, src := proto.MessageReflect(vasp), proto.MessageReflect(patch)
+	for _, path := range mask.Paths {
+		copyPath(dst, src, strings.Split(path, "."))
+	}
+
+	preserve(dst, orig, nil)
+	return nil
+}
+
+// maskViolations returns only the violations of the entity fields that are in the paths
+// of the update mask, so that a legacy record that does not pass newer validation rules
+// can still be patched; the fields that are not updated are not validated again.
+func maskViolations(violations validate.Violations, mask *field_mask.FieldMask) (masked validate.Violations) {
+	for _, v := range violations {
+		field := "vaspEntity." + v.Field
+		if v.Field == "entity" {
+			field = "vaspEntity"
+		}
+
+		for _, path := range mask.GetPaths() {
+			if field == path || strings.HasPrefix(field, path+".") || strings.HasPrefix(path, field+".") {
+				masked = append(masked, v)
+				break
+			}
+		}
+	}
+	return masked
+}
+
+// checks that every field of the path exists and that only the last field is not a
+// singular message field.
+func maskPath(desc protoreflect.MessageDescriptor, fields []string) error {
+	fd := desc.Fields().ByName(protoreflect.Name(fields[0]))
+	if fd == nil {
+		return fmt.Errorf("has no field %s", fields[0])
+	}
+
+	if len(fields) == 1 {
+		return nil
+	}
+
+	if fd.Message() == nil || fd.IsList() || fd.IsMap() {
+		return fmt.Errorf("cannot select fields of %s", fields[0])
+	}
+	return maskPath(fd.Message(), fields[1:])
+}
+
+// copies the field at the path from the source message to the destination message,
+// creating the intermediate messages of the destination if they are not set.
+func copyPath(dst, src protoreflect.Message, fields []string) {
+	fd := dst.Descriptor().Fields().ByName(protoreflect.Name(fields[0]))
+	if len(fields) > 1 {
+		// Unset messages of the source are read as empty messages
+		copyPath(dst.Mutable(fd).Message(), src.Get(fd).Message(), fields[1:])
+		return
+	}
+
+	if src.Has(fd) {
+		dst.Set(fd, src.Get(fd))
+	} else {
+		dst.Clear(fd)
+	}
+}
+
+// restores the protected fields of the destination message from the original message,
+// descending into the messages that are set on both.
+func preserve(dst, orig protoreflect.Message, path []string) {
+	fds := dst.Descriptor().Fields()
+	for i := 0; i < fds.Len(); i++ {
+		fd := fds.Get(i)
+		fields := append(path[:len(path):len(path)], string(fd.Name()))
+
+		switch {
+		case protected(fields):
+			if orig.Has(fd) {
+				dst.Set(fd, orig.Get(fd))
+			} else {
+				dst.Clear(fd)
+			}
+		case fd.Message() != nil && !fd.IsList() && !fd.IsMap() && dst.Has(fd) && orig.Has(fd):
+			preserve(dst.Mutable(fd).Message(), orig.Get(fd).Message(), fields)
+		}
+	}
+}
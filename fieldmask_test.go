@@ -0,0 +1,187 @@
+package trisads
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/bbengfort/trisads/validate"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/protobuf/field_mask"
+)
+
+// returns a stored VASP with nested records that have been assigned ids.
+func maskedVASP() *pb.VASP {
+	return &pb.VASP{
+		Id: 42,
+		VaspEntity: &pb.Entity{
+			Id:                7,
+			VaspFullLegalName: "Masked Exchange",
+			VaspURL:           "https://masked.io",
+			VaspCountry:       "US",
+		},
+		VaspTRISACertification: &pb.TRISACertification{
+			Id:          8,
+			SubjectName: &pb.Name{Id: 9, CommonName: "masked.io", Organization: "Masked"},
+			Parameters:  []string{"a", "b"},
+			PublicKeyInfo: &pb.PublicKeyInfo{
+				Id:       10,
+				KeyUsage: []string{"digitalSignature"},
+			},
+			Revoked: false,
+		},
+		VerificationStatus: pb.VerificationState_VERIFIED,
+	}
+}
+
+func TestApplyMask(t *testing.T) {
+	patch := &pb.VASP{
+		Id: 99,
+		VaspEntity: &pb.Entity{
+			Id:                99,
+			VaspFullLegalName: "Patched Exchange",
+			VaspURL:           "https://patched.io",
+		},
+		VaspTRISACertification: &pb.TRISACertification{
+			Id:          99,
+			SubjectName: &pb.Name{Id: 99, CommonName: "patched.io"},
+			Parameters:  []string{"c"},
+			PublicKeyInfo: &pb.PublicKeyInfo{
+				Id:       99,
+				KeyUsage: []string{"keyEncipherment", "digitalSignature"},
+			},
+			Revoked: true,
+		},
+		VerificationStatus: pb.VerificationState_REJECTED,
+	}
+
+	tests := []struct {
+		name   string
+		paths  []string
+		expect func(*pb.VASP)
+	}{
+		{
+			name:  "scalar field",
+			paths: []string{"vaspEntity.vaspURL"},
+			expect: func(v *pb.VASP) {
+				v.VaspEntity.VaspURL = "https://patched.io"
+			},
+		},
+		{
+			name:  "unset field is cleared",
+			paths: []string{"vaspEntity.vaspCountry"},
+			expect: func(v *pb.VASP) {
+				v.VaspEntity.VaspCountry = ""
+			},
+		},
+		{
+			name:  "nested field",
+			paths: []string{"vaspTRISACertification.subjectName.commonName"},
+			expect: func(v *pb.VASP) {
+				v.VaspTRISACertification.SubjectName.CommonName = "patched.io"
+			},
+		},
+		{
+			name:  "repeated field",
+			paths: []string{"vaspTRISACertification.parameters", "vaspTRISACertification.PublicKeyInfo.keyUsage"},
+			expect: func(v *pb.VASP) {
+				v.VaspTRISACertification.Parameters = []string{"c"}
+				v.VaspTRISACertification.PublicKeyInfo.KeyUsage = []string{"keyEncipherment", "digitalSignature"}
+			},
+		},
+		{
+			name:  "message field keeps the protected fields",
+			paths: []string{"vaspTRISACertification.subjectName"},
+			expect: func(v *pb.VASP) {
+				v.VaspTRISACertification.SubjectName = &pb.Name{Id: 9, CommonName: "patched.io"}
+			},
+		},
+		{
+			name:  "top level message keeps the protected fields",
+			paths: []string{"vaspEntity", "vaspTRISACertification"},
+			expect: func(v *pb.VASP) {
+				v.VaspEntity = &pb.Entity{Id: 7, VaspFullLegalName: "Patched Exchange", VaspURL: "https://patched.io"}
+				v.VaspTRISACertification = &pb.TRISACertification{
+					Id:          8,
+					SubjectName: &pb.Name{Id: 9, CommonName: "patched.io"},
+					Parameters:  []string{"c"},
+					PublicKeyInfo: &pb.PublicKeyInfo{
+						Id:       10,
+						KeyUsage: []string{"keyEncipherment", "digitalSignature"},
+					},
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			vasp, expected := maskedVASP(), maskedVASP()
+			tc.expect(expected)
+
+			require.NoError(t, applyMask(vasp, patch, &field_mask.FieldMask{Paths: tc.paths}))
+			require.True(t, proto.Equal(expected, vasp), "expected %v got %v", expected, vasp)
+		})
+	}
+
+	// Nested messages that are not set on the stored VASP are created by the mask
+	vasp := &pb.VASP{Id: 42}
+	require.NoError(t, applyMask(vasp, patch, &field_mask.FieldMask{Paths: []string{"vaspTRISACertification.subjectName.commonName"}}))
+	require.Equal(t, "patched.io", vasp.VaspTRISACertification.SubjectName.CommonName)
+	require.Zero(t, vasp.VaspTRISACertification.Id)
+	require.Zero(t, vasp.VaspTRISACertification.SubjectName.Id)
+}
+
+func TestApplyMaskInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+	}{
+		{"empty mask", nil},
+		{"empty path", []string{""}},
+		{"unknown field", []string{"vaspEntity.vaspNickname"}},
+		{"unknown nested field", []string{"vaspTRISACertification.subjectName.nickname"}},
+		{"managed field", []string{"verificationStatus"}},
+		{"managed secret", []string{"verificationToken"}},
+		{"field of a scalar", []string{"vaspEntity.vaspURL.host"}},
+		{"field of a repeated field", []string{"vaspTRISACertification.parameters.value"}},
+		{"entity id", []string{"vaspEntity.id"}},
+		{"certification id", []string{"vaspTRISACertification.id"}},
+		{"nested id", []string{"vaspTRISACertification.subjectName.id"}},
+		{"public key info id", []string{"vaspTRISACertification.PublicKeyInfo.id"}},
+		{"revocation", []string{"vaspTRISACertification.revoked"}},
+		{"one invalid path", []string{"vaspEntity.vaspURL", "vaspEntity.id"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			vasp := maskedVASP()
+			err := applyMask(vasp, &pb.VASP{VaspEntity: &pb.Entity{VaspURL: "https://patched.io"}}, &field_mask.FieldMask{Paths: tc.paths})
+			require.True(t, errors.Is(err, ErrInvalidMask), "expected invalid mask error got %v", err)
+
+			// No path is applied if any path is invalid
+			require.True(t, proto.Equal(maskedVASP(), vasp))
+		})
+	}
+}
+
+func TestMaskViolations(t *testing.T) {
+	violations := validate.Violations{
+		{Field: "vaspURL", Description: "invalid url"},
+		{Field: "vaspCountry", Description: "invalid country"},
+	}
+
+	masked := func(paths ...string) validate.Violations {
+		return maskViolations(violations, &field_mask.FieldMask{Paths: paths})
+	}
+
+	require.Empty(t, masked("vaspEntity.vaspFullLegalName"))
+	require.Empty(t, masked("vaspTRISACertification"))
+	require.Equal(t, violations[:1], masked("vaspEntity.vaspURL"))
+	require.Equal(t, violations, masked("vaspEntity"))
+
+	// A missing entity is reported by a path to any of its fields
+	missing := validate.Violations{{Field: "entity", Description: "an entity is required"}}
+	require.Equal(t, missing, maskViolations(missing, &field_mask.FieldMask{Paths: []string{"vaspEntity.vaspURL"}}))
+}
@@ -0,0 +1,198 @@
+package trisads
+
+import (
+	"errors"
+	"time"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/bbengfort/trisads/store"
+	"github.com/rs/zerolog/log"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+)
+
+// Maximum number of attempts to deliver an email before it is dropped from the outbox.
+const maxEmailAttempts = 10
+
+// Errors that may occur when delivering emails from the outbox.
+var (
+	ErrUnknownEmailType = errors.New("unknown email type")
+	ErrEmailNotNeeded   = errors.New("email is no longer needed")
+)
+
+// Notify queues an email about a VASP in the outbox and wakes the EmailManager to deliver
+// it. Because the email is persisted before it is sent, it is retried if delivery fails.
+func (s *Server) Notify(e pb.Email) (err error) {
+	if e.Id, err = s.db.CreateEmail(e); err != nil {
+		return err
+	}
+
+	log.Debug().Uint64("email", e.Id).Uint64("id", e.Vasp).Str("type", e.Type.String()).Msg("email queued")
+	s.wakeOutbox()
+	return nil
+}
+
+// EmailManager runs in its own go routine, delivering the emails in the outbox when it is
+// woken by Notify or periodically to retry failed deliveries, until the stop channel is
+// closed. Because the outbox is persisted, delivery is resumed when the server restarts.
+func (s *Server) EmailManager(stop <-chan struct{}) {
+	ticker := time.NewTicker(s.conf.EmailPollEvery)
+	defer ticker.Stop()
+	log.Info().Dur("interval", s.conf.EmailPollEvery).Msg("email manager started")
+
+	for {
+		s.processEmails(stop)
+
+		select {
+		case <-stop:
+			log.Info().Msg("email manager stopped")
+			return
+		case <-ticker.C:
+		case <-s.outbox:
+		}
+	}
+}
+
+// wakes the EmailManager without blocking if it has already been woken.
+func (s *Server) wakeOutbox() {
+	select {
+	case s.outbox <- struct{}{}:
+	default:
+	}
+}
+
+// deliver all of the emails in the outbox that are ready for an attempt.
+func (s *Server) processEmails(stop <-chan struct{}) {
+	emails, err := s.db.ListEmails()
+	if err != nil {
+		log.Error().Err(err).Msg("could not list outbox emails")
+		return
+	}
+
+	now := time.Now()
+	for _, email := range emails {
+		// Do not start delivering another email if the server is shutting down
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		// Skip emails that are waiting to be retried
+		if email.NextAttempt != "" {
+			if next, err := time.Parse(time.RFC3339, email.NextAttempt); err == nil && now.Before(next) {
+				continue
+			}
+		}
+
+		if err = s.deliverEmail(email); err != nil {
+			s.handleEmailError(email, err)
+		}
+	}
+}
+
+// renders the email from the current state of the VASP record and sends it, removing it
+// from the outbox once it has been delivered.
+func (s *Server) deliverEmail(email pb.Email) (err error) {
+	var vasp pb.VASP
+	if vasp, err = s.db.Retrieve(email.Vasp); err != nil {
+		return err
+	}
+
+	// Delisted VASPs are no longer contacted by the directory service
+	if vasp.VerificationStatus == pb.VerificationState_DELISTED {
+		return ErrEmailNotNeeded
+	}
+
+	var message *mail.SGMailV3
+	switch email.Type {
+	case pb.EmailType_VERIFY_CONTACT:
+		// The contact may have verified their email address using a previous email
+		if vasp.VerificationToken == "" || !vasp.VerificationStatus.CanTransition(pb.VerificationState_EMAILED) {
+			return ErrEmailNotNeeded
+		}
+		message, err = s.verificationEmail(vasp)
+	case pb.EmailType_REVIEW_REQUEST:
+		message, err = s.reviewRequestEmail(vasp)
+	case pb.EmailType_REJECTION:
+		message, err = s.rejectionEmail(vasp, email.Reason)
+	case pb.EmailType_CERTIFICATES:
+		message, err = s.certificatesEmail(vasp, email.AttachmentName, email.Attachment)
+	default:
+		return ErrUnknownEmailType
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err = s.sendEmail(message); err != nil {
+		return err
+	}
+	log.Info().Uint64("email", email.Id).Uint64("id", vasp.Id).Str("type", email.Type.String()).Msg("email sent")
+
+	if err = s.db.DeleteEmail(email.Id); err != nil {
+		log.Error().Err(err).Uint64("email", email.Id).Msg("could not delete delivered email")
+	}
+
+	// The VASP contact has been sent the link to verify their email address; the VASP is
+	// retrieved again since it may have been modified while the email was being sent. Only
+	// VASPs that were waiting for the email are advanced, a resent email does not change
+	// the state of a VASP that was already emailed or has verified its email since.
+	if email.Type == pb.EmailType_VERIFY_CONTACT {
+		if vasp, err = s.db.Retrieve(vasp.Id); err == nil && awaitingEmail(vasp.VerificationStatus) {
+			vasp.VerificationStatus = pb.VerificationState_EMAILED
+			err = s.db.Update(vasp, emailManagerActor)
+		}
+
+		if err != nil {
+			log.Error().Err(err).Uint64("id", email.Vasp).Msg("could not update VASP verification state")
+		}
+	}
+	return nil
+}
+
+// returns true if the VASP is waiting for the verification email to be delivered: it has
+// just registered or the previous verification email could not be delivered.
+func awaitingEmail(state pb.VerificationState) bool {
+	return state == pb.VerificationState_SUBMITTED || state == pb.VerificationState_ERRORED
+}
+
+// schedules a retry of the email with exponential backoff, or drops the email from the
+// outbox if it can no longer be delivered. If the verification email could not be
+// delivered, the VASP is moved to the errored state so it can be resent by an admin.
+func (s *Server) handleEmailError(email pb.Email, err error) {
+	email.Attempts++
+	email.LastError = err.Error()
+
+	if errors.Is(err, ErrEmailNotNeeded) || errors.Is(err, store.ErrEntityNotFound) {
+		log.Debug().Err(err).Uint64("email", email.Id).Msg("dropping outbox email")
+		if err = s.db.DeleteEmail(email.Id); err != nil {
+			log.Error().Err(err).Uint64("email", email.Id).Msg("could not delete outbox email")
+		}
+		return
+	}
+
+	if email.Attempts < maxEmailAttempts {
+		delay := backoff(s.conf.EmailPollEvery, email.Attempts)
+		email.NextAttempt = time.Now().Add(delay).Format(time.RFC3339)
+		log.Warn().Err(err).Uint64("email", email.Id).Int32("attempts", email.Attempts).Dur("backoff", delay).Msg("could not send email, will retry")
+		if err = s.db.UpdateEmail(email); err != nil {
+			log.Error().Err(err).Uint64("email", email.Id).Msg("could not update outbox email")
+		}
+		return
+	}
+
+	log.Error().Err(err).Uint64("email", email.Id).Uint64("id", email.Vasp).Str("type", email.Type.String()).Msg("could not send email, too many attempts")
+	if email.Type == pb.EmailType_VERIFY_CONTACT {
+		if vasp, verr := s.db.Retrieve(email.Vasp); verr == nil && vasp.VerificationStatus == pb.VerificationState_SUBMITTED {
+			vasp.VerificationStatus = pb.VerificationState_ERRORED
+			if uerr := s.db.Update(vasp, emailManagerActor); uerr != nil {
+				log.Error().Err(uerr).Uint64("id", vasp.Id).Msg("could not update VASP verification state")
+			}
+		}
+	}
+
+	if err = s.db.DeleteEmail(email.Id); err != nil {
+		log.Error().Err(err).Uint64("email", email.Id).Msg("could not delete failed outbox email")
+	}
+}
@@ -0,0 +1,92 @@
+package trisads
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/sendgrid/sendgrid-go"
+	"github.com/stretchr/testify/require"
+)
+
+// delivers the emails in the outbox, returning the number of emails that were delivered.
+func deliverOutbox(t *testing.T, s *Server) (delivered int) {
+	emails, err := s.db.ListEmails()
+	require.NoError(t, err)
+
+	for _, email := range emails {
+		require.NoError(t, s.deliverEmail(email))
+		delivered++
+	}
+	return delivered
+}
+
+func TestDeliverVerificationEmail(t *testing.T) {
+	// SendGrid accepts every email and runs the hook while the email is being sent
+	var hook func()
+	sendgridAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hook != nil {
+			hook()
+			hook = nil
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer sendgridAPI.Close()
+
+	s := testServer(t)
+	s.email = sendgrid.NewSendClient("testing")
+	s.email.BaseURL = sendgridAPI.URL + "/v3/mail/send"
+	ctx := context.Background()
+
+	rep, err := s.Register(ctx, &pb.RegisterRequest{
+		Entity: &pb.Entity{VaspFullLegalName: "Emailed Exchange", VaspContactEmail: "admin@emailed.io", VaspURL: "https://emailed.io"},
+		Verify: true,
+	})
+	require.NoError(t, err)
+
+	state := func() pb.VerificationState {
+		vasp, err := s.db.Retrieve(rep.Id)
+		require.NoError(t, err)
+		return vasp.VerificationStatus
+	}
+
+	// Delivering the email moves the VASP from submitted into the emailed state
+	require.Equal(t, pb.VerificationState_SUBMITTED, state())
+	require.Equal(t, 1, deliverOutbox(t, s))
+	require.Equal(t, pb.VerificationState_EMAILED, state())
+
+	history, err := s.db.History(rep.Id)
+	require.NoError(t, err)
+
+	// Resending the email does not change the state of the VASP
+	_, err = s.ResendEmail(ctx, &pb.ResendEmailRequest{Id: rep.Id})
+	require.NoError(t, err)
+	require.Equal(t, 1, deliverOutbox(t, s))
+	require.Equal(t, pb.VerificationState_EMAILED, state())
+
+	resent, err := s.db.History(rep.Id)
+	require.NoError(t, err)
+	require.Equal(t, len(history), len(resent))
+
+	// The contact verifies their email using the first email while the resent email
+	// is being delivered
+	_, err = s.ResendEmail(ctx, &pb.ResendEmailRequest{Id: rep.Id})
+	require.NoError(t, err)
+
+	vasp, err := s.db.Retrieve(rep.Id)
+	require.NoError(t, err)
+	hook = func() {
+		_, err := s.VerifyEmail(ctx, &pb.VerifyEmailRequest{Id: rep.Id, Token: vasp.VerificationToken})
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, deliverOutbox(t, s))
+	require.Equal(t, pb.VerificationState_PENDING_REVIEW, state())
+
+	emails, err := s.db.ListEmails()
+	require.NoError(t, err)
+	for _, email := range emails {
+		require.NotEqual(t, pb.EmailType_VERIFY_CONTACT, email.Type)
+	}
+}
@@ -0,0 +1,243 @@
+package trisads
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/bbengfort/trisads/store"
+)
+
+// Number of search results returned in a page if the page size is not specified, and
+// the maximum page size that may be requested.
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+)
+
+// ErrInvalidCursor is returned if a page token or list cursor cannot be decoded, was
+// modified by the client, has expired or was created for a different ordering than the
+// request.
+var ErrInvalidCursor = errors.New("invalid page token or cursor")
+
+// cursor identifies the position of a VASP in an ordered search or listing so that the
+// next page starts immediately after it. Cursors contain the sort key of the VASP rather
+// than an offset so that pages are stable if VASPs are added or removed between requests.
+type cursor struct {
+	Order pb.ListOrder `json:"o,omitempty"`
+	Desc  bool         `json:"d,omitempty"`
+	Score float64      `json:"s,omitempty"`
+	Name  string       `json:"n,omitempty"`
+	Time  int64        `json:"t,omitempty"`
+	ID    uint64       `json:"i"`
+	Exp   int64        `json:"x,omitempty"`
+}
+
+// cursorCodec encodes cursors as opaque tokens that are signed so that clients cannot
+// modify them and that expire after the TTL so that stale positions are not resumed.
+type cursorCodec struct {
+	key []byte
+	ttl time.Duration
+}
+
+// creates a cursor codec with the secret key, or with a random key if it is empty, in
+// which case the tokens cannot be decoded once the server is restarted.
+func newCursorCodec(secret string, ttl time.Duration) (_ cursorCodec, err error) {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err = rand.Read(key); err != nil {
+			return cursorCodec{}, err
+		}
+	}
+	return cursorCodec{key: key, ttl: ttl}, nil
+}
+
+// encodes the cursor as an opaque token that is returned to clients
+func (k cursorCodec) encode(c cursor) string {
+	if c.Exp == 0 && k.ttl > 0 {
+		c.Exp = time.Now().Add(k.ttl).Unix()
+	}
+
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data) + "." + base64.RawURLEncoding.EncodeToString(k.sign(data))
+}
+
+// decodes a cursor that was returned to a client
+func (k cursorCodec) decode(token string) (c cursor, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return c, fmt.Errorf("%w: malformed token", ErrInvalidCursor)
+	}
+
+	var data, sig []byte
+	if data, err = base64.RawURLEncoding.DecodeString(parts[0]); err != nil {
+		return c, fmt.Errorf("%w: malformed token", ErrInvalidCursor)
+	}
+
+	if sig, err = base64.RawURLEncoding.DecodeString(parts[1]); err != nil || !hmac.Equal(sig, k.sign(data)) {
+		return c, fmt.Errorf("%w: token signature does not match", ErrInvalidCursor)
+	}
+
+	if err = json.Unmarshal(data, &c); err != nil || c.ID == 0 {
+		return c, fmt.Errorf("%w: malformed token", ErrInvalidCursor)
+	}
+
+	if c.Exp != 0 && time.Now().Unix() > c.Exp {
+		return c, fmt.Errorf("%w: token has expired", ErrInvalidCursor)
+	}
+	return c, nil
+}
+
+// returns the HMAC signature of the encoded cursor
+func (k cursorCodec) sign(data []byte) []byte {
+	mac := hmac.New(sha256.New, k.key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// paginate returns the page of search results that starts after the page token along
+// with the token of the next page, which is empty if this is the last page. The results
+// must be ordered by descending score and ascending ID (see store.SortResults).
+func (k cursorCodec) paginate(results []store.SearchResult, pageSize uint32, pageToken string) (_ []store.SearchResult, next string, err error) {
+	size := int(pageSize)
+	switch {
+	case size == 0:
+		size = defaultPageSize
+	case size > maxPageSize:
+		size = maxPageSize
+	}
+
+	start := 0
+	if pageToken != "" {
+		var c cursor
+		if c, err = k.decode(pageToken); err != nil {
+			return nil, "", err
+		}
+
+		start = sort.Search(len(results), func(i int) bool {
+			r := results[i]
+			return r.Score < c.Score || (r.Score == c.Score && r.VASP.Id > c.ID)
+		})
+	}
+
+	end := start + size
+	if end >= len(results) {
+		return results[start:], "", nil
+	}
+
+	last := results[end-1]
+	next = k.encode(cursor{Score: last.Score, ID: last.VASP.Id})
+	return results[start:end], next, nil
+}
+
+// returns the cursor of the VASP in a listing with the specified order
+func listCursor(v *pb.VASP, order pb.ListOrder, desc bool) cursor {
+	c := cursor{Order: order, Desc: desc, ID: v.Id}
+	switch order {
+	case pb.ListOrder_ORDER_BY_NAME:
+		if v.VaspEntity != nil {
+			c.Name = store.NormalizeName(v.VaspEntity.VaspFullLegalName)
+		}
+	case pb.ListOrder_ORDER_BY_FIRST_LISTED:
+		c.Time = parseTimestamp(v.FirstListed)
+	case pb.ListOrder_ORDER_BY_LAST_UPDATED:
+		c.Time = parseTimestamp(v.LastUpdated)
+	}
+	return c
+}
+
+// returns true if the cursor comes before the other cursor in the listing order; VASPs
+// with the same name or timestamp are ordered by ID so that the order is total.
+func (c cursor) before(o cursor) bool {
+	less, equal := c.ID < o.ID, c.ID == o.ID
+	switch c.Order {
+	case pb.ListOrder_ORDER_BY_NAME:
+		if c.Name != o.Name {
+			less, equal = strings.Compare(c.Name, o.Name) < 0, false
+		}
+	case pb.ListOrder_ORDER_BY_FIRST_LISTED, pb.ListOrder_ORDER_BY_LAST_UPDATED:
+		if c.Time != o.Time {
+			less, equal = c.Time < o.Time, false
+		}
+	}
+
+	if c.Desc {
+		return !less && !equal
+	}
+	return less
+}
+
+// Number of VASPs that are read from the store at a time while listing the directory.
+const listBatchSize = 256
+
+// listed is a VASP in a listing along with its cursor.
+type listed struct {
+	vasp   pb.VASP
+	cursor cursor
+}
+
+// returns the VASPs that come after the start cursor in the listing order, up to the
+// limit if it is not zero. The VASPs are read from the store in batches and only the
+// listed VASPs are kept, so the directory is not held in memory to list a page of it.
+func listAfter(db store.Store, start *cursor, order pb.ListOrder, desc bool, limit uint32) (page []listed, err error) {
+	var after uint64
+	for {
+		var batch []pb.VASP
+		if batch, err = db.ListAfter(after, listBatchSize); err != nil {
+			return nil, err
+		}
+
+		for _, vasp := range batch {
+			c := listCursor(&vasp, order, desc)
+			if start != nil && !start.before(c) {
+				continue
+			}
+
+			// Insert the VASP into its position in the page, dropping the VASPs after the
+			// limit; without a limit every VASP is listed so they are sorted at the end
+			if limit == 0 {
+				page = append(page, listed{vasp, c})
+				continue
+			}
+
+			i := sort.Search(len(page), func(j int) bool { return c.before(page[j].cursor) })
+			if i >= int(limit) {
+				continue
+			}
+
+			if len(page) < int(limit) {
+				page = append(page, listed{})
+			}
+			copy(page[i+1:], page[i:])
+			page[i] = listed{vasp, c}
+		}
+
+		if len(batch) < listBatchSize {
+			break
+		}
+		after = batch[len(batch)-1].Id
+	}
+
+	if limit == 0 {
+		sort.Slice(page, func(i, j int) bool { return page[i].cursor.before(page[j].cursor) })
+	}
+	return page, nil
+}
+
+// parses an RFC3339 timestamp of a VASP record, returning zero if it is not set.
+func parseTimestamp(ts string) int64 {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return 0
+	}
+	return t.UnixNano()
+}
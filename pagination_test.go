@@ -0,0 +1,231 @@
+package trisads
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/bbengfort/trisads/store"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCursorCodec(t *testing.T) {
+	k := cursorCodec{key: []byte("testing"), ttl: time.Hour}
+	c := cursor{Order: pb.ListOrder_ORDER_BY_NAME, Desc: true, Name: "example exchange", ID: 42}
+
+	token := k.encode(c)
+	decoded, err := k.decode(token)
+	require.NoError(t, err)
+	require.Equal(t, c.Name, decoded.Name)
+	require.Equal(t, c.ID, decoded.ID)
+	require.True(t, decoded.Exp > time.Now().Unix())
+
+	invalid := func(token string) {
+		_, err := k.decode(token)
+		require.True(t, errors.Is(err, ErrInvalidCursor), "expected invalid cursor for %q got %v", token, err)
+	}
+
+	// Malformed tokens
+	invalid("")
+	invalid("notatoken")
+	invalid("not.base64!")
+	invalid(token + ".extra")
+	invalid(strings.Split(token, ".")[0])
+
+	// Tokens that are signed but are not cursors
+	data := []byte(`{"n":"example exchange"}`)
+	invalid(base64.RawURLEncoding.EncodeToString(data) + "." + base64.RawURLEncoding.EncodeToString(k.sign(data)))
+
+	// Tampered tokens: the position is modified or the token was signed by another key
+	data = []byte(fmt.Sprintf(`{"o":1,"d":true,"n":"a","i":42,"x":%d}`, time.Now().Add(time.Hour).Unix()))
+	invalid(base64.RawURLEncoding.EncodeToString(data) + "." + strings.Split(token, ".")[1])
+	invalid(cursorCodec{key: []byte("other"), ttl: time.Hour}.encode(c))
+
+	// Expired tokens
+	c.Exp = time.Now().Add(-time.Minute).Unix()
+	invalid(k.encode(c))
+
+	// Tokens do not expire without a TTL
+	k.ttl = 0
+	c.Exp = 0
+	decoded, err = k.decode(k.encode(c))
+	require.NoError(t, err)
+	require.Zero(t, decoded.Exp)
+}
+
+func TestPaginate(t *testing.T) {
+	k := cursorCodec{key: []byte("testing"), ttl: time.Hour}
+
+	// Scores are tied so that pages split results with the same score
+	results := make([]store.SearchResult, 10)
+	for i := range results {
+		results[i] = store.SearchResult{VASP: pb.VASP{Id: uint64(i + 1)}, Score: 1 - float64(i/4)*0.25}
+	}
+	store.SortResults(results)
+
+	ids := func(results []store.SearchResult) (ids []uint64) {
+		for _, r := range results {
+			ids = append(ids, r.VASP.Id)
+		}
+		return ids
+	}
+
+	// Collects all of the pages of the results with the page size
+	pages := func(size uint32) (pages [][]uint64) {
+		var token string
+		for {
+			page, next, err := k.paginate(results, size, token)
+			require.NoError(t, err)
+			pages = append(pages, ids(page))
+			if next == "" {
+				return pages
+			}
+			token = next
+		}
+	}
+
+	require.Equal(t, [][]uint64{{1, 2, 3, 4}, {5, 6, 7, 8}, {9, 10}}, pages(4))
+	require.Equal(t, [][]uint64{{1, 2, 3, 4, 5}, {6, 7, 8, 9, 10}}, pages(5))
+	require.Equal(t, [][]uint64{{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}}, pages(10))
+	require.Equal(t, [][]uint64{{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}}, pages(0))
+	require.Equal(t, [][]uint64{{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}}, pages(maxPageSize+1))
+
+	// Pages are not shifted if results before the page token are removed
+	_, next, err := k.paginate(results, 3, "")
+	require.NoError(t, err)
+	page, _, err := k.paginate(results[2:], 3, next)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{4, 5, 6}, ids(page))
+
+	// The page after the last result is empty
+	last := k.encode(cursor{Score: results[9].Score, ID: results[9].VASP.Id})
+	page, next, err = k.paginate(results, 3, last)
+	require.NoError(t, err)
+	require.Empty(t, page)
+	require.Empty(t, next)
+
+	_, _, err = k.paginate(results, 3, "invalid")
+	require.True(t, errors.Is(err, ErrInvalidCursor))
+
+	// No results
+	page, next, err = k.paginate(nil, 3, "")
+	require.NoError(t, err)
+	require.Empty(t, page)
+	require.Empty(t, next)
+}
+
+// listStream collects the replies of the List RPC.
+type listStream struct {
+	grpc.ServerStream
+	replies []*pb.ListReply
+}
+
+func (s *listStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *listStream) Send(rep *pb.ListReply) error {
+	s.replies = append(s.replies, rep)
+	return nil
+}
+
+func TestListCursor(t *testing.T) {
+	s := testServer(t)
+	for _, name := range []string{"Delta Exchange", "Alpha Exchange", "Charlie Exchange", "Bravo Exchange"} {
+		_, err := s.db.Create(pb.VASP{VaspEntity: &pb.Entity{VaspFullLegalName: name}}, testActor)
+		require.NoError(t, err)
+	}
+
+	list := func(in *pb.ListRequest) (names []string, cursors []string) {
+		stream := &listStream{}
+		require.NoError(t, s.List(in, stream))
+		for _, rep := range stream.replies {
+			names = append(names, rep.Vasp.VaspEntity.VaspFullLegalName)
+			cursors = append(cursors, rep.Cursor)
+		}
+		return names, cursors
+	}
+
+	names, cursors := list(&pb.ListRequest{Order: pb.ListOrder_ORDER_BY_NAME, Limit: 2})
+	require.Equal(t, []string{"Alpha Exchange", "Bravo Exchange"}, names)
+
+	names, _ = list(&pb.ListRequest{Order: pb.ListOrder_ORDER_BY_NAME, Cursor: cursors[1]})
+	require.Equal(t, []string{"Charlie Exchange", "Delta Exchange"}, names)
+
+	names, _ = list(&pb.ListRequest{Order: pb.ListOrder_ORDER_BY_NAME, Descending: true, Limit: 1})
+	require.Equal(t, []string{"Delta Exchange"}, names)
+
+	// Cursors cannot be used with another order, modified or used after they expire
+	err := s.List(&pb.ListRequest{Order: pb.ListOrder_ORDER_BY_ID, Cursor: cursors[1]}, &listStream{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	err = s.List(&pb.ListRequest{Order: pb.ListOrder_ORDER_BY_NAME, Cursor: "x" + cursors[1]}, &listStream{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	expired := s.cursors.encode(cursor{Order: pb.ListOrder_ORDER_BY_NAME, Name: "alpha exchange", ID: 2, Exp: time.Now().Add(-time.Minute).Unix()})
+	err = s.List(&pb.ListRequest{Order: pb.ListOrder_ORDER_BY_NAME, Cursor: expired}, &listStream{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestListPages(t *testing.T) {
+	// More VASPs than are read from the store in a batch, named in reverse ID order
+	s := testServer(t)
+	n := listBatchSize + 44
+	for i := 0; i < n; i++ {
+		_, err := s.db.Create(pb.VASP{VaspEntity: &pb.Entity{VaspFullLegalName: fmt.Sprintf("Paged Exchange %03d", n-i)}}, testActor)
+		require.NoError(t, err)
+	}
+
+	list := func(in *pb.ListRequest) (ids []uint64, cursor string) {
+		stream := &listStream{}
+		require.NoError(t, s.List(in, stream))
+		for _, rep := range stream.replies {
+			ids = append(ids, rep.Vasp.Id)
+			cursor = rep.Cursor
+		}
+		return ids, cursor
+	}
+
+	orders := []struct {
+		order pb.ListOrder
+		desc  bool
+	}{
+		{pb.ListOrder_ORDER_BY_ID, false},
+		{pb.ListOrder_ORDER_BY_ID, true},
+		{pb.ListOrder_ORDER_BY_NAME, false},
+	}
+
+	for _, o := range orders {
+		all, _ := list(&pb.ListRequest{Order: o.order, Descending: o.desc})
+		require.Len(t, all, n)
+
+		// Listing by name is the reverse of listing by ID
+		if o.order == pb.ListOrder_ORDER_BY_NAME || o.desc {
+			require.True(t, all[0] > all[n-1])
+		} else {
+			require.True(t, all[0] < all[n-1])
+		}
+
+		// Resuming the listing in pages returns the same VASPs in the same order
+		var paged []uint64
+		var cursor string
+		for {
+			var page []uint64
+			page, cursor = list(&pb.ListRequest{Order: o.order, Descending: o.desc, Limit: 37, Cursor: cursor})
+			require.True(t, len(page) <= 37)
+			if len(page) == 0 {
+				break
+			}
+			paged = append(paged, page...)
+		}
+		require.Equal(t, all, paged, "listing %s descending %t", o.order, o.desc)
+	}
+}
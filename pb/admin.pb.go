@@ -0,0 +1,1101 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: admin.proto
+
+package pb
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	context "golang.org/x/net/context"
+	field_mask "google.golang.org/genproto/protobuf/field_mask"
+	grpc "google.golang.org/grpc"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type ListPendingRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListPendingRequest) Reset()         { *m = ListPendingRequest{} }
+func (m *ListPendingRequest) String() string { return proto.CompactTextString(m) }
+func (*ListPendingRequest) ProtoMessage()    {}
+func (*ListPendingRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{0}
+}
+
+func (m *ListPendingRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListPendingRequest.Unmarshal(m, b)
+}
+func (m *ListPendingRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListPendingRequest.Marshal(b, m, deterministic)
+}
+func (m *ListPendingRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListPendingRequest.Merge(m, src)
+}
+func (m *ListPendingRequest) XXX_Size() int {
+	return xxx_messageInfo_ListPendingRequest.Size(m)
+}
+func (m *ListPendingRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListPendingRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListPendingRequest proto.InternalMessageInfo
+
+type ListPendingReply struct {
+	Error                *Error   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Vasps                []*VASP  `protobuf:"bytes,2,rep,name=vasps,proto3" json:"vasps,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListPendingReply) Reset()         { *m = ListPendingReply{} }
+func (m *ListPendingReply) String() string { return proto.CompactTextString(m) }
+func (*ListPendingReply) ProtoMessage()    {}
+func (*ListPendingReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{1}
+}
+
+func (m *ListPendingReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListPendingReply.Unmarshal(m, b)
+}
+func (m *ListPendingReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListPendingReply.Marshal(b, m, deterministic)
+}
+func (m *ListPendingReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListPendingReply.Merge(m, src)
+}
+func (m *ListPendingReply) XXX_Size() int {
+	return xxx_messageInfo_ListPendingReply.Size(m)
+}
+func (m *ListPendingReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListPendingReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListPendingReply proto.InternalMessageInfo
+
+func (m *ListPendingReply) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *ListPendingReply) GetVasps() []*VASP {
+	if m != nil {
+		return m.Vasps
+	}
+	return nil
+}
+
+type ReviewRequest struct {
+	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReviewRequest) Reset()         { *m = ReviewRequest{} }
+func (m *ReviewRequest) String() string { return proto.CompactTextString(m) }
+func (*ReviewRequest) ProtoMessage()    {}
+func (*ReviewRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{2}
+}
+
+func (m *ReviewRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReviewRequest.Unmarshal(m, b)
+}
+func (m *ReviewRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReviewRequest.Marshal(b, m, deterministic)
+}
+func (m *ReviewRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReviewRequest.Merge(m, src)
+}
+func (m *ReviewRequest) XXX_Size() int {
+	return xxx_messageInfo_ReviewRequest.Size(m)
+}
+func (m *ReviewRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReviewRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReviewRequest proto.InternalMessageInfo
+
+func (m *ReviewRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type ReviewReply struct {
+	Error                *Error   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Vasp                 *VASP    `protobuf:"bytes,2,opt,name=vasp,proto3" json:"vasp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReviewReply) Reset()         { *m = ReviewReply{} }
+func (m *ReviewReply) String() string { return proto.CompactTextString(m) }
+func (*ReviewReply) ProtoMessage()    {}
+func (*ReviewReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{3}
+}
+
+func (m *ReviewReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReviewReply.Unmarshal(m, b)
+}
+func (m *ReviewReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReviewReply.Marshal(b, m, deterministic)
+}
+func (m *ReviewReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReviewReply.Merge(m, src)
+}
+func (m *ReviewReply) XXX_Size() int {
+	return xxx_messageInfo_ReviewReply.Size(m)
+}
+func (m *ReviewReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReviewReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReviewReply proto.InternalMessageInfo
+
+func (m *ReviewReply) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *ReviewReply) GetVasp() *VASP {
+	if m != nil {
+		return m.Vasp
+	}
+	return nil
+}
+
+type RejectRequest struct {
+	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason               string   `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RejectRequest) Reset()         { *m = RejectRequest{} }
+func (m *RejectRequest) String() string { return proto.CompactTextString(m) }
+func (*RejectRequest) ProtoMessage()    {}
+func (*RejectRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{4}
+}
+
+func (m *RejectRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RejectRequest.Unmarshal(m, b)
+}
+func (m *RejectRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RejectRequest.Marshal(b, m, deterministic)
+}
+func (m *RejectRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RejectRequest.Merge(m, src)
+}
+func (m *RejectRequest) XXX_Size() int {
+	return xxx_messageInfo_RejectRequest.Size(m)
+}
+func (m *RejectRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RejectRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RejectRequest proto.InternalMessageInfo
+
+func (m *RejectRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *RejectRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type RejectReply struct {
+	Error                *Error   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Vasp                 *VASP    `protobuf:"bytes,2,opt,name=vasp,proto3" json:"vasp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RejectReply) Reset()         { *m = RejectReply{} }
+func (m *RejectReply) String() string { return proto.CompactTextString(m) }
+func (*RejectReply) ProtoMessage()    {}
+func (*RejectReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{5}
+}
+
+func (m *RejectReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RejectReply.Unmarshal(m, b)
+}
+func (m *RejectReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RejectReply.Marshal(b, m, deterministic)
+}
+func (m *RejectReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RejectReply.Merge(m, src)
+}
+func (m *RejectReply) XXX_Size() int {
+	return xxx_messageInfo_RejectReply.Size(m)
+}
+func (m *RejectReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_RejectReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RejectReply proto.InternalMessageInfo
+
+func (m *RejectReply) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *RejectReply) GetVasp() *VASP {
+	if m != nil {
+		return m.Vasp
+	}
+	return nil
+}
+
+// UpdateVASPRequest must specify the version of the VASP that was edited so that the
+// update is aborted if the VASP was modified in the meantime. If an update mask is
+// specified, only the fields of the entity and certification in its paths (e.g.
+// "vaspEntity.vaspURL") are updated, otherwise the entire VASP is replaced.
+type UpdateVASPRequest struct {
+	Vasp                 *VASP                 `protobuf:"bytes,1,opt,name=vasp,proto3" json:"vasp,omitempty"`
+	UpdateMask           *field_mask.FieldMask `protobuf:"bytes,2,opt,name=updateMask,proto3" json:"updateMask,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *UpdateVASPRequest) Reset()         { *m = UpdateVASPRequest{} }
+func (m *UpdateVASPRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateVASPRequest) ProtoMessage()    {}
+func (*UpdateVASPRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{6}
+}
+
+func (m *UpdateVASPRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateVASPRequest.Unmarshal(m, b)
+}
+func (m *UpdateVASPRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateVASPRequest.Marshal(b, m, deterministic)
+}
+func (m *UpdateVASPRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateVASPRequest.Merge(m, src)
+}
+func (m *UpdateVASPRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateVASPRequest.Size(m)
+}
+func (m *UpdateVASPRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateVASPRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateVASPRequest proto.InternalMessageInfo
+
+func (m *UpdateVASPRequest) GetVasp() *VASP {
+	if m != nil {
+		return m.Vasp
+	}
+	return nil
+}
+
+func (m *UpdateVASPRequest) GetUpdateMask() *field_mask.FieldMask {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
+type UpdateVASPReply struct {
+	Error                *Error   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Vasp                 *VASP    `protobuf:"bytes,2,opt,name=vasp,proto3" json:"vasp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateVASPReply) Reset()         { *m = UpdateVASPReply{} }
+func (m *UpdateVASPReply) String() string { return proto.CompactTextString(m) }
+func (*UpdateVASPReply) ProtoMessage()    {}
+func (*UpdateVASPReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{7}
+}
+
+func (m *UpdateVASPReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateVASPReply.Unmarshal(m, b)
+}
+func (m *UpdateVASPReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateVASPReply.Marshal(b, m, deterministic)
+}
+func (m *UpdateVASPReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateVASPReply.Merge(m, src)
+}
+func (m *UpdateVASPReply) XXX_Size() int {
+	return xxx_messageInfo_UpdateVASPReply.Size(m)
+}
+func (m *UpdateVASPReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateVASPReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateVASPReply proto.InternalMessageInfo
+
+func (m *UpdateVASPReply) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *UpdateVASPReply) GetVasp() *VASP {
+	if m != nil {
+		return m.Vasp
+	}
+	return nil
+}
+
+// DeleteVASPRequest delists the VASP, leaving a tombstone that is purged by the
+// compaction job once the grace period (a duration such as "720h") has elapsed. If no
+// grace period is specified the server default is used, which may keep it indefinitely.
+type DeleteVASPRequest struct {
+	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason               string   `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	GracePeriod          string   `protobuf:"bytes,3,opt,name=gracePeriod,proto3" json:"gracePeriod,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteVASPRequest) Reset()         { *m = DeleteVASPRequest{} }
+func (m *DeleteVASPRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteVASPRequest) ProtoMessage()    {}
+func (*DeleteVASPRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{8}
+}
+
+func (m *DeleteVASPRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteVASPRequest.Unmarshal(m, b)
+}
+func (m *DeleteVASPRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteVASPRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteVASPRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteVASPRequest.Merge(m, src)
+}
+func (m *DeleteVASPRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteVASPRequest.Size(m)
+}
+func (m *DeleteVASPRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteVASPRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteVASPRequest proto.InternalMessageInfo
+
+func (m *DeleteVASPRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *DeleteVASPRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *DeleteVASPRequest) GetGracePeriod() string {
+	if m != nil {
+		return m.GracePeriod
+	}
+	return ""
+}
+
+type DeleteVASPReply struct {
+	Error                *Error   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteVASPReply) Reset()         { *m = DeleteVASPReply{} }
+func (m *DeleteVASPReply) String() string { return proto.CompactTextString(m) }
+func (*DeleteVASPReply) ProtoMessage()    {}
+func (*DeleteVASPReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{9}
+}
+
+func (m *DeleteVASPReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteVASPReply.Unmarshal(m, b)
+}
+func (m *DeleteVASPReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteVASPReply.Marshal(b, m, deterministic)
+}
+func (m *DeleteVASPReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteVASPReply.Merge(m, src)
+}
+func (m *DeleteVASPReply) XXX_Size() int {
+	return xxx_messageInfo_DeleteVASPReply.Size(m)
+}
+func (m *DeleteVASPReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteVASPReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteVASPReply proto.InternalMessageInfo
+
+func (m *DeleteVASPReply) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type ResendEmailRequest struct {
+	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResendEmailRequest) Reset()         { *m = ResendEmailRequest{} }
+func (m *ResendEmailRequest) String() string { return proto.CompactTextString(m) }
+func (*ResendEmailRequest) ProtoMessage()    {}
+func (*ResendEmailRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{10}
+}
+
+func (m *ResendEmailRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResendEmailRequest.Unmarshal(m, b)
+}
+func (m *ResendEmailRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResendEmailRequest.Marshal(b, m, deterministic)
+}
+func (m *ResendEmailRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResendEmailRequest.Merge(m, src)
+}
+func (m *ResendEmailRequest) XXX_Size() int {
+	return xxx_messageInfo_ResendEmailRequest.Size(m)
+}
+func (m *ResendEmailRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResendEmailRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResendEmailRequest proto.InternalMessageInfo
+
+func (m *ResendEmailRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type ResendEmailReply struct {
+	Error                *Error   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResendEmailReply) Reset()         { *m = ResendEmailReply{} }
+func (m *ResendEmailReply) String() string { return proto.CompactTextString(m) }
+func (*ResendEmailReply) ProtoMessage()    {}
+func (*ResendEmailReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{11}
+}
+
+func (m *ResendEmailReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResendEmailReply.Unmarshal(m, b)
+}
+func (m *ResendEmailReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResendEmailReply.Marshal(b, m, deterministic)
+}
+func (m *ResendEmailReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResendEmailReply.Merge(m, src)
+}
+func (m *ResendEmailReply) XXX_Size() int {
+	return xxx_messageInfo_ResendEmailReply.Size(m)
+}
+func (m *ResendEmailReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResendEmailReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResendEmailReply proto.InternalMessageInfo
+
+func (m *ResendEmailReply) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+// RevokeRequest revokes the TRISA certificate of a verified VASP with the RFC 5280
+// reason (e.g. "key compromise" or "cessation of operation"), unspecified by default.
+type RevokeRequest struct {
+	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason               string   `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeRequest) Reset()         { *m = RevokeRequest{} }
+func (m *RevokeRequest) String() string { return proto.CompactTextString(m) }
+func (*RevokeRequest) ProtoMessage()    {}
+func (*RevokeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{12}
+}
+
+func (m *RevokeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeRequest.Unmarshal(m, b)
+}
+func (m *RevokeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeRequest.Marshal(b, m, deterministic)
+}
+func (m *RevokeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeRequest.Merge(m, src)
+}
+func (m *RevokeRequest) XXX_Size() int {
+	return xxx_messageInfo_RevokeRequest.Size(m)
+}
+func (m *RevokeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeRequest proto.InternalMessageInfo
+
+func (m *RevokeRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *RevokeRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type RevokeReply struct {
+	Error                *Error   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Vasp                 *VASP    `protobuf:"bytes,2,opt,name=vasp,proto3" json:"vasp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeReply) Reset()         { *m = RevokeReply{} }
+func (m *RevokeReply) String() string { return proto.CompactTextString(m) }
+func (*RevokeReply) ProtoMessage()    {}
+func (*RevokeReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{13}
+}
+
+func (m *RevokeReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeReply.Unmarshal(m, b)
+}
+func (m *RevokeReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeReply.Marshal(b, m, deterministic)
+}
+func (m *RevokeReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeReply.Merge(m, src)
+}
+func (m *RevokeReply) XXX_Size() int {
+	return xxx_messageInfo_RevokeReply.Size(m)
+}
+func (m *RevokeReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeReply proto.InternalMessageInfo
+
+func (m *RevokeReply) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *RevokeReply) GetVasp() *VASP {
+	if m != nil {
+		return m.Vasp
+	}
+	return nil
+}
+
+// HistoryRequest returns the audit history of a VASP, including the history of VASPs
+// that have been deleted from the directory.
+type HistoryRequest struct {
+	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HistoryRequest) Reset()         { *m = HistoryRequest{} }
+func (m *HistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*HistoryRequest) ProtoMessage()    {}
+func (*HistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{14}
+}
+
+func (m *HistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryRequest.Unmarshal(m, b)
+}
+func (m *HistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *HistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryRequest.Merge(m, src)
+}
+func (m *HistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_HistoryRequest.Size(m)
+}
+func (m *HistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryRequest proto.InternalMessageInfo
+
+func (m *HistoryRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type HistoryReply struct {
+	Error                *Error        `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Entries              []*AuditEntry `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *HistoryReply) Reset()         { *m = HistoryReply{} }
+func (m *HistoryReply) String() string { return proto.CompactTextString(m) }
+func (*HistoryReply) ProtoMessage()    {}
+func (*HistoryReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_73a7fc70dcc2027c, []int{15}
+}
+
+func (m *HistoryReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryReply.Unmarshal(m, b)
+}
+func (m *HistoryReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryReply.Marshal(b, m, deterministic)
+}
+func (m *HistoryReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryReply.Merge(m, src)
+}
+func (m *HistoryReply) XXX_Size() int {
+	return xxx_messageInfo_HistoryReply.Size(m)
+}
+func (m *HistoryReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryReply proto.InternalMessageInfo
+
+func (m *HistoryReply) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *HistoryReply) GetEntries() []*AuditEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ListPendingRequest)(nil), "pb.ListPendingRequest")
+	proto.RegisterType((*ListPendingReply)(nil), "pb.ListPendingReply")
+	proto.RegisterType((*ReviewRequest)(nil), "pb.ReviewRequest")
+	proto.RegisterType((*ReviewReply)(nil), "pb.ReviewReply")
+	proto.RegisterType((*RejectRequest)(nil), "pb.RejectRequest")
+	proto.RegisterType((*RejectReply)(nil), "pb.RejectReply")
+	proto.RegisterType((*UpdateVASPRequest)(nil), "pb.UpdateVASPRequest")
+	proto.RegisterType((*UpdateVASPReply)(nil), "pb.UpdateVASPReply")
+	proto.RegisterType((*DeleteVASPRequest)(nil), "pb.DeleteVASPRequest")
+	proto.RegisterType((*DeleteVASPReply)(nil), "pb.DeleteVASPReply")
+	proto.RegisterType((*ResendEmailRequest)(nil), "pb.ResendEmailRequest")
+	proto.RegisterType((*ResendEmailReply)(nil), "pb.ResendEmailReply")
+	proto.RegisterType((*RevokeRequest)(nil), "pb.RevokeRequest")
+	proto.RegisterType((*RevokeReply)(nil), "pb.RevokeReply")
+	proto.RegisterType((*HistoryRequest)(nil), "pb.HistoryRequest")
+	proto.RegisterType((*HistoryReply)(nil), "pb.HistoryReply")
+}
+
+func init() { proto.RegisterFile("admin.proto", fileDescriptor_73a7fc70dcc2027c) }
+
+var fileDescriptor_73a7fc70dcc2027c = []byte{
+	// 542 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x54, 0x4f, 0x6b, 0xdb, 0x4e,
+	0x10, 0xfd, 0xd9, 0x4e, 0x9c, 0x9f, 0x47, 0xa9, 0xff, 0x6c, 0xdc, 0x60, 0x96, 0xd2, 0x08, 0xd1,
+	0x83, 0x4f, 0x0a, 0x75, 0x0e, 0x85, 0x40, 0x0e, 0x86, 0xba, 0xb4, 0x90, 0x82, 0x91, 0xdb, 0x42,
+	0x0f, 0xa5, 0xc8, 0xd1, 0xc4, 0x6c, 0x2d, 0x6b, 0x55, 0xad, 0xec, 0xe2, 0x0f, 0xd8, 0xef, 0x55,
+	0x76, 0x57, 0xb2, 0x57, 0x51, 0x0d, 0xa2, 0xf8, 0xb8, 0x6f, 0xdf, 0x9b, 0xd9, 0x9d, 0x79, 0x33,
+	0x60, 0xf9, 0xc1, 0x8a, 0x45, 0x6e, 0x9c, 0xf0, 0x94, 0x93, 0x7a, 0x3c, 0xa7, 0x2d, 0x3f, 0x66,
+	0xfa, 0x48, 0xcf, 0x57, 0x3c, 0xc0, 0x50, 0x64, 0x27, 0x7b, 0xc1, 0xf9, 0x22, 0xc4, 0x6b, 0x75,
+	0x9a, 0xaf, 0x1f, 0xaf, 0x1f, 0x19, 0x86, 0xc1, 0xf7, 0x95, 0x2f, 0x96, 0x9a, 0xe1, 0xf4, 0x81,
+	0xdc, 0x33, 0x91, 0x4e, 0x31, 0x0a, 0x58, 0xb4, 0xf0, 0xf0, 0xe7, 0x1a, 0x45, 0xea, 0xcc, 0xa0,
+	0x5b, 0x40, 0xe3, 0x70, 0x4b, 0xae, 0xe0, 0x14, 0x93, 0x84, 0x27, 0x83, 0x9a, 0x5d, 0x1b, 0x5a,
+	0xa3, 0x96, 0x1b, 0xcf, 0xdd, 0x89, 0x04, 0x3c, 0x8d, 0x93, 0x97, 0x70, 0xba, 0xf1, 0x45, 0x2c,
+	0x06, 0x75, 0xbb, 0x31, 0xb4, 0x46, 0xff, 0x4b, 0xc2, 0x97, 0xf1, 0x6c, 0xea, 0x69, 0xd8, 0xb9,
+	0x82, 0x67, 0x1e, 0x6e, 0x18, 0xfe, 0xca, 0xb2, 0x90, 0x36, 0xd4, 0x59, 0xa0, 0xc2, 0x9d, 0x78,
+	0x75, 0x16, 0x38, 0xf7, 0x60, 0xe5, 0x84, 0x4a, 0x09, 0x5f, 0xc0, 0x89, 0x8c, 0x3c, 0xa8, 0xdb,
+	0xb5, 0x42, 0x3e, 0x85, 0x3a, 0x6f, 0x64, 0xba, 0x1f, 0xf8, 0x90, 0x1e, 0x48, 0x47, 0x2e, 0xa1,
+	0x99, 0xa0, 0x2f, 0x78, 0xa4, 0x02, 0xb4, 0xbc, 0xec, 0xa4, 0x9f, 0xa1, 0x85, 0x47, 0x78, 0xc6,
+	0x0a, 0x7a, 0x9f, 0xe3, 0xc0, 0x4f, 0x51, 0x61, 0xd9, 0x53, 0x72, 0x49, 0xed, 0x6f, 0x12, 0x72,
+	0x0b, 0xb0, 0x56, 0x92, 0x8f, 0xbe, 0x58, 0x66, 0x61, 0xa9, 0xab, 0x5b, 0xe9, 0xe6, 0xad, 0x74,
+	0xdf, 0xc9, 0x56, 0x4a, 0x86, 0x67, 0xb0, 0x9d, 0x29, 0x74, 0xcc, 0x74, 0x47, 0xf8, 0xc0, 0x37,
+	0xe8, 0xbd, 0xc5, 0x10, 0x8b, 0x1f, 0xa8, 0x58, 0x4b, 0x62, 0x83, 0xb5, 0x48, 0xfc, 0x07, 0x9c,
+	0x62, 0xc2, 0x78, 0x30, 0x68, 0xa8, 0x4b, 0x13, 0x72, 0x46, 0xd0, 0x31, 0xc3, 0x57, 0x79, 0xb0,
+	0xf3, 0x0a, 0x88, 0x87, 0x02, 0xa3, 0x60, 0xb2, 0xf2, 0x59, 0x78, 0xc8, 0x4e, 0x37, 0xd0, 0x2d,
+	0xb0, 0x2a, 0x85, 0x56, 0xae, 0xd9, 0xf0, 0x25, 0xfe, 0x93, 0x6b, 0xb4, 0xf0, 0x08, 0x45, 0xb7,
+	0xa1, 0xfd, 0x9e, 0x89, 0x94, 0x27, 0xdb, 0x43, 0xbf, 0xfb, 0x0a, 0xe7, 0x3b, 0x46, 0xa5, 0x84,
+	0x43, 0x38, 0xc3, 0x28, 0x4d, 0x18, 0xe6, 0x03, 0xda, 0x96, 0x94, 0xf1, 0x3a, 0x60, 0xe9, 0x24,
+	0x4a, 0x93, 0xad, 0x97, 0x5f, 0x8f, 0x7e, 0x37, 0x00, 0x3e, 0x79, 0x1f, 0x66, 0xe3, 0xb1, 0xdc,
+	0x33, 0xe4, 0x0e, 0x2c, 0x63, 0x19, 0x90, 0x4b, 0x29, 0x2b, 0xef, 0x0c, 0xda, 0x2f, 0xe1, 0x71,
+	0xb8, 0x75, 0xfe, 0x23, 0x2e, 0x34, 0xf5, 0x54, 0x93, 0x9e, 0x64, 0x14, 0x56, 0x00, 0xed, 0x98,
+	0x90, 0xc1, 0x97, 0xe3, 0x97, 0xf3, 0x8d, 0x19, 0xa6, 0x1d, 0x13, 0xd2, 0xfc, 0x5b, 0x80, 0xbd,
+	0xe3, 0xc9, 0x73, 0x49, 0x28, 0x0d, 0x1c, 0xbd, 0x78, 0x0a, 0xef, 0xb4, 0x7b, 0xf3, 0x69, 0x6d,
+	0xc9, 0xeb, 0xf4, 0xe2, 0x29, 0xac, 0xb5, 0x77, 0x60, 0x19, 0xf6, 0xd2, 0x65, 0x29, 0xbb, 0x92,
+	0xf6, 0x4b, 0xb8, 0x59, 0x16, 0xbe, 0xc4, 0x5d, 0x59, 0xf6, 0xa6, 0xa3, 0x1d, 0x13, 0xd2, 0xfc,
+	0xd7, 0x70, 0x96, 0xf5, 0x9b, 0x10, 0x79, 0x5b, 0xb4, 0x07, 0xed, 0x16, 0x30, 0x25, 0x99, 0x37,
+	0xd5, 0xae, 0xb8, 0xf9, 0x33, 0x00, 0x88, 0x77, 0xff, 0xc0, 0x30, 0x06, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// TRISAAdminClient is the client API for TRISAAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type TRISAAdminClient interface {
+	ListPending(ctx context.Context, in *ListPendingRequest, opts ...grpc.CallOption) (*ListPendingReply, error)
+	Review(ctx context.Context, in *ReviewRequest, opts ...grpc.CallOption) (*ReviewReply, error)
+	Reject(ctx context.Context, in *RejectRequest, opts ...grpc.CallOption) (*RejectReply, error)
+	UpdateVASP(ctx context.Context, in *UpdateVASPRequest, opts ...grpc.CallOption) (*UpdateVASPReply, error)
+	DeleteVASP(ctx context.Context, in *DeleteVASPRequest, opts ...grpc.CallOption) (*DeleteVASPReply, error)
+	ResendEmail(ctx context.Context, in *ResendEmailRequest, opts ...grpc.CallOption) (*ResendEmailReply, error)
+	Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*RevokeReply, error)
+	History(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryReply, error)
+}
+
+type tRISAAdminClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewTRISAAdminClient(cc *grpc.ClientConn) TRISAAdminClient {
+	return &tRISAAdminClient{cc}
+}
+
+func (c *tRISAAdminClient) ListPending(ctx context.Context, in *ListPendingRequest, opts ...grpc.CallOption) (*ListPendingReply, error) {
+	out := new(ListPendingReply)
+	err := c.cc.Invoke(ctx, "/pb.TRISAAdmin/ListPending", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tRISAAdminClient) Review(ctx context.Context, in *ReviewRequest, opts ...grpc.CallOption) (*ReviewReply, error) {
+	out := new(ReviewReply)
+	err := c.cc.Invoke(ctx, "/pb.TRISAAdmin/Review", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tRISAAdminClient) Reject(ctx context.Context, in *RejectRequest, opts ...grpc.CallOption) (*RejectReply, error) {
+	out := new(RejectReply)
+	err := c.cc.Invoke(ctx, "/pb.TRISAAdmin/Reject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tRISAAdminClient) UpdateVASP(ctx context.Context, in *UpdateVASPRequest, opts ...grpc.CallOption) (*UpdateVASPReply, error) {
+	out := new(UpdateVASPReply)
+	err := c.cc.Invoke(ctx, "/pb.TRISAAdmin/UpdateVASP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tRISAAdminClient) DeleteVASP(ctx context.Context, in *DeleteVASPRequest, opts ...grpc.CallOption) (*DeleteVASPReply, error) {
+	out := new(DeleteVASPReply)
+	err := c.cc.Invoke(ctx, "/pb.TRISAAdmin/DeleteVASP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tRISAAdminClient) ResendEmail(ctx context.Context, in *ResendEmailRequest, opts ...grpc.CallOption) (*ResendEmailReply, error) {
+	out := new(ResendEmailReply)
+	err := c.cc.Invoke(ctx, "/pb.TRISAAdmin/ResendEmail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tRISAAdminClient) Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*RevokeReply, error) {
+	out := new(RevokeReply)
+	err := c.cc.Invoke(ctx, "/pb.TRISAAdmin/Revoke", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tRISAAdminClient) History(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryReply, error) {
+	out := new(HistoryReply)
+	err := c.cc.Invoke(ctx, "/pb.TRISAAdmin/History", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TRISAAdminServer is the server API for TRISAAdmin service.
+type TRISAAdminServer interface {
+	ListPending(context.Context, *ListPendingRequest) (*ListPendingReply, error)
+	Review(context.Context, *ReviewRequest) (*ReviewReply, error)
+	Reject(context.Context, *RejectRequest) (*RejectReply, error)
+	UpdateVASP(context.Context, *UpdateVASPRequest) (*UpdateVASPReply, error)
+	DeleteVASP(context.Context, *DeleteVASPRequest) (*DeleteVASPReply, error)
+	ResendEmail(context.Context, *ResendEmailRequest) (*ResendEmailReply, error)
+	Revoke(context.Context, *RevokeRequest) (*RevokeReply, error)
+	History(context.Context, *HistoryRequest) (*HistoryReply, error)
+}
+
+func RegisterTRISAAdminServer(s *grpc.Server, srv TRISAAdminServer) {
+	s.RegisterService(&_TRISAAdmin_serviceDesc, srv)
+}
+
+func _TRISAAdmin_ListPending_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPendingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TRISAAdminServer).ListPending(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.TRISAAdmin/ListPending",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TRISAAdminServer).ListPending(ctx, req.(*ListPendingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TRISAAdmin_Review_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TRISAAdminServer).Review(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.TRISAAdmin/Review",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TRISAAdminServer).Review(ctx, req.(*ReviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TRISAAdmin_Reject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TRISAAdminServer).Reject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.TRISAAdmin/Reject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TRISAAdminServer).Reject(ctx, req.(*RejectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TRISAAdmin_UpdateVASP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateVASPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TRISAAdminServer).UpdateVASP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.TRISAAdmin/UpdateVASP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TRISAAdminServer).UpdateVASP(ctx, req.(*UpdateVASPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TRISAAdmin_DeleteVASP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteVASPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TRISAAdminServer).DeleteVASP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.TRISAAdmin/DeleteVASP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TRISAAdminServer).DeleteVASP(ctx, req.(*DeleteVASPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TRISAAdmin_ResendEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResendEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TRISAAdminServer).ResendEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.TRISAAdmin/ResendEmail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TRISAAdminServer).ResendEmail(ctx, req.(*ResendEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TRISAAdmin_Revoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TRISAAdminServer).Revoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.TRISAAdmin/Revoke",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TRISAAdminServer).Revoke(ctx, req.(*RevokeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TRISAAdmin_History_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TRISAAdminServer).History(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.TRISAAdmin/History",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TRISAAdminServer).History(ctx, req.(*HistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _TRISAAdmin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.TRISAAdmin",
+	HandlerType: (*TRISAAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListPending",
+			Handler:    _TRISAAdmin_ListPending_Handler,
+		},
+		{
+			MethodName: "Review",
+			Handler:    _TRISAAdmin_Review_Handler,
+		},
+		{
+			MethodName: "Reject",
+			Handler:    _TRISAAdmin_Reject_Handler,
+		},
+		{
+			MethodName: "UpdateVASP",
+			Handler:    _TRISAAdmin_UpdateVASP_Handler,
+		},
+		{
+			MethodName: "DeleteVASP",
+			Handler:    _TRISAAdmin_DeleteVASP_Handler,
+		},
+		{
+			MethodName: "ResendEmail",
+			Handler:    _TRISAAdmin_ResendEmail_Handler,
+		},
+		{
+			MethodName: "Revoke",
+			Handler:    _TRISAAdmin_Revoke_Handler,
+		},
+		{
+			MethodName: "History",
+			Handler:    _TRISAAdmin_History_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin.proto",
+}
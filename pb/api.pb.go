@@ -22,6 +22,96 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+// CertificateValidity filters VASPs by the state of their TRISA certificate. A
+// certificate is valid if it has not been revoked and the current time is within its
+// validity period.
+type CertificateValidity int32
+
+const (
+	CertificateValidity_ANY_CERTIFICATE     CertificateValidity = 0
+	CertificateValidity_VALID_CERTIFICATE   CertificateValidity = 1
+	CertificateValidity_INVALID_CERTIFICATE CertificateValidity = 2
+	CertificateValidity_NO_CERTIFICATE      CertificateValidity = 3
+)
+
+var CertificateValidity_name = map[int32]string{
+	0: "ANY_CERTIFICATE",
+	1: "VALID_CERTIFICATE",
+	2: "INVALID_CERTIFICATE",
+	3: "NO_CERTIFICATE",
+}
+
+var CertificateValidity_value = map[string]int32{
+	"ANY_CERTIFICATE":     0,
+	"VALID_CERTIFICATE":   1,
+	"INVALID_CERTIFICATE": 2,
+	"NO_CERTIFICATE":      3,
+}
+
+func (x CertificateValidity) String() string {
+	return proto.EnumName(CertificateValidity_name, int32(x))
+}
+
+func (CertificateValidity) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{0}
+}
+
+type ListOrder int32
+
+const (
+	ListOrder_ORDER_BY_ID           ListOrder = 0
+	ListOrder_ORDER_BY_NAME         ListOrder = 1
+	ListOrder_ORDER_BY_FIRST_LISTED ListOrder = 2
+	ListOrder_ORDER_BY_LAST_UPDATED ListOrder = 3
+)
+
+var ListOrder_name = map[int32]string{
+	0: "ORDER_BY_ID",
+	1: "ORDER_BY_NAME",
+	2: "ORDER_BY_FIRST_LISTED",
+	3: "ORDER_BY_LAST_UPDATED",
+}
+
+var ListOrder_value = map[string]int32{
+	"ORDER_BY_ID":           0,
+	"ORDER_BY_NAME":         1,
+	"ORDER_BY_FIRST_LISTED": 2,
+	"ORDER_BY_LAST_UPDATED": 3,
+}
+
+func (x ListOrder) String() string {
+	return proto.EnumName(ListOrder_name, int32(x))
+}
+
+func (ListOrder) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{1}
+}
+
+type Query_Operator int32
+
+const (
+	Query_AND Query_Operator = 0
+	Query_OR  Query_Operator = 1
+)
+
+var Query_Operator_name = map[int32]string{
+	0: "AND",
+	1: "OR",
+}
+
+var Query_Operator_value = map[string]int32{
+	"AND": 0,
+	"OR":  1,
+}
+
+func (x Query_Operator) String() string {
+	return proto.EnumName(Query_Operator_name, int32(x))
+}
+
+func (Query_Operator) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{6, 0}
+}
+
 type Error struct {
 	Code                 int32    `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
 	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
@@ -119,6 +209,7 @@ func (m *RegisterRequest) GetVerify() bool {
 type RegisterReply struct {
 	Error                *Error   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
 	Id                   uint64   `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Pkcs12Password       string   `protobuf:"bytes,3,opt,name=pkcs12Password,proto3" json:"pkcs12Password,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -163,12 +254,27 @@ func (m *RegisterReply) GetId() uint64 {
 	return 0
 }
 
+func (m *RegisterReply) GetPkcs12Password() string {
+	if m != nil {
+		return m.Pkcs12Password
+	}
+	return ""
+}
+
+// LookupRequest identifies a single VASP by its ID or by one of its unique keys: the
+// full legal name, the LEI number, the domain of the VASP URL or the common name of the
+// VASP's TRISA certificate (e.g. to identify the peer of an mTLS connection).
 type LookupRequest struct {
-	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	// Types that are valid to be assigned to Query:
+	//	*LookupRequest_Id
+	//	*LookupRequest_Name
+	//	*LookupRequest_Lei
+	//	*LookupRequest_Domain
+	//	*LookupRequest_CommonName
+	Query                isLookupRequest_Query `protobuf_oneof:"query"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
 func (m *LookupRequest) Reset()         { *m = LookupRequest{} }
@@ -196,26 +302,103 @@ func (m *LookupRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_LookupRequest proto.InternalMessageInfo
 
-func (m *LookupRequest) GetId() uint64 {
+type isLookupRequest_Query interface {
+	isLookupRequest_Query()
+}
+
+type LookupRequest_Id struct {
+	Id uint64 `protobuf:"varint,1,opt,name=id,proto3,oneof"`
+}
+
+type LookupRequest_Name struct {
+	Name string `protobuf:"bytes,2,opt,name=name,proto3,oneof"`
+}
+
+type LookupRequest_Lei struct {
+	Lei string `protobuf:"bytes,3,opt,name=lei,proto3,oneof"`
+}
+
+type LookupRequest_Domain struct {
+	Domain string `protobuf:"bytes,4,opt,name=domain,proto3,oneof"`
+}
+
+type LookupRequest_CommonName struct {
+	CommonName string `protobuf:"bytes,5,opt,name=commonName,proto3,oneof"`
+}
+
+func (*LookupRequest_Id) isLookupRequest_Query() {}
+
+func (*LookupRequest_Name) isLookupRequest_Query() {}
+
+func (*LookupRequest_Lei) isLookupRequest_Query() {}
+
+func (*LookupRequest_Domain) isLookupRequest_Query() {}
+
+func (*LookupRequest_CommonName) isLookupRequest_Query() {}
+
+func (m *LookupRequest) GetQuery() isLookupRequest_Query {
 	if m != nil {
-		return m.Id
+		return m.Query
+	}
+	return nil
+}
+
+func (m *LookupRequest) GetId() uint64 {
+	if x, ok := m.GetQuery().(*LookupRequest_Id); ok {
+		return x.Id
 	}
 	return 0
 }
 
 func (m *LookupRequest) GetName() string {
-	if m != nil {
-		return m.Name
+	if x, ok := m.GetQuery().(*LookupRequest_Name); ok {
+		return x.Name
+	}
+	return ""
+}
+
+func (m *LookupRequest) GetLei() string {
+	if x, ok := m.GetQuery().(*LookupRequest_Lei); ok {
+		return x.Lei
+	}
+	return ""
+}
+
+func (m *LookupRequest) GetDomain() string {
+	if x, ok := m.GetQuery().(*LookupRequest_Domain); ok {
+		return x.Domain
 	}
 	return ""
 }
 
+func (m *LookupRequest) GetCommonName() string {
+	if x, ok := m.GetQuery().(*LookupRequest_CommonName); ok {
+		return x.CommonName
+	}
+	return ""
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*LookupRequest) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*LookupRequest_Id)(nil),
+		(*LookupRequest_Name)(nil),
+		(*LookupRequest_Lei)(nil),
+		(*LookupRequest_Domain)(nil),
+		(*LookupRequest_CommonName)(nil),
+	}
+}
+
+// LookupReply for a VASP that has been delisted is marked delisted and contains only
+// the tombstone of the VASP: its id, name and the date and reason it was delisted.
 type LookupReply struct {
-	Error                *Error   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	Vasp                 *VASP    `protobuf:"bytes,2,opt,name=vasp,proto3" json:"vasp,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Error                *Error            `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Vasp                 *VASP             `protobuf:"bytes,2,opt,name=vasp,proto3" json:"vasp,omitempty"`
+	VerificationStatus   VerificationState `protobuf:"varint,3,opt,name=verificationStatus,proto3,enum=pb.VerificationState" json:"verificationStatus,omitempty"`
+	Delisted             bool              `protobuf:"varint,4,opt,name=delisted,proto3" json:"delisted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *LookupReply) Reset()         { *m = LookupReply{} }
@@ -257,9 +440,29 @@ func (m *LookupReply) GetVasp() *VASP {
 	return nil
 }
 
+func (m *LookupReply) GetVerificationStatus() VerificationState {
+	if m != nil {
+		return m.VerificationStatus
+	}
+	return VerificationState_NO_VERIFICATION
+}
+
+func (m *LookupReply) GetDelisted() bool {
+	if m != nil {
+		return m.Delisted
+	}
+	return false
+}
+
+// SearchRequest finds VASPs that match both the name and country (if specified) as
+// well as the structured query (if specified). Results are returned in pages, to fetch
+// the next page pass the nextPageToken of the reply with an otherwise identical request.
 type SearchRequest struct {
 	Name                 []string `protobuf:"bytes,1,rep,name=name,proto3" json:"name,omitempty"`
 	Country              []string `protobuf:"bytes,2,rep,name=country,proto3" json:"country,omitempty"`
+	Query                *Query   `protobuf:"bytes,3,opt,name=query,proto3" json:"query,omitempty"`
+	PageSize             uint32   `protobuf:"varint,4,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	PageToken            string   `protobuf:"bytes,5,opt,name=pageToken,proto3" json:"pageToken,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -304,19 +507,150 @@ func (m *SearchRequest) GetCountry() []string {
 	return nil
 }
 
+func (m *SearchRequest) GetQuery() *Query {
+	if m != nil {
+		return m.Query
+	}
+	return nil
+}
+
+func (m *SearchRequest) GetPageSize() uint32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *SearchRequest) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+// Query is a structured search query. A VASP matches a field condition if it matches
+// any of the values of that field; the field conditions that are set and the nested
+// queries are combined with the operator (AND by default). Names are matched by
+// relevance (see Search), countries, categories and LEIs are matched exactly (case
+// insensitive) and domains match the host of the VASP URL or any of its subdomains.
+type Query struct {
+	Operator             Query_Operator      `protobuf:"varint,1,opt,name=operator,proto3,enum=pb.Query_Operator" json:"operator,omitempty"`
+	Name                 []string            `protobuf:"bytes,2,rep,name=name,proto3" json:"name,omitempty"`
+	Country              []string            `protobuf:"bytes,3,rep,name=country,proto3" json:"country,omitempty"`
+	Category             []string            `protobuf:"bytes,4,rep,name=category,proto3" json:"category,omitempty"`
+	Lei                  []string            `protobuf:"bytes,5,rep,name=lei,proto3" json:"lei,omitempty"`
+	Domain               []string            `protobuf:"bytes,6,rep,name=domain,proto3" json:"domain,omitempty"`
+	VerificationStatus   []VerificationState `protobuf:"varint,7,rep,packed,name=verificationStatus,proto3,enum=pb.VerificationState" json:"verificationStatus,omitempty"`
+	Certificate          CertificateValidity `protobuf:"varint,8,opt,name=certificate,proto3,enum=pb.CertificateValidity" json:"certificate,omitempty"`
+	Queries              []*Query            `protobuf:"bytes,9,rep,name=queries,proto3" json:"queries,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *Query) Reset()         { *m = Query{} }
+func (m *Query) String() string { return proto.CompactTextString(m) }
+func (*Query) ProtoMessage()    {}
+func (*Query) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{6}
+}
+
+func (m *Query) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Query.Unmarshal(m, b)
+}
+func (m *Query) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Query.Marshal(b, m, deterministic)
+}
+func (m *Query) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Query.Merge(m, src)
+}
+func (m *Query) XXX_Size() int {
+	return xxx_messageInfo_Query.Size(m)
+}
+func (m *Query) XXX_DiscardUnknown() {
+	xxx_messageInfo_Query.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Query proto.InternalMessageInfo
+
+func (m *Query) GetOperator() Query_Operator {
+	if m != nil {
+		return m.Operator
+	}
+	return Query_AND
+}
+
+func (m *Query) GetName() []string {
+	if m != nil {
+		return m.Name
+	}
+	return nil
+}
+
+func (m *Query) GetCountry() []string {
+	if m != nil {
+		return m.Country
+	}
+	return nil
+}
+
+func (m *Query) GetCategory() []string {
+	if m != nil {
+		return m.Category
+	}
+	return nil
+}
+
+func (m *Query) GetLei() []string {
+	if m != nil {
+		return m.Lei
+	}
+	return nil
+}
+
+func (m *Query) GetDomain() []string {
+	if m != nil {
+		return m.Domain
+	}
+	return nil
+}
+
+func (m *Query) GetVerificationStatus() []VerificationState {
+	if m != nil {
+		return m.VerificationStatus
+	}
+	return nil
+}
+
+func (m *Query) GetCertificate() CertificateValidity {
+	if m != nil {
+		return m.Certificate
+	}
+	return CertificateValidity_ANY_CERTIFICATE
+}
+
+func (m *Query) GetQueries() []*Query {
+	if m != nil {
+		return m.Queries
+	}
+	return nil
+}
+
 type SearchReply struct {
-	Error                *Error   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
-	Vasps                []*VASP  `protobuf:"bytes,2,rep,name=vasps,proto3" json:"vasps,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Error                *Error    `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Vasps                []*VASP   `protobuf:"bytes,2,rep,name=vasps,proto3" json:"vasps,omitempty"`
+	Scores               []float64 `protobuf:"fixed64,3,rep,packed,name=scores,proto3" json:"scores,omitempty"`
+	NextPageToken        string    `protobuf:"bytes,4,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
 func (m *SearchReply) Reset()         { *m = SearchReply{} }
 func (m *SearchReply) String() string { return proto.CompactTextString(m) }
 func (*SearchReply) ProtoMessage()    {}
 func (*SearchReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_00212fb1f9d3bf1c, []int{6}
+	return fileDescriptor_00212fb1f9d3bf1c, []int{7}
 }
 
 func (m *SearchReply) XXX_Unmarshal(b []byte) error {
@@ -351,43 +685,504 @@ func (m *SearchReply) GetVasps() []*VASP {
 	return nil
 }
 
+func (m *SearchReply) GetScores() []float64 {
+	if m != nil {
+		return m.Scores
+	}
+	return nil
+}
+
+func (m *SearchReply) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+// ListRequest streams every VASP in the directory in the specified order. Each reply
+// contains a cursor; to resume an interrupted listing, pass the cursor of the last VASP
+// that was received and the same order. Cursors expire after a day. Listings resumed
+// from a cursor do not skip or repeat VASPs that are added or removed in the meantime,
+// but the listing is not a snapshot: a VASP whose sort key changes in the meantime
+// (e.g. its last updated timestamp or name) moves in the order and may be skipped or
+// repeated. Listings ordered by ID never skip or repeat VASPs.
+type ListRequest struct {
+	Order                ListOrder `protobuf:"varint,1,opt,name=order,proto3,enum=pb.ListOrder" json:"order,omitempty"`
+	Descending           bool      `protobuf:"varint,2,opt,name=descending,proto3" json:"descending,omitempty"`
+	Cursor               string    `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit                uint32    `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ListRequest) Reset()         { *m = ListRequest{} }
+func (m *ListRequest) String() string { return proto.CompactTextString(m) }
+func (*ListRequest) ProtoMessage()    {}
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{8}
+}
+
+func (m *ListRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListRequest.Unmarshal(m, b)
+}
+func (m *ListRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListRequest.Marshal(b, m, deterministic)
+}
+func (m *ListRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListRequest.Merge(m, src)
+}
+func (m *ListRequest) XXX_Size() int {
+	return xxx_messageInfo_ListRequest.Size(m)
+}
+func (m *ListRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListRequest proto.InternalMessageInfo
+
+func (m *ListRequest) GetOrder() ListOrder {
+	if m != nil {
+		return m.Order
+	}
+	return ListOrder_ORDER_BY_ID
+}
+
+func (m *ListRequest) GetDescending() bool {
+	if m != nil {
+		return m.Descending
+	}
+	return false
+}
+
+func (m *ListRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *ListRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type ListReply struct {
+	Vasp                 *VASP    `protobuf:"bytes,1,opt,name=vasp,proto3" json:"vasp,omitempty"`
+	Cursor               string   `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListReply) Reset()         { *m = ListReply{} }
+func (m *ListReply) String() string { return proto.CompactTextString(m) }
+func (*ListReply) ProtoMessage()    {}
+func (*ListReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{9}
+}
+
+func (m *ListReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListReply.Unmarshal(m, b)
+}
+func (m *ListReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListReply.Marshal(b, m, deterministic)
+}
+func (m *ListReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListReply.Merge(m, src)
+}
+func (m *ListReply) XXX_Size() int {
+	return xxx_messageInfo_ListReply.Size(m)
+}
+func (m *ListReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListReply proto.InternalMessageInfo
+
+func (m *ListReply) GetVasp() *VASP {
+	if m != nil {
+		return m.Vasp
+	}
+	return nil
+}
+
+func (m *ListReply) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+type VerifyEmailRequest struct {
+	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Token                string   `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerifyEmailRequest) Reset()         { *m = VerifyEmailRequest{} }
+func (m *VerifyEmailRequest) String() string { return proto.CompactTextString(m) }
+func (*VerifyEmailRequest) ProtoMessage()    {}
+func (*VerifyEmailRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{10}
+}
+
+func (m *VerifyEmailRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyEmailRequest.Unmarshal(m, b)
+}
+func (m *VerifyEmailRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyEmailRequest.Marshal(b, m, deterministic)
+}
+func (m *VerifyEmailRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyEmailRequest.Merge(m, src)
+}
+func (m *VerifyEmailRequest) XXX_Size() int {
+	return xxx_messageInfo_VerifyEmailRequest.Size(m)
+}
+func (m *VerifyEmailRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyEmailRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyEmailRequest proto.InternalMessageInfo
+
+func (m *VerifyEmailRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *VerifyEmailRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type VerifyEmailReply struct {
+	Error                *Error   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Id                   uint64   `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerifyEmailReply) Reset()         { *m = VerifyEmailReply{} }
+func (m *VerifyEmailReply) String() string { return proto.CompactTextString(m) }
+func (*VerifyEmailReply) ProtoMessage()    {}
+func (*VerifyEmailReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{11}
+}
+
+func (m *VerifyEmailReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyEmailReply.Unmarshal(m, b)
+}
+func (m *VerifyEmailReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyEmailReply.Marshal(b, m, deterministic)
+}
+func (m *VerifyEmailReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyEmailReply.Merge(m, src)
+}
+func (m *VerifyEmailReply) XXX_Size() int {
+	return xxx_messageInfo_VerifyEmailReply.Size(m)
+}
+func (m *VerifyEmailReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyEmailReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyEmailReply proto.InternalMessageInfo
+
+func (m *VerifyEmailReply) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *VerifyEmailReply) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+// VerifyCertificateRequest identifies a TRISA certificate, e.g. of the peer of an mTLS
+// connection, either by the certificate itself (PEM or DER encoded, optionally followed
+// by intermediate certificates in PEM format) or by its serial number in hex.
+type VerifyCertificateRequest struct {
+	// Types that are valid to be assigned to Query:
+	//	*VerifyCertificateRequest_Certificate
+	//	*VerifyCertificateRequest_SerialNumber
+	Query                isVerifyCertificateRequest_Query `protobuf_oneof:"query"`
+	XXX_NoUnkeyedLiteral struct{}                         `json:"-"`
+	XXX_unrecognized     []byte                           `json:"-"`
+	XXX_sizecache        int32                            `json:"-"`
+}
+
+func (m *VerifyCertificateRequest) Reset()         { *m = VerifyCertificateRequest{} }
+func (m *VerifyCertificateRequest) String() string { return proto.CompactTextString(m) }
+func (*VerifyCertificateRequest) ProtoMessage()    {}
+func (*VerifyCertificateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{12}
+}
+
+func (m *VerifyCertificateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyCertificateRequest.Unmarshal(m, b)
+}
+func (m *VerifyCertificateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyCertificateRequest.Marshal(b, m, deterministic)
+}
+func (m *VerifyCertificateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyCertificateRequest.Merge(m, src)
+}
+func (m *VerifyCertificateRequest) XXX_Size() int {
+	return xxx_messageInfo_VerifyCertificateRequest.Size(m)
+}
+func (m *VerifyCertificateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyCertificateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyCertificateRequest proto.InternalMessageInfo
+
+type isVerifyCertificateRequest_Query interface {
+	isVerifyCertificateRequest_Query()
+}
+
+type VerifyCertificateRequest_Certificate struct {
+	Certificate []byte `protobuf:"bytes,1,opt,name=certificate,proto3,oneof"`
+}
+
+type VerifyCertificateRequest_SerialNumber struct {
+	SerialNumber string `protobuf:"bytes,2,opt,name=serialNumber,proto3,oneof"`
+}
+
+func (*VerifyCertificateRequest_Certificate) isVerifyCertificateRequest_Query() {}
+
+func (*VerifyCertificateRequest_SerialNumber) isVerifyCertificateRequest_Query() {}
+
+func (m *VerifyCertificateRequest) GetQuery() isVerifyCertificateRequest_Query {
+	if m != nil {
+		return m.Query
+	}
+	return nil
+}
+
+func (m *VerifyCertificateRequest) GetCertificate() []byte {
+	if x, ok := m.GetQuery().(*VerifyCertificateRequest_Certificate); ok {
+		return x.Certificate
+	}
+	return nil
+}
+
+func (m *VerifyCertificateRequest) GetSerialNumber() string {
+	if x, ok := m.GetQuery().(*VerifyCertificateRequest_SerialNumber); ok {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*VerifyCertificateRequest) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*VerifyCertificateRequest_Certificate)(nil),
+		(*VerifyCertificateRequest_SerialNumber)(nil),
+	}
+}
+
+// VerifyCertificateReply describes the VASP the certificate was issued to. The
+// certificate is valid if it was issued to a verified VASP, has not been revoked, and the
+// current time is within its validity window; if a certificate was given it must also be
+// the certificate that was issued to the VASP and be signed by a trusted TRISA authority.
+type VerifyCertificateReply struct {
+	Error                *Error            `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	Vasp                 *VASP             `protobuf:"bytes,2,opt,name=vasp,proto3" json:"vasp,omitempty"`
+	VerificationStatus   VerificationState `protobuf:"varint,3,opt,name=verificationStatus,proto3,enum=pb.VerificationState" json:"verificationStatus,omitempty"`
+	Revoked              bool              `protobuf:"varint,4,opt,name=revoked,proto3" json:"revoked,omitempty"`
+	NotValidBefore       string            `protobuf:"bytes,5,opt,name=notValidBefore,proto3" json:"notValidBefore,omitempty"`
+	NotValidAfter        string            `protobuf:"bytes,6,opt,name=notValidAfter,proto3" json:"notValidAfter,omitempty"`
+	Valid                bool              `protobuf:"varint,7,opt,name=valid,proto3" json:"valid,omitempty"`
+	Reason               string            `protobuf:"bytes,8,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *VerifyCertificateReply) Reset()         { *m = VerifyCertificateReply{} }
+func (m *VerifyCertificateReply) String() string { return proto.CompactTextString(m) }
+func (*VerifyCertificateReply) ProtoMessage()    {}
+func (*VerifyCertificateReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_00212fb1f9d3bf1c, []int{13}
+}
+
+func (m *VerifyCertificateReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyCertificateReply.Unmarshal(m, b)
+}
+func (m *VerifyCertificateReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyCertificateReply.Marshal(b, m, deterministic)
+}
+func (m *VerifyCertificateReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyCertificateReply.Merge(m, src)
+}
+func (m *VerifyCertificateReply) XXX_Size() int {
+	return xxx_messageInfo_VerifyCertificateReply.Size(m)
+}
+func (m *VerifyCertificateReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyCertificateReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyCertificateReply proto.InternalMessageInfo
+
+func (m *VerifyCertificateReply) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *VerifyCertificateReply) GetVasp() *VASP {
+	if m != nil {
+		return m.Vasp
+	}
+	return nil
+}
+
+func (m *VerifyCertificateReply) GetVerificationStatus() VerificationState {
+	if m != nil {
+		return m.VerificationStatus
+	}
+	return VerificationState_NO_VERIFICATION
+}
+
+func (m *VerifyCertificateReply) GetRevoked() bool {
+	if m != nil {
+		return m.Revoked
+	}
+	return false
+}
+
+func (m *VerifyCertificateReply) GetNotValidBefore() string {
+	if m != nil {
+		return m.NotValidBefore
+	}
+	return ""
+}
+
+func (m *VerifyCertificateReply) GetNotValidAfter() string {
+	if m != nil {
+		return m.NotValidAfter
+	}
+	return ""
+}
+
+func (m *VerifyCertificateReply) GetValid() bool {
+	if m != nil {
+		return m.Valid
+	}
+	return false
+}
+
+func (m *VerifyCertificateReply) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
 func init() {
+	proto.RegisterEnum("pb.CertificateValidity", CertificateValidity_name, CertificateValidity_value)
+	proto.RegisterEnum("pb.ListOrder", ListOrder_name, ListOrder_value)
+	proto.RegisterEnum("pb.Query_Operator", Query_Operator_name, Query_Operator_value)
 	proto.RegisterType((*Error)(nil), "pb.Error")
 	proto.RegisterType((*RegisterRequest)(nil), "pb.RegisterRequest")
 	proto.RegisterType((*RegisterReply)(nil), "pb.RegisterReply")
 	proto.RegisterType((*LookupRequest)(nil), "pb.LookupRequest")
 	proto.RegisterType((*LookupReply)(nil), "pb.LookupReply")
 	proto.RegisterType((*SearchRequest)(nil), "pb.SearchRequest")
+	proto.RegisterType((*Query)(nil), "pb.Query")
 	proto.RegisterType((*SearchReply)(nil), "pb.SearchReply")
+	proto.RegisterType((*ListRequest)(nil), "pb.ListRequest")
+	proto.RegisterType((*ListReply)(nil), "pb.ListReply")
+	proto.RegisterType((*VerifyEmailRequest)(nil), "pb.VerifyEmailRequest")
+	proto.RegisterType((*VerifyEmailReply)(nil), "pb.VerifyEmailReply")
+	proto.RegisterType((*VerifyCertificateRequest)(nil), "pb.VerifyCertificateRequest")
+	proto.RegisterType((*VerifyCertificateReply)(nil), "pb.VerifyCertificateReply")
 }
 
 func init() { proto.RegisterFile("api.proto", fileDescriptor_00212fb1f9d3bf1c) }
 
 var fileDescriptor_00212fb1f9d3bf1c = []byte{
-	// 356 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x52, 0x41, 0x4f, 0xf2, 0x40,
-	0x10, 0xa5, 0xfd, 0x68, 0x3f, 0x98, 0x0a, 0xc4, 0x35, 0x31, 0x84, 0x18, 0x25, 0x7b, 0xe2, 0xd4,
-	0x03, 0xe8, 0xd1, 0x44, 0x12, 0x39, 0x98, 0xa0, 0x31, 0x8b, 0xf1, 0x5e, 0xda, 0x11, 0x1b, 0x81,
-	0x5d, 0x77, 0x17, 0x92, 0xfe, 0x21, 0x7f, 0xa7, 0xd9, 0x5d, 0x56, 0xc1, 0x13, 0xb7, 0x9d, 0x37,
-	0x6f, 0xde, 0xbc, 0x79, 0x2d, 0x34, 0x33, 0x51, 0xa6, 0x42, 0x72, 0xcd, 0x49, 0x28, 0xe6, 0xbd,
-	0x93, 0x15, 0x2f, 0x70, 0xa9, 0x1c, 0x42, 0x6f, 0x20, 0x9a, 0x48, 0xc9, 0x25, 0x21, 0x50, 0xcf,
-	0x79, 0x81, 0xdd, 0xa0, 0x1f, 0x0c, 0x22, 0x66, 0xdf, 0xa4, 0x0b, 0xff, 0x57, 0xa8, 0x54, 0xb6,
-	0xc0, 0x6e, 0xd8, 0x0f, 0x06, 0x4d, 0xe6, 0x4b, 0xfa, 0x08, 0x1d, 0x86, 0x8b, 0x52, 0x69, 0x94,
-	0x0c, 0x3f, 0x37, 0xa8, 0x34, 0xa1, 0x10, 0xe3, 0x5a, 0x97, 0xba, 0xb2, 0x12, 0xc9, 0x10, 0x52,
-	0x31, 0x4f, 0x27, 0x16, 0x61, 0xbb, 0x0e, 0x39, 0x87, 0x78, 0x8b, 0xb2, 0x7c, 0xab, 0xac, 0x5e,
-	0x83, 0xed, 0x2a, 0x7a, 0x07, 0xad, 0x5f, 0x39, 0xb1, 0xac, 0xc8, 0x15, 0x44, 0x68, 0x6c, 0xed,
-	0xb4, 0x9a, 0x56, 0xcb, 0x00, 0xcc, 0xe1, 0xa4, 0x0d, 0x61, 0x59, 0x58, 0x95, 0x3a, 0x0b, 0xcb,
-	0x82, 0x8e, 0xa0, 0x35, 0xe5, 0xfc, 0x63, 0x23, 0xbc, 0x1d, 0x47, 0x08, 0x3c, 0xc1, 0xdc, 0xb7,
-	0xce, 0x56, 0xfe, 0x10, 0xfb, 0xa6, 0x53, 0x48, 0xfc, 0xd0, 0x51, 0x4b, 0x2f, 0xa0, 0xbe, 0xcd,
-	0x94, 0xb0, 0x1a, 0xc9, 0xb0, 0x61, 0xfa, 0xaf, 0xe3, 0xd9, 0x33, 0xb3, 0x28, 0xbd, 0x85, 0xd6,
-	0x0c, 0x33, 0x99, 0xbf, 0x7b, 0x0b, 0x7e, 0x65, 0xd0, 0xff, 0xe7, 0x57, 0x9a, 0x48, 0x73, 0xbe,
-	0x59, 0x6b, 0x69, 0x22, 0x30, 0xb0, 0x2f, 0xe9, 0x13, 0x24, 0x7e, 0xfc, 0x28, 0x33, 0x97, 0x10,
-	0x99, 0xb5, 0xca, 0xea, 0xec, 0xbb, 0x71, 0xf0, 0xf0, 0x2b, 0x80, 0xf6, 0x0b, 0x7b, 0x98, 0x8d,
-	0xef, 0x4b, 0x89, 0xb9, 0xe6, 0xb2, 0x22, 0xd7, 0xd0, 0xf0, 0x31, 0x93, 0x33, 0xc3, 0xff, 0xf3,
-	0x0d, 0x7b, 0xa7, 0x87, 0xa0, 0x58, 0x56, 0xb4, 0x46, 0x52, 0x88, 0x5d, 0x4a, 0xc4, 0xb6, 0x0f,
-	0x62, 0xee, 0x75, 0xf6, 0xa1, 0x1f, 0xbe, 0x3b, 0xc4, 0xf1, 0x0f, 0x32, 0x71, 0xfc, 0xbd, 0x3b,
-	0x69, 0x6d, 0x1e, 0xdb, 0x3f, 0x71, 0xf4, 0x1d, 0x00, 0x00, 0xff, 0xff, 0xd0, 0x81, 0x08, 0x06,
-	0xa8, 0x02, 0x00, 0x00,
+	// 1069 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x56, 0xcd, 0x6e, 0xdb, 0x46,
+	0x10, 0x16, 0x29, 0x51, 0x3f, 0x23, 0xeb, 0xc7, 0xe3, 0x9f, 0xb0, 0xaa, 0x91, 0x08, 0x4c, 0x50,
+	0x18, 0x3e, 0x08, 0xad, 0xda, 0x1e, 0x5a, 0xa0, 0x07, 0xda, 0x52, 0x60, 0x01, 0x8e, 0xe4, 0xae,
+	0x54, 0x03, 0x39, 0x19, 0x34, 0xb9, 0x76, 0x08, 0x4b, 0x5c, 0x66, 0x49, 0xb9, 0x55, 0x4f, 0x7d,
+	0x80, 0x1e, 0x7a, 0xec, 0x5b, 0xf4, 0xd2, 0x27, 0xea, 0x0b, 0xf4, 0x15, 0x8a, 0x5d, 0x2e, 0x29,
+	0xd1, 0x56, 0x80, 0xb4, 0xa7, 0xde, 0xf8, 0x7d, 0x33, 0x9c, 0xdd, 0x99, 0xf9, 0x66, 0x48, 0xa8,
+	0x39, 0xa1, 0xdf, 0x0b, 0x39, 0x8b, 0x19, 0xea, 0xe1, 0x4d, 0x67, 0x67, 0xc1, 0x3c, 0x3a, 0x8f,
+	0x12, 0xc6, 0xfa, 0x1a, 0x8c, 0x21, 0xe7, 0x8c, 0x23, 0x42, 0xc9, 0x65, 0x1e, 0x35, 0xb5, 0xae,
+	0x76, 0x6c, 0x10, 0xf9, 0x8c, 0x26, 0x54, 0x16, 0x34, 0x8a, 0x9c, 0x3b, 0x6a, 0xea, 0x5d, 0xed,
+	0xb8, 0x46, 0x52, 0x68, 0xbd, 0x81, 0x16, 0xa1, 0x77, 0x7e, 0x14, 0x53, 0x4e, 0xe8, 0xfb, 0x25,
+	0x8d, 0x62, 0xb4, 0xa0, 0x4c, 0x83, 0xd8, 0x8f, 0x57, 0x32, 0x44, 0xbd, 0x0f, 0xbd, 0xf0, 0xa6,
+	0x37, 0x94, 0x0c, 0x51, 0x16, 0x3c, 0x84, 0xf2, 0x03, 0xe5, 0xfe, 0xed, 0x4a, 0xc6, 0xab, 0x12,
+	0x85, 0xac, 0x77, 0xd0, 0x58, 0x87, 0x0b, 0xe7, 0x2b, 0x7c, 0x01, 0x06, 0x15, 0xd7, 0x52, 0xb1,
+	0x6a, 0x32, 0x96, 0x20, 0x48, 0xc2, 0x63, 0x13, 0x74, 0xdf, 0x93, 0x51, 0x4a, 0x44, 0xf7, 0x3d,
+	0xfc, 0x0c, 0x9a, 0xe1, 0xbd, 0x1b, 0x7d, 0xd1, 0xbf, 0x74, 0xa2, 0xe8, 0x47, 0xc6, 0x3d, 0xb3,
+	0x28, 0x6f, 0xfc, 0x88, 0xb5, 0x7e, 0xd3, 0xa0, 0x71, 0xc1, 0xd8, 0xfd, 0x32, 0x4c, 0xef, 0xdd,
+	0x96, 0x91, 0xc4, 0x39, 0xa5, 0xf3, 0x82, 0x8c, 0xb5, 0x0f, 0xa5, 0xc0, 0x59, 0xa8, 0x9c, 0xcf,
+	0x0b, 0x44, 0x22, 0x44, 0x28, 0xce, 0xa9, 0x9f, 0x84, 0x3d, 0x2f, 0x10, 0x01, 0xd0, 0x84, 0xb2,
+	0xc7, 0x16, 0x8e, 0x1f, 0x98, 0x25, 0x45, 0x2b, 0x8c, 0x5d, 0x00, 0x97, 0x2d, 0x16, 0x2c, 0x18,
+	0x8b, 0x48, 0x86, 0xb2, 0x6e, 0x70, 0xa7, 0x15, 0x30, 0xde, 0x2f, 0x29, 0x5f, 0x59, 0x7f, 0x68,
+	0x50, 0x4f, 0xaf, 0xf4, 0x51, 0xb9, 0x1f, 0x41, 0xe9, 0xc1, 0x89, 0x42, 0x79, 0xbf, 0x7a, 0xbf,
+	0x2a, 0xec, 0x57, 0xf6, 0xf4, 0x92, 0x48, 0x16, 0x87, 0x80, 0xb2, 0xaa, 0xbe, 0xeb, 0xc4, 0x3e,
+	0x0b, 0xa6, 0xb1, 0x13, 0x2f, 0x23, 0x79, 0xed, 0x66, 0xff, 0x40, 0xfa, 0x3e, 0xb2, 0x52, 0xb2,
+	0xe5, 0x05, 0xec, 0x40, 0xd5, 0xa3, 0x73, 0xd1, 0x12, 0x4f, 0x26, 0x57, 0x25, 0x19, 0xb6, 0x7e,
+	0xd7, 0xa0, 0x31, 0xa5, 0x0e, 0x77, 0xdf, 0xa5, 0x45, 0x44, 0x55, 0x32, 0xad, 0x5b, 0x3c, 0xae,
+	0xa9, 0x82, 0x99, 0x50, 0x71, 0xd9, 0x32, 0x88, 0xb9, 0xe8, 0xb6, 0xa0, 0x53, 0x88, 0x2f, 0x54,
+	0xea, 0x66, 0x71, 0x9d, 0xe1, 0xf7, 0x82, 0x20, 0x09, 0x2f, 0x0e, 0x0f, 0x9d, 0x3b, 0x3a, 0xf5,
+	0x7f, 0xa6, 0xf2, 0xf0, 0x06, 0xc9, 0x30, 0x1e, 0x41, 0x4d, 0x3c, 0xcf, 0xd8, 0x3d, 0x0d, 0x92,
+	0xc2, 0x92, 0x35, 0x61, 0xfd, 0xad, 0x83, 0x21, 0x43, 0x61, 0x0f, 0xaa, 0x2c, 0xa4, 0xdc, 0x89,
+	0x55, 0x25, 0x9b, 0x7d, 0xcc, 0xce, 0xe9, 0x4d, 0x94, 0x85, 0x64, 0x3e, 0x59, 0x0a, 0xfa, 0xf6,
+	0x14, 0x8a, 0xf9, 0x14, 0x3a, 0x50, 0x75, 0x9d, 0x98, 0xde, 0x31, 0xbe, 0x32, 0x4b, 0xd2, 0x94,
+	0x61, 0x6c, 0x27, 0x4a, 0x31, 0x24, 0x2d, 0x1e, 0x85, 0xee, 0x95, 0x4e, 0xca, 0x92, 0x54, 0xe8,
+	0x03, 0xbd, 0xaa, 0x74, 0x8b, 0xff, 0xae, 0x57, 0xdf, 0x40, 0xdd, 0xa5, 0x3c, 0x4e, 0x68, 0x6a,
+	0x56, 0x65, 0xb6, 0xcf, 0xc4, 0xfb, 0x67, 0x6b, 0xfa, 0xca, 0x99, 0xfb, 0x9e, 0x18, 0xc6, 0x4d,
+	0x5f, 0x7c, 0x09, 0x15, 0x51, 0x72, 0x9f, 0x46, 0x66, 0xad, 0x5b, 0xcc, 0x37, 0x23, 0xb5, 0x58,
+	0x9f, 0x42, 0x35, 0x2d, 0x18, 0x56, 0xa0, 0x68, 0x8f, 0x07, 0xed, 0x02, 0x96, 0x41, 0x9f, 0x90,
+	0xb6, 0x66, 0xfd, 0xaa, 0x41, 0x3d, 0x15, 0xc3, 0x47, 0xc9, 0xf7, 0x39, 0x18, 0x42, 0xa8, 0x91,
+	0xac, 0xf4, 0xa6, 0x7e, 0x13, 0x5a, 0x14, 0x2b, 0x72, 0x19, 0xa7, 0x91, 0xac, 0xb9, 0x46, 0x14,
+	0xc2, 0x57, 0xd0, 0x08, 0xe8, 0x4f, 0xf1, 0x65, 0xd6, 0x7c, 0x39, 0x73, 0x24, 0x4f, 0x5a, 0xbf,
+	0x88, 0x69, 0xf2, 0xa3, 0x38, 0x55, 0xe6, 0x4b, 0x30, 0x18, 0xf7, 0x68, 0xaa, 0x81, 0x86, 0x38,
+	0x4d, 0xd8, 0x27, 0x82, 0x24, 0x89, 0x0d, 0x9f, 0x03, 0x78, 0x34, 0x72, 0x69, 0xe0, 0xf9, 0xc1,
+	0x9d, 0xda, 0x4d, 0x1b, 0x8c, 0xb8, 0x92, 0xbb, 0xe4, 0x11, 0xe3, 0x6a, 0xab, 0x28, 0x84, 0xfb,
+	0x60, 0xcc, 0xfd, 0x85, 0x1f, 0x2b, 0x91, 0x26, 0xc0, 0xb2, 0xa1, 0x96, 0xdc, 0x40, 0x94, 0x23,
+	0x1d, 0x56, 0x6d, 0xeb, 0xb0, 0xae, 0x03, 0xeb, 0x9b, 0x81, 0xad, 0x6f, 0x01, 0x65, 0xeb, 0x57,
+	0xc3, 0x85, 0xe3, 0xcf, 0xd3, 0x5c, 0x9a, 0xeb, 0x55, 0xa5, 0x16, 0x95, 0x11, 0xcb, 0x4a, 0x24,
+	0x2f, 0x27, 0xc0, 0x3a, 0x83, 0x76, 0xee, 0xdd, 0xff, 0xb2, 0x4f, 0xad, 0x05, 0x98, 0x49, 0x90,
+	0x0d, 0x05, 0xad, 0x37, 0x7d, 0x4e, 0x6e, 0x22, 0xe4, 0xce, 0x79, 0x21, 0xaf, 0xab, 0x57, 0xb0,
+	0x13, 0x51, 0xee, 0x3b, 0xf3, 0xf1, 0x72, 0x71, 0x43, 0x79, 0xb6, 0x4b, 0x73, 0xec, 0x7a, 0x07,
+	0xfe, 0xa9, 0xc3, 0xe1, 0x96, 0xf3, 0xfe, 0x3f, 0xeb, 0xd0, 0x84, 0x0a, 0xa7, 0x0f, 0xec, 0x3e,
+	0xdb, 0x86, 0x29, 0x14, 0x5f, 0x9e, 0x80, 0xc5, 0x72, 0xba, 0x4e, 0xe9, 0x2d, 0xe3, 0x6a, 0xdb,
+	0x93, 0x47, 0xac, 0x94, 0xaf, 0x62, 0xec, 0xdb, 0x98, 0x72, 0xb3, 0xac, 0xe4, 0xbb, 0x49, 0x8a,
+	0x96, 0x3e, 0x08, 0x64, 0x56, 0xe4, 0x29, 0x09, 0x10, 0x32, 0xe1, 0xd4, 0x89, 0x58, 0x20, 0x67,
+	0xbb, 0x46, 0x14, 0x3a, 0x09, 0x60, 0x6f, 0xcb, 0x84, 0xe3, 0x1e, 0xb4, 0xec, 0xf1, 0xdb, 0xeb,
+	0xb3, 0x21, 0x99, 0x8d, 0x5e, 0x8f, 0xce, 0xec, 0xd9, 0xb0, 0x5d, 0xc0, 0x03, 0xd8, 0xbd, 0xb2,
+	0x2f, 0x46, 0x83, 0x1c, 0xad, 0xe1, 0x33, 0xd8, 0x1b, 0x8d, 0x9f, 0x1a, 0x74, 0x44, 0x68, 0x8e,
+	0x27, 0x39, 0xae, 0x78, 0x42, 0xa1, 0x96, 0xcd, 0x0e, 0xb6, 0xa0, 0x3e, 0x21, 0x83, 0x21, 0xb9,
+	0x3e, 0x7d, 0x7b, 0x3d, 0x12, 0x1b, 0x61, 0x17, 0x1a, 0x19, 0x31, 0xb6, 0xdf, 0x88, 0xe8, 0x9f,
+	0xc0, 0x41, 0x46, 0xbd, 0x1e, 0x91, 0xe9, 0xec, 0xfa, 0x62, 0x34, 0x9d, 0x0d, 0x07, 0x6d, 0x3d,
+	0x67, 0xba, 0xb0, 0xa7, 0xb3, 0xeb, 0x1f, 0x2e, 0x07, 0xb6, 0x30, 0x15, 0xfb, 0x7f, 0xe9, 0xd0,
+	0x9c, 0x91, 0xd1, 0xd4, 0x1e, 0xf8, 0x9c, 0xba, 0xb1, 0xd8, 0xa9, 0x5f, 0x41, 0x35, 0xfd, 0x43,
+	0xc0, 0x3d, 0xd1, 0xb6, 0x47, 0xbf, 0x1f, 0x9d, 0xdd, 0x3c, 0x19, 0xce, 0x57, 0x56, 0x01, 0x7b,
+	0x50, 0x4e, 0xbe, 0xac, 0x28, 0xcd, 0xb9, 0x0f, 0x7f, 0xa7, 0xb5, 0x49, 0x65, 0xfe, 0xc9, 0x2a,
+	0x4b, 0xfc, 0x73, 0xdf, 0xb8, 0x4e, 0x6b, 0x93, 0x4a, 0xfc, 0x4f, 0xa0, 0x24, 0xea, 0x81, 0xad,
+	0x74, 0xab, 0xa4, 0xbe, 0x8d, 0x35, 0x21, 0x3d, 0x3f, 0xd7, 0xf0, 0x3b, 0xa8, 0x6f, 0x8c, 0x25,
+	0x1e, 0x66, 0xda, 0xcb, 0xcd, 0x78, 0x67, 0xff, 0x09, 0x9f, 0x1c, 0x35, 0x81, 0xdd, 0x27, 0x03,
+	0x82, 0x47, 0x6b, 0xe7, 0xa7, 0x73, 0xda, 0xe9, 0x7c, 0xc0, 0x2a, 0x03, 0xde, 0x94, 0xe5, 0x0f,
+	0xe0, 0x97, 0xff, 0x0c, 0x00, 0x11, 0xec, 0xa0, 0x38, 0x1f, 0x0a, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -405,6 +1200,9 @@ type TRISADirectoryClient interface {
 	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterReply, error)
 	Lookup(ctx context.Context, in *LookupRequest, opts ...grpc.CallOption) (*LookupReply, error)
 	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchReply, error)
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (TRISADirectory_ListClient, error)
+	VerifyEmail(ctx context.Context, in *VerifyEmailRequest, opts ...grpc.CallOption) (*VerifyEmailReply, error)
+	VerifyCertificate(ctx context.Context, in *VerifyCertificateRequest, opts ...grpc.CallOption) (*VerifyCertificateReply, error)
 }
 
 type tRISADirectoryClient struct {
@@ -442,11 +1240,64 @@ func (c *tRISADirectoryClient) Search(ctx context.Context, in *SearchRequest, op
 	return out, nil
 }
 
+func (c *tRISADirectoryClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (TRISADirectory_ListClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TRISADirectory_serviceDesc.Streams[0], "/pb.TRISADirectory/List", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tRISADirectoryListClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TRISADirectory_ListClient interface {
+	Recv() (*ListReply, error)
+	grpc.ClientStream
+}
+
+type tRISADirectoryListClient struct {
+	grpc.ClientStream
+}
+
+func (x *tRISADirectoryListClient) Recv() (*ListReply, error) {
+	m := new(ListReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tRISADirectoryClient) VerifyEmail(ctx context.Context, in *VerifyEmailRequest, opts ...grpc.CallOption) (*VerifyEmailReply, error) {
+	out := new(VerifyEmailReply)
+	err := c.cc.Invoke(ctx, "/pb.TRISADirectory/VerifyEmail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tRISADirectoryClient) VerifyCertificate(ctx context.Context, in *VerifyCertificateRequest, opts ...grpc.CallOption) (*VerifyCertificateReply, error) {
+	out := new(VerifyCertificateReply)
+	err := c.cc.Invoke(ctx, "/pb.TRISADirectory/VerifyCertificate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TRISADirectoryServer is the server API for TRISADirectory service.
 type TRISADirectoryServer interface {
 	Register(context.Context, *RegisterRequest) (*RegisterReply, error)
 	Lookup(context.Context, *LookupRequest) (*LookupReply, error)
 	Search(context.Context, *SearchRequest) (*SearchReply, error)
+	List(*ListRequest, TRISADirectory_ListServer) error
+	VerifyEmail(context.Context, *VerifyEmailRequest) (*VerifyEmailReply, error)
+	VerifyCertificate(context.Context, *VerifyCertificateRequest) (*VerifyCertificateReply, error)
 }
 
 func RegisterTRISADirectoryServer(s *grpc.Server, srv TRISADirectoryServer) {
@@ -507,6 +1358,63 @@ func _TRISADirectory_Search_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TRISADirectory_List_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TRISADirectoryServer).List(m, &tRISADirectoryListServer{stream})
+}
+
+type TRISADirectory_ListServer interface {
+	Send(*ListReply) error
+	grpc.ServerStream
+}
+
+type tRISADirectoryListServer struct {
+	grpc.ServerStream
+}
+
+func (x *tRISADirectoryListServer) Send(m *ListReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TRISADirectory_VerifyEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyEmailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TRISADirectoryServer).VerifyEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.TRISADirectory/VerifyEmail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TRISADirectoryServer).VerifyEmail(ctx, req.(*VerifyEmailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TRISADirectory_VerifyCertificate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyCertificateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TRISADirectoryServer).VerifyCertificate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.TRISADirectory/VerifyCertificate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TRISADirectoryServer).VerifyCertificate(ctx, req.(*VerifyCertificateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _TRISADirectory_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.TRISADirectory",
 	HandlerType: (*TRISADirectoryServer)(nil),
@@ -523,7 +1431,21 @@ var _TRISADirectory_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Search",
 			Handler:    _TRISADirectory_Search_Handler,
 		},
+		{
+			MethodName: "VerifyEmail",
+			Handler:    _TRISADirectory_VerifyEmail_Handler,
+		},
+		{
+			MethodName: "VerifyCertificate",
+			Handler:    _TRISADirectory_VerifyCertificate_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "List",
+			Handler:       _TRISADirectory_List_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api.proto",
 }
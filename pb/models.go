@@ -0,0 +1,58 @@
+package pb
+
+// transitions defines the legal moves of the verification state machine. A VASP may
+// always "transition" to the state it is already in (e.g. when editing other fields).
+var transitions = map[VerificationState][]VerificationState{
+	VerificationState_NO_VERIFICATION: {
+		VerificationState_SUBMITTED,
+	},
+	VerificationState_SUBMITTED: {
+		VerificationState_EMAILED, VerificationState_REJECTED, VerificationState_ERRORED,
+	},
+	VerificationState_EMAILED: {
+		VerificationState_PENDING_REVIEW, VerificationState_REJECTED, VerificationState_ERRORED,
+	},
+	VerificationState_PENDING_REVIEW: {
+		VerificationState_REVIEWED, VerificationState_REJECTED, VerificationState_ERRORED,
+	},
+	VerificationState_REVIEWED: {
+		VerificationState_ISSUING_CERTIFICATE, VerificationState_REJECTED, VerificationState_ERRORED,
+	},
+	VerificationState_ISSUING_CERTIFICATE: {
+		VerificationState_VERIFIED, VerificationState_ERRORED,
+	},
+	VerificationState_VERIFIED: {
+		VerificationState_ISSUING_CERTIFICATE, VerificationState_REVOKING,
+	},
+	VerificationState_REVOKING: {
+		VerificationState_REVOKED,
+	},
+	VerificationState_REJECTED: {},
+	VerificationState_REVOKED:  {},
+	VerificationState_DELISTED: {},
+	VerificationState_ERRORED: {
+		VerificationState_SUBMITTED, VerificationState_EMAILED, VerificationState_PENDING_REVIEW,
+		VerificationState_REVIEWED, VerificationState_ISSUING_CERTIFICATE, VerificationState_REJECTED,
+	},
+}
+
+// CanTransition returns true if a VASP in the current state may be moved to the
+// specified state in the verification workflow. A VASP in any state may be delisted,
+// but a delisted VASP remains a tombstone until it is purged.
+func (s VerificationState) CanTransition(to VerificationState) bool {
+	if s == to || to == VerificationState_DELISTED {
+		return true
+	}
+
+	for _, state := range transitions[s] {
+		if state == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Initial returns true if a VASP record may be created in the specified state.
+func (s VerificationState) Initial() bool {
+	return s == VerificationState_NO_VERIFICATION || s == VerificationState_SUBMITTED
+}
@@ -20,15 +20,147 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type VerificationState int32
+
+const (
+	VerificationState_NO_VERIFICATION     VerificationState = 0
+	VerificationState_SUBMITTED           VerificationState = 1
+	VerificationState_EMAILED             VerificationState = 2
+	VerificationState_PENDING_REVIEW      VerificationState = 3
+	VerificationState_REVIEWED            VerificationState = 4
+	VerificationState_ISSUING_CERTIFICATE VerificationState = 5
+	VerificationState_VERIFIED            VerificationState = 6
+	VerificationState_REJECTED            VerificationState = 7
+	VerificationState_REVOKED             VerificationState = 8
+	VerificationState_ERRORED             VerificationState = 9
+	VerificationState_DELISTED            VerificationState = 10
+	VerificationState_REVOKING            VerificationState = 11
+)
+
+var VerificationState_name = map[int32]string{
+	0:  "NO_VERIFICATION",
+	1:  "SUBMITTED",
+	2:  "EMAILED",
+	3:  "PENDING_REVIEW",
+	4:  "REVIEWED",
+	5:  "ISSUING_CERTIFICATE",
+	6:  "VERIFIED",
+	7:  "REJECTED",
+	8:  "REVOKED",
+	9:  "ERRORED",
+	10: "DELISTED",
+	11: "REVOKING",
+}
+
+var VerificationState_value = map[string]int32{
+	"NO_VERIFICATION":     0,
+	"SUBMITTED":           1,
+	"EMAILED":             2,
+	"PENDING_REVIEW":      3,
+	"REVIEWED":            4,
+	"ISSUING_CERTIFICATE": 5,
+	"VERIFIED":            6,
+	"REJECTED":            7,
+	"REVOKED":             8,
+	"ERRORED":             9,
+	"DELISTED":            10,
+	"REVOKING":            11,
+}
+
+func (x VerificationState) String() string {
+	return proto.EnumName(VerificationState_name, int32(x))
+}
+
+func (VerificationState) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_0b5431a010549573, []int{0}
+}
+
+type EmailType int32
+
+const (
+	EmailType_UNKNOWN_EMAIL  EmailType = 0
+	EmailType_VERIFY_CONTACT EmailType = 1
+	EmailType_REVIEW_REQUEST EmailType = 2
+	EmailType_REJECTION      EmailType = 3
+	EmailType_CERTIFICATES   EmailType = 4
+)
+
+var EmailType_name = map[int32]string{
+	0: "UNKNOWN_EMAIL",
+	1: "VERIFY_CONTACT",
+	2: "REVIEW_REQUEST",
+	3: "REJECTION",
+	4: "CERTIFICATES",
+}
+
+var EmailType_value = map[string]int32{
+	"UNKNOWN_EMAIL":  0,
+	"VERIFY_CONTACT": 1,
+	"REVIEW_REQUEST": 2,
+	"REJECTION":      3,
+	"CERTIFICATES":   4,
+}
+
+func (x EmailType) String() string {
+	return proto.EnumName(EmailType_name, int32(x))
+}
+
+func (EmailType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_0b5431a010549573, []int{1}
+}
+
+type AuditAction int32
+
+const (
+	AuditAction_UNKNOWN_ACTION AuditAction = 0
+	AuditAction_CREATED        AuditAction = 1
+	AuditAction_UPDATED        AuditAction = 2
+	AuditAction_DELETED        AuditAction = 3
+)
+
+var AuditAction_name = map[int32]string{
+	0: "UNKNOWN_ACTION",
+	1: "CREATED",
+	2: "UPDATED",
+	3: "DELETED",
+}
+
+var AuditAction_value = map[string]int32{
+	"UNKNOWN_ACTION": 0,
+	"CREATED":        1,
+	"UPDATED":        2,
+	"DELETED":        3,
+}
+
+func (x AuditAction) String() string {
+	return proto.EnumName(AuditAction_name, int32(x))
+}
+
+func (AuditAction) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_0b5431a010549573, []int{2}
+}
+
 type VASP struct {
 	Id                     uint64              `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	VaspEntity             *Entity             `protobuf:"bytes,2,opt,name=vaspEntity,proto3" json:"vaspEntity,omitempty"`
 	VaspTRISACertification *TRISACertification `protobuf:"bytes,3,opt,name=vaspTRISACertification,proto3" json:"vaspTRISACertification,omitempty"`
 	FirstListed            string              `protobuf:"bytes,4,opt,name=firstListed,proto3" json:"firstListed,omitempty"`
 	LastUpdated            string              `protobuf:"bytes,5,opt,name=lastUpdated,proto3" json:"lastUpdated,omitempty"`
-	XXX_NoUnkeyedLiteral   struct{}            `json:"-"`
-	XXX_unrecognized       []byte              `json:"-"`
-	XXX_sizecache          int32               `json:"-"`
+	VerifiedOn             string              `protobuf:"bytes,6,opt,name=verifiedOn,proto3" json:"verifiedOn,omitempty"`
+	VerificationToken      string              `protobuf:"bytes,7,opt,name=verificationToken,proto3" json:"verificationToken,omitempty"`
+	VerificationStatus     VerificationState   `protobuf:"varint,8,opt,name=verificationStatus,proto3,enum=pb.VerificationState" json:"verificationStatus,omitempty"`
+	Pkcs12Password         string              `protobuf:"bytes,9,opt,name=pkcs12Password,proto3" json:"pkcs12Password,omitempty"`
+	// Delisted VASPs are kept as tombstones until they are purged after purgeAfter (if
+	// set), all timestamps are RFC3339 strings.
+	DelistedOn   string `protobuf:"bytes,10,opt,name=delistedOn,proto3" json:"delistedOn,omitempty"`
+	DelistReason string `protobuf:"bytes,11,opt,name=delistReason,proto3" json:"delistReason,omitempty"`
+	PurgeAfter   string `protobuf:"bytes,12,opt,name=purgeAfter,proto3" json:"purgeAfter,omitempty"`
+	// The version of the record is incremented by every update, which must be made to
+	// the current version of the record so that concurrent updates are not lost.
+	Version              uint64   `protobuf:"varint,13,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *VASP) Reset()         { *m = VASP{} }
@@ -91,6 +223,62 @@ func (m *VASP) GetLastUpdated() string {
 	return ""
 }
 
+func (m *VASP) GetVerifiedOn() string {
+	if m != nil {
+		return m.VerifiedOn
+	}
+	return ""
+}
+
+func (m *VASP) GetVerificationToken() string {
+	if m != nil {
+		return m.VerificationToken
+	}
+	return ""
+}
+
+func (m *VASP) GetVerificationStatus() VerificationState {
+	if m != nil {
+		return m.VerificationStatus
+	}
+	return VerificationState_NO_VERIFICATION
+}
+
+func (m *VASP) GetPkcs12Password() string {
+	if m != nil {
+		return m.Pkcs12Password
+	}
+	return ""
+}
+
+func (m *VASP) GetDelistedOn() string {
+	if m != nil {
+		return m.DelistedOn
+	}
+	return ""
+}
+
+func (m *VASP) GetDelistReason() string {
+	if m != nil {
+		return m.DelistReason
+	}
+	return ""
+}
+
+func (m *VASP) GetPurgeAfter() string {
+	if m != nil {
+		return m.PurgeAfter
+	}
+	return ""
+}
+
+func (m *VASP) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
 type Entity struct {
 	Id                      uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	VaspFullLegalName       string   `protobuf:"bytes,2,opt,name=vaspFullLegalName,proto3" json:"vaspFullLegalName,omitempty"`
@@ -535,62 +723,480 @@ func (m *PublicKeyInfo) GetSignature() []byte {
 	return nil
 }
 
+type CertificateRequest struct {
+	Id                   uint64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Vasp                 uint64   `protobuf:"varint,2,opt,name=vasp,proto3" json:"vasp,omitempty"`
+	CommonName           string   `protobuf:"bytes,3,opt,name=commonName,proto3" json:"commonName,omitempty"`
+	BatchId              int64    `protobuf:"varint,4,opt,name=batchId,proto3" json:"batchId,omitempty"`
+	BatchName            string   `protobuf:"bytes,5,opt,name=batchName,proto3" json:"batchName,omitempty"`
+	Attempts             int32    `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	Created              string   `protobuf:"bytes,7,opt,name=created,proto3" json:"created,omitempty"`
+	NextAttempt          string   `protobuf:"bytes,8,opt,name=nextAttempt,proto3" json:"nextAttempt,omitempty"`
+	LastError            string   `protobuf:"bytes,9,opt,name=lastError,proto3" json:"lastError,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CertificateRequest) Reset()         { *m = CertificateRequest{} }
+func (m *CertificateRequest) String() string { return proto.CompactTextString(m) }
+func (*CertificateRequest) ProtoMessage()    {}
+func (*CertificateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0b5431a010549573, []int{5}
+}
+
+func (m *CertificateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CertificateRequest.Unmarshal(m, b)
+}
+func (m *CertificateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CertificateRequest.Marshal(b, m, deterministic)
+}
+func (m *CertificateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CertificateRequest.Merge(m, src)
+}
+func (m *CertificateRequest) XXX_Size() int {
+	return xxx_messageInfo_CertificateRequest.Size(m)
+}
+func (m *CertificateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CertificateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CertificateRequest proto.InternalMessageInfo
+
+func (m *CertificateRequest) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *CertificateRequest) GetVasp() uint64 {
+	if m != nil {
+		return m.Vasp
+	}
+	return 0
+}
+
+func (m *CertificateRequest) GetCommonName() string {
+	if m != nil {
+		return m.CommonName
+	}
+	return ""
+}
+
+func (m *CertificateRequest) GetBatchId() int64 {
+	if m != nil {
+		return m.BatchId
+	}
+	return 0
+}
+
+func (m *CertificateRequest) GetBatchName() string {
+	if m != nil {
+		return m.BatchName
+	}
+	return ""
+}
+
+func (m *CertificateRequest) GetAttempts() int32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
+func (m *CertificateRequest) GetCreated() string {
+	if m != nil {
+		return m.Created
+	}
+	return ""
+}
+
+func (m *CertificateRequest) GetNextAttempt() string {
+	if m != nil {
+		return m.NextAttempt
+	}
+	return ""
+}
+
+func (m *CertificateRequest) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+type Email struct {
+	Id                   uint64    `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Vasp                 uint64    `protobuf:"varint,2,opt,name=vasp,proto3" json:"vasp,omitempty"`
+	Type                 EmailType `protobuf:"varint,3,opt,name=type,proto3,enum=pb.EmailType" json:"type,omitempty"`
+	Reason               string    `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Attachment           []byte    `protobuf:"bytes,5,opt,name=attachment,proto3" json:"attachment,omitempty"`
+	AttachmentName       string    `protobuf:"bytes,6,opt,name=attachmentName,proto3" json:"attachmentName,omitempty"`
+	Attempts             int32     `protobuf:"varint,7,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	Created              string    `protobuf:"bytes,8,opt,name=created,proto3" json:"created,omitempty"`
+	NextAttempt          string    `protobuf:"bytes,9,opt,name=nextAttempt,proto3" json:"nextAttempt,omitempty"`
+	LastError            string    `protobuf:"bytes,10,opt,name=lastError,proto3" json:"lastError,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *Email) Reset()         { *m = Email{} }
+func (m *Email) String() string { return proto.CompactTextString(m) }
+func (*Email) ProtoMessage()    {}
+func (*Email) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0b5431a010549573, []int{6}
+}
+
+func (m *Email) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Email.Unmarshal(m, b)
+}
+func (m *Email) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Email.Marshal(b, m, deterministic)
+}
+func (m *Email) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Email.Merge(m, src)
+}
+func (m *Email) XXX_Size() int {
+	return xxx_messageInfo_Email.Size(m)
+}
+func (m *Email) XXX_DiscardUnknown() {
+	xxx_messageInfo_Email.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Email proto.InternalMessageInfo
+
+func (m *Email) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Email) GetVasp() uint64 {
+	if m != nil {
+		return m.Vasp
+	}
+	return 0
+}
+
+func (m *Email) GetType() EmailType {
+	if m != nil {
+		return m.Type
+	}
+	return EmailType_UNKNOWN_EMAIL
+}
+
+func (m *Email) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *Email) GetAttachment() []byte {
+	if m != nil {
+		return m.Attachment
+	}
+	return nil
+}
+
+func (m *Email) GetAttachmentName() string {
+	if m != nil {
+		return m.AttachmentName
+	}
+	return ""
+}
+
+func (m *Email) GetAttempts() int32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
+func (m *Email) GetCreated() string {
+	if m != nil {
+		return m.Created
+	}
+	return ""
+}
+
+func (m *Email) GetNextAttempt() string {
+	if m != nil {
+		return m.NextAttempt
+	}
+	return ""
+}
+
+func (m *Email) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+// AuditEntry is an immutable record of a change to a VASP, appended to the history of
+// the VASP whenever it is created, updated or deleted. The actor is the identity of the
+// client (or the background process) that made the change, and the changes are the
+// fields of the record that differ before and after the change; secrets are redacted.
+type AuditEntry struct {
+	Id                   uint64         `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Vasp                 uint64         `protobuf:"varint,2,opt,name=vasp,proto3" json:"vasp,omitempty"`
+	Action               AuditAction    `protobuf:"varint,3,opt,name=action,proto3,enum=pb.AuditAction" json:"action,omitempty"`
+	Timestamp            string         `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Actor                string         `protobuf:"bytes,5,opt,name=actor,proto3" json:"actor,omitempty"`
+	Address              string         `protobuf:"bytes,6,opt,name=address,proto3" json:"address,omitempty"`
+	Rpc                  string         `protobuf:"bytes,7,opt,name=rpc,proto3" json:"rpc,omitempty"`
+	Changes              []*FieldChange `protobuf:"bytes,8,rep,name=changes,proto3" json:"changes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *AuditEntry) Reset()         { *m = AuditEntry{} }
+func (m *AuditEntry) String() string { return proto.CompactTextString(m) }
+func (*AuditEntry) ProtoMessage()    {}
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0b5431a010549573, []int{7}
+}
+
+func (m *AuditEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditEntry.Unmarshal(m, b)
+}
+func (m *AuditEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditEntry.Marshal(b, m, deterministic)
+}
+func (m *AuditEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditEntry.Merge(m, src)
+}
+func (m *AuditEntry) XXX_Size() int {
+	return xxx_messageInfo_AuditEntry.Size(m)
+}
+func (m *AuditEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditEntry proto.InternalMessageInfo
+
+func (m *AuditEntry) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *AuditEntry) GetVasp() uint64 {
+	if m != nil {
+		return m.Vasp
+	}
+	return 0
+}
+
+func (m *AuditEntry) GetAction() AuditAction {
+	if m != nil {
+		return m.Action
+	}
+	return AuditAction_UNKNOWN_ACTION
+}
+
+func (m *AuditEntry) GetTimestamp() string {
+	if m != nil {
+		return m.Timestamp
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetRpc() string {
+	if m != nil {
+		return m.Rpc
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetChanges() []*FieldChange {
+	if m != nil {
+		return m.Changes
+	}
+	return nil
+}
+
+// FieldChange is the value of a VASP field (by its JSON path, e.g. vaspEntity.vaspURL)
+// before and after a change, values are JSON encoded and empty if the field is not set.
+type FieldChange struct {
+	Field                string   `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Before               string   `protobuf:"bytes,2,opt,name=before,proto3" json:"before,omitempty"`
+	After                string   `protobuf:"bytes,3,opt,name=after,proto3" json:"after,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FieldChange) Reset()         { *m = FieldChange{} }
+func (m *FieldChange) String() string { return proto.CompactTextString(m) }
+func (*FieldChange) ProtoMessage()    {}
+func (*FieldChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0b5431a010549573, []int{8}
+}
+
+func (m *FieldChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FieldChange.Unmarshal(m, b)
+}
+func (m *FieldChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FieldChange.Marshal(b, m, deterministic)
+}
+func (m *FieldChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FieldChange.Merge(m, src)
+}
+func (m *FieldChange) XXX_Size() int {
+	return xxx_messageInfo_FieldChange.Size(m)
+}
+func (m *FieldChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_FieldChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FieldChange proto.InternalMessageInfo
+
+func (m *FieldChange) GetField() string {
+	if m != nil {
+		return m.Field
+	}
+	return ""
+}
+
+func (m *FieldChange) GetBefore() string {
+	if m != nil {
+		return m.Before
+	}
+	return ""
+}
+
+func (m *FieldChange) GetAfter() string {
+	if m != nil {
+		return m.After
+	}
+	return ""
+}
+
 func init() {
+	proto.RegisterEnum("pb.VerificationState", VerificationState_name, VerificationState_value)
+	proto.RegisterEnum("pb.EmailType", EmailType_name, EmailType_value)
+	proto.RegisterEnum("pb.AuditAction", AuditAction_name, AuditAction_value)
 	proto.RegisterType((*VASP)(nil), "pb.VASP")
 	proto.RegisterType((*Entity)(nil), "pb.Entity")
 	proto.RegisterType((*TRISACertification)(nil), "pb.TRISACertification")
 	proto.RegisterType((*Name)(nil), "pb.Name")
 	proto.RegisterType((*PublicKeyInfo)(nil), "pb.PublicKeyInfo")
+	proto.RegisterType((*CertificateRequest)(nil), "pb.CertificateRequest")
+	proto.RegisterType((*Email)(nil), "pb.Email")
+	proto.RegisterType((*AuditEntry)(nil), "pb.AuditEntry")
+	proto.RegisterType((*FieldChange)(nil), "pb.FieldChange")
 }
 
 func init() { proto.RegisterFile("models.proto", fileDescriptor_0b5431a010549573) }
 
 var fileDescriptor_0b5431a010549573 = []byte{
-	// 723 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x95, 0xd1, 0x6a, 0xe3, 0x46,
-	0x14, 0x86, 0xb1, 0xe5, 0x24, 0xd6, 0xb1, 0x13, 0x92, 0xa1, 0x4d, 0x45, 0x09, 0xc1, 0x98, 0x52,
-	0x4c, 0x28, 0xbe, 0x70, 0x0b, 0xed, 0xad, 0x9b, 0xa6, 0x60, 0xd6, 0x84, 0x30, 0x5e, 0xe7, 0x7e,
-	0x2c, 0x1d, 0x7b, 0x67, 0x23, 0xcd, 0x88, 0x99, 0x51, 0x58, 0xe7, 0x51, 0x96, 0x7d, 0x81, 0x7d,
-	0xa1, 0x7d, 0x89, 0x7d, 0x89, 0x65, 0x46, 0xb2, 0x22, 0x59, 0xce, 0xe5, 0xf9, 0xfe, 0x7f, 0x2c,
-	0x9d, 0xf3, 0x9f, 0x91, 0xa1, 0x9f, 0xc8, 0x08, 0x63, 0x3d, 0x4e, 0x95, 0x34, 0x92, 0xb4, 0xd3,
-	0xd5, 0xf0, 0x5b, 0x0b, 0x3a, 0x8f, 0xd3, 0xc5, 0x03, 0x39, 0x83, 0x36, 0x8f, 0x82, 0xd6, 0xa0,
-	0x35, 0xea, 0xd0, 0x36, 0x8f, 0xc8, 0x0d, 0xc0, 0x33, 0xd3, 0xe9, 0x9d, 0x30, 0xdc, 0x6c, 0x83,
-	0xf6, 0xa0, 0x35, 0xea, 0x4d, 0x60, 0x9c, 0xae, 0xc6, 0x39, 0xa1, 0x15, 0x95, 0xdc, 0xc3, 0xa5,
-	0xad, 0xde, 0xd3, 0xd9, 0x62, 0x7a, 0x8b, 0xca, 0xf0, 0x35, 0x0f, 0x99, 0xe1, 0x52, 0x04, 0x9e,
-	0x3b, 0x77, 0x69, 0xcf, 0x35, 0x55, 0xfa, 0xc6, 0x29, 0x32, 0x80, 0xde, 0x9a, 0x2b, 0x6d, 0xe6,
-	0x5c, 0x1b, 0x8c, 0x82, 0xce, 0xa0, 0x35, 0xf2, 0x69, 0x15, 0x59, 0x47, 0xcc, 0xb4, 0x59, 0xa6,
-	0x11, 0xb3, 0x8e, 0xa3, 0xdc, 0x51, 0x41, 0xc3, 0x2f, 0x1e, 0x1c, 0x17, 0xaf, 0xb7, 0xdf, 0xda,
-	0x1f, 0x70, 0x61, 0x1f, 0xfc, 0x7f, 0x16, 0xc7, 0x73, 0xdc, 0xb0, 0xf8, 0x9e, 0x25, 0xe8, 0x3a,
-	0xf4, 0x69, 0x53, 0x20, 0x13, 0xf8, 0xa9, 0x06, 0xa7, 0x51, 0xa4, 0x50, 0x6b, 0xd7, 0x9a, 0x4f,
-	0x0f, 0x6a, 0xe4, 0x2f, 0xf8, 0xd9, 0xf2, 0x99, 0x08, 0xa5, 0x4a, 0xa5, 0x72, 0x5d, 0xfd, 0xc7,
-	0x0c, 0x16, 0xad, 0x1c, 0x16, 0xc9, 0x3f, 0xf0, 0x4b, 0x43, 0xb8, 0xcf, 0x92, 0x15, 0xaa, 0xa2,
-	0xc1, 0xb7, 0x64, 0xf2, 0x1b, 0x9c, 0x5a, 0x69, 0x7e, 0x37, 0x2b, 0xfc, 0xc7, 0xce, 0x5f, 0x87,
-	0xe4, 0x06, 0xce, 0x2d, 0xb8, 0x95, 0xc2, 0xb0, 0xd0, 0xdc, 0x25, 0x8c, 0xc7, 0xc1, 0x89, 0x33,
-	0x36, 0x38, 0x09, 0xe0, 0xc4, 0xb2, 0x25, 0x9d, 0x07, 0x5d, 0x67, 0xd9, 0x95, 0x64, 0x08, 0x7d,
-	0xe7, 0x66, 0x06, 0x37, 0x52, 0x6d, 0x03, 0xdf, 0xc9, 0x35, 0x66, 0xe3, 0xc9, 0x7f, 0x31, 0x13,
-	0x46, 0x6d, 0x03, 0xc8, 0xe3, 0xa9, 0xa0, 0xe1, 0x57, 0x0f, 0xc8, 0x81, 0xe4, 0x9b, 0x5b, 0xd8,
-	0xd3, 0xd9, 0xea, 0x23, 0x86, 0xa6, 0x0c, 0xa9, 0x37, 0xe9, 0xda, 0x75, 0xb2, 0x35, 0xad, 0x8a,
-	0x64, 0x04, 0xc0, 0xb5, 0xce, 0x50, 0x39, 0xab, 0xb7, 0x67, 0xad, 0x68, 0xb6, 0x05, 0x8d, 0x8a,
-	0xb3, 0xb8, 0x98, 0x96, 0x4d, 0xa5, 0x4f, 0x6b, 0xcc, 0x0d, 0x00, 0x95, 0xb6, 0x4b, 0x7c, 0x54,
-	0x0c, 0x20, 0x2f, 0xc9, 0x18, 0x88, 0xe6, 0x1b, 0xc1, 0x4c, 0xa6, 0x70, 0x1a, 0x6f, 0xa4, 0xe2,
-	0xe6, 0x43, 0x52, 0x4c, 0xfc, 0x80, 0x42, 0xae, 0x01, 0x52, 0xa6, 0x58, 0x82, 0x06, 0x95, 0x0e,
-	0x4e, 0x06, 0xde, 0xc8, 0xa7, 0x15, 0x42, 0x7e, 0x87, 0x33, 0x21, 0xcd, 0x23, 0x8b, 0x79, 0xf4,
-	0x2f, 0xae, 0xa5, 0xc2, 0x62, 0xe2, 0x7b, 0xd4, 0x86, 0xbc, 0x23, 0xd3, 0xb5, 0x41, 0x55, 0x4c,
-	0xbe, 0x0e, 0xc9, 0xdf, 0x70, 0xfa, 0x90, 0xad, 0x62, 0x1e, 0xbe, 0xc3, 0xed, 0x4c, 0xac, 0xa5,
-	0x1b, 0x7e, 0x6f, 0x72, 0x61, 0x07, 0x51, 0x13, 0x68, 0xdd, 0x67, 0x1b, 0x56, 0xf8, 0x2c, 0x9f,
-	0x30, 0x0a, 0x7a, 0x83, 0xd6, 0xa8, 0x4b, 0x77, 0xe5, 0xf0, 0xb3, 0x07, 0x1d, 0x37, 0xb7, 0xfd,
-	0x74, 0xae, 0x01, 0x42, 0x99, 0x24, 0x52, 0x54, 0x6e, 0x50, 0x85, 0xd8, 0x37, 0x0e, 0xf3, 0xbc,
-	0x29, 0x6e, 0x76, 0x9f, 0x03, 0x9f, 0xd6, 0xa1, 0x4d, 0x43, 0xaa, 0x0d, 0x13, 0xfc, 0x25, 0xff,
-	0x66, 0xe4, 0x77, 0xa4, 0xc6, 0xec, 0xcc, 0xab, 0x35, 0x8b, 0x97, 0x82, 0x9b, 0x22, 0x98, 0x03,
-	0x0a, 0xf9, 0x15, 0xba, 0xb1, 0x0c, 0x59, 0x6c, 0xbf, 0x5d, 0x79, 0x32, 0x65, 0x6d, 0xdf, 0x4a,
-	0x1b, 0x66, 0xf0, 0x41, 0xc9, 0x67, 0x2e, 0x42, 0x2c, 0xee, 0x40, 0x1d, 0x36, 0x76, 0x24, 0xcf,
-	0xa4, 0xbe, 0x23, 0x37, 0x70, 0xce, 0x45, 0x78, 0x5b, 0x6b, 0x31, 0x0f, 0xa5, 0xc1, 0x0b, 0xef,
-	0xa2, 0xf6, 0x60, 0x28, 0xbd, 0x35, 0x6e, 0xbd, 0xab, 0x4c, 0x73, 0x81, 0x5a, 0x97, 0xd7, 0xac,
-	0x97, 0x7b, 0xf7, 0xf9, 0xf0, 0x7b, 0x6b, 0x2f, 0xf0, 0x46, 0x4a, 0x57, 0xe0, 0xb3, 0x72, 0x4d,
-	0xf3, 0x90, 0x5e, 0xc1, 0xde, 0x76, 0x7a, 0x8d, 0xed, 0xbc, 0x02, 0x3f, 0xdd, 0xfd, 0x7c, 0x71,
-	0x51, 0x5e, 0x81, 0x9d, 0x33, 0x7e, 0x4a, 0xa5, 0x40, 0x91, 0xa7, 0xe1, 0xd1, 0xb2, 0xb6, 0x0b,
-	0xf5, 0x84, 0xdb, 0x05, 0x7f, 0x41, 0x17, 0x81, 0x47, 0x77, 0xa5, 0x3d, 0xf5, 0x84, 0xdb, 0xa5,
-	0x66, 0x1b, 0x2c, 0xee, 0x43, 0x59, 0xdb, 0xe7, 0x95, 0x77, 0xc8, 0x0d, 0xbd, 0x4f, 0x5f, 0xc1,
-	0xea, 0xd8, 0xfd, 0x73, 0xfd, 0xf9, 0x23, 0x00, 0x00, 0xff, 0xff, 0xfc, 0xbb, 0x7e, 0x3e, 0xc9,
-	0x06, 0x00, 0x00,
+	// 1378 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x57, 0xcd, 0x6e, 0xdb, 0x46,
+	0x10, 0x8e, 0x44, 0xd9, 0xb2, 0x46, 0xb2, 0x43, 0x6f, 0xfe, 0x88, 0x22, 0x08, 0x5c, 0xa1, 0x68,
+	0x5d, 0xa3, 0x30, 0x50, 0xb5, 0x40, 0x7b, 0x55, 0x24, 0x26, 0x65, 0xed, 0x48, 0xce, 0x4a, 0x72,
+	0xd0, 0x93, 0xb1, 0xa2, 0x56, 0x32, 0x6b, 0x8a, 0x64, 0x77, 0x57, 0x6e, 0x94, 0x07, 0xe8, 0xbd,
+	0xd7, 0xa2, 0xc7, 0x5e, 0xfa, 0x38, 0x7d, 0x87, 0xde, 0xfa, 0x12, 0xc5, 0xec, 0x92, 0x12, 0x29,
+	0x39, 0x6d, 0x6e, 0xfa, 0xbe, 0x99, 0xdd, 0xd9, 0xf9, 0x66, 0x66, 0xb9, 0x82, 0xc6, 0x3c, 0x9e,
+	0xf0, 0x50, 0x9e, 0x26, 0x22, 0x56, 0x31, 0x29, 0x27, 0xe3, 0xe6, 0xaf, 0x15, 0xa8, 0x5c, 0xb6,
+	0x07, 0x17, 0xe4, 0x00, 0xca, 0xc1, 0xc4, 0x29, 0x1d, 0x95, 0x8e, 0x2b, 0xb4, 0x1c, 0x4c, 0xc8,
+	0x09, 0xc0, 0x2d, 0x93, 0x89, 0x1b, 0xa9, 0x40, 0x2d, 0x9d, 0xf2, 0x51, 0xe9, 0xb8, 0xde, 0x82,
+	0xd3, 0x64, 0x7c, 0x6a, 0x18, 0x9a, 0xb3, 0x92, 0x1e, 0x3c, 0x46, 0x34, 0xa4, 0xde, 0xa0, 0xdd,
+	0xe1, 0x42, 0x05, 0xd3, 0xc0, 0x67, 0x2a, 0x88, 0x23, 0xc7, 0xd2, 0xeb, 0x1e, 0xe3, 0xba, 0x6d,
+	0x2b, 0x7d, 0xcf, 0x2a, 0x72, 0x04, 0xf5, 0x69, 0x20, 0xa4, 0x3a, 0x0f, 0xa4, 0xe2, 0x13, 0xa7,
+	0x72, 0x54, 0x3a, 0xae, 0xd1, 0x3c, 0x85, 0x1e, 0x21, 0x93, 0x6a, 0x94, 0x4c, 0x18, 0x7a, 0xec,
+	0x18, 0x8f, 0x1c, 0x45, 0x9e, 0x01, 0xdc, 0x72, 0x11, 0x4c, 0x03, 0x3e, 0xe9, 0x47, 0xce, 0xae,
+	0x76, 0xc8, 0x31, 0xe4, 0x0b, 0x38, 0x34, 0xc8, 0xc4, 0x1c, 0xc6, 0x37, 0x3c, 0x72, 0xaa, 0xda,
+	0x6d, 0xdb, 0x40, 0x5c, 0x20, 0x79, 0x72, 0xa0, 0x98, 0x5a, 0x48, 0x67, 0xef, 0xa8, 0x74, 0x7c,
+	0xd0, 0x7a, 0x84, 0xd9, 0x5d, 0x6e, 0x58, 0x39, 0xbd, 0x63, 0x01, 0xf9, 0x14, 0x0e, 0x92, 0x1b,
+	0x5f, 0x7e, 0xd9, 0xba, 0x60, 0x52, 0xfe, 0x1c, 0x8b, 0x89, 0x53, 0xd3, 0x11, 0x37, 0x58, 0x3c,
+	0xfc, 0x84, 0x87, 0x3a, 0xd5, 0x7e, 0xe4, 0x80, 0x39, 0xfc, 0x9a, 0x21, 0x4d, 0x68, 0x18, 0x44,
+	0x39, 0x93, 0x71, 0xe4, 0xd4, 0xb5, 0x47, 0x81, 0xc3, 0x3d, 0x92, 0x85, 0x98, 0xf1, 0xf6, 0x54,
+	0x71, 0xe1, 0x34, 0xcc, 0x1e, 0x6b, 0x86, 0x38, 0x50, 0xbd, 0xe5, 0x42, 0x62, 0x95, 0xf6, 0x75,
+	0xd5, 0x33, 0xd8, 0xfc, 0xdd, 0x82, 0xdd, 0xb4, 0xb2, 0x9b, 0x5d, 0x81, 0xaa, 0x31, 0x99, 0xbc,
+	0x58, 0x84, 0xe1, 0x39, 0x9f, 0xb1, 0xb0, 0xc7, 0xe6, 0xdc, 0x29, 0xa7, 0xaa, 0x6d, 0x1a, 0x48,
+	0x0b, 0x1e, 0x16, 0xc8, 0xf6, 0x64, 0x22, 0xb8, 0x94, 0xba, 0x2b, 0x6a, 0xf4, 0x4e, 0x1b, 0xf9,
+	0x1a, 0x1e, 0x21, 0xef, 0x45, 0x7e, 0x2c, 0x92, 0x58, 0x68, 0xf5, 0xba, 0x4c, 0xf1, 0xb4, 0x0b,
+	0xee, 0x36, 0x92, 0x6f, 0xe1, 0xc9, 0x96, 0xa1, 0xb7, 0x98, 0x8f, 0xb9, 0x48, 0x7b, 0xe3, 0x7d,
+	0x66, 0xf2, 0x09, 0xec, 0xa3, 0xe9, 0xdc, 0xf5, 0x52, 0x7f, 0xd3, 0x2a, 0x45, 0x92, 0x9c, 0x80,
+	0x8d, 0x44, 0x27, 0x8e, 0x14, 0xf3, 0x95, 0x3b, 0x67, 0x41, 0x98, 0x36, 0xcb, 0x16, 0xaf, 0x85,
+	0x65, 0x32, 0x19, 0xd1, 0x73, 0xdd, 0x20, 0x35, 0x9a, 0x41, 0x2c, 0x9b, 0xf6, 0x66, 0x8a, 0xcf,
+	0x62, 0xb1, 0x4c, 0x8b, 0x5f, 0xe0, 0xb0, 0xb3, 0xcd, 0x8e, 0x8b, 0x48, 0x89, 0x65, 0x5a, 0xfb,
+	0x3c, 0xd5, 0xfc, 0xd3, 0x02, 0x72, 0xc7, 0xd0, 0x6c, 0x0f, 0x70, 0x5d, 0x2e, 0xc6, 0x3f, 0x72,
+	0x5f, 0xad, 0x8a, 0x54, 0x6f, 0xed, 0x61, 0xaf, 0x22, 0xa6, 0x79, 0x23, 0x39, 0x06, 0x08, 0xa4,
+	0x5c, 0x70, 0xa1, 0x5d, 0xad, 0x0d, 0xd7, 0x9c, 0x0d, 0x53, 0x90, 0x5c, 0x04, 0x2c, 0x4c, 0xd5,
+	0xc2, 0xaa, 0x34, 0x68, 0x81, 0xcb, 0x77, 0xd6, 0x4e, 0x2a, 0x80, 0x81, 0xe4, 0x14, 0x88, 0x0c,
+	0x66, 0x11, 0x53, 0x0b, 0xc1, 0xdb, 0xe1, 0x2c, 0x16, 0x81, 0xba, 0x9e, 0xa7, 0x8a, 0xdf, 0x61,
+	0xd1, 0x3d, 0xcc, 0x04, 0x9b, 0x73, 0xc5, 0x85, 0x74, 0xaa, 0x47, 0x96, 0xee, 0xe1, 0x15, 0x83,
+	0xf3, 0x14, 0xc5, 0xea, 0x92, 0x85, 0xc1, 0xe4, 0x39, 0x9f, 0xc6, 0x82, 0xa7, 0x8a, 0x6f, 0xb0,
+	0x58, 0xe4, 0x8c, 0x31, 0xe3, 0x60, 0x94, 0x2f, 0x92, 0xe4, 0x1b, 0xd8, 0xbf, 0x58, 0x8c, 0xc3,
+	0xc0, 0x3f, 0xe3, 0x4b, 0x2f, 0x9a, 0xc6, 0x5a, 0xfc, 0x7a, 0xeb, 0x10, 0x85, 0x28, 0x18, 0x68,
+	0xd1, 0x0f, 0x13, 0x16, 0xfc, 0x36, 0xbe, 0xe1, 0x13, 0x3d, 0x89, 0x7b, 0x34, 0x83, 0xcd, 0xdf,
+	0x2c, 0xa8, 0x68, 0xdd, 0x36, 0xab, 0xf3, 0x0c, 0xc0, 0x8f, 0xe7, 0xf3, 0x38, 0xca, 0x4d, 0x50,
+	0x8e, 0xc1, 0x13, 0xfb, 0xa6, 0xde, 0x94, 0xcf, 0xb2, 0x9b, 0xb4, 0x46, 0x8b, 0x24, 0x56, 0x23,
+	0x16, 0x33, 0x16, 0x05, 0xef, 0xcc, 0x75, 0x6b, 0x66, 0xa4, 0xc0, 0xa1, 0xe6, 0x79, 0xcc, 0xc2,
+	0x51, 0x14, 0xa8, 0xb4, 0x30, 0x77, 0x58, 0xc8, 0x47, 0xb0, 0x17, 0xc6, 0x3e, 0x0b, 0xf1, 0xda,
+	0x37, 0x95, 0x59, 0x61, 0x3c, 0x95, 0x54, 0x4c, 0xf1, 0x0b, 0x11, 0xdf, 0x06, 0x91, 0xcf, 0xd3,
+	0x19, 0x28, 0x92, 0x5b, 0x3d, 0x62, 0x6a, 0x52, 0xe0, 0x70, 0xa0, 0x82, 0xc8, 0xef, 0x14, 0x52,
+	0x34, 0x45, 0xd9, 0xe2, 0x53, 0xdf, 0x41, 0x21, 0x30, 0xac, 0x7c, 0x0b, 0x3c, 0xfa, 0x8e, 0x17,
+	0x32, 0x88, 0xb8, 0x94, 0xab, 0x31, 0x33, 0xb7, 0xe3, 0x16, 0xdf, 0xfc, 0xa7, 0xb4, 0x51, 0xf0,
+	0xad, 0x2a, 0x3d, 0x85, 0x1a, 0x5b, 0xb5, 0xa9, 0x29, 0xd2, 0x9a, 0xd8, 0xe8, 0x4e, 0x6b, 0xab,
+	0x3b, 0x9f, 0x42, 0x2d, 0xc9, 0xb6, 0x4f, 0x07, 0x65, 0x4d, 0xa0, 0xce, 0xfc, 0x6d, 0x12, 0x47,
+	0x3c, 0x32, 0xd5, 0xb0, 0xe8, 0x0a, 0x63, 0x43, 0xdd, 0xf0, 0xe5, 0x20, 0x78, 0xc7, 0x75, 0x09,
+	0x2c, 0x9a, 0x41, 0x5c, 0x75, 0xc3, 0x97, 0x23, 0xc9, 0x66, 0x3c, 0x9d, 0x87, 0x15, 0xc6, 0x78,
+	0xab, 0x19, 0xd2, 0xa2, 0x37, 0xe8, 0x9a, 0x68, 0xfe, 0x52, 0x06, 0xb2, 0xbe, 0x31, 0x38, 0xe5,
+	0x3f, 0x2d, 0xb8, 0x54, 0x5b, 0x29, 0x13, 0xa8, 0xe0, 0x65, 0xa3, 0xb3, 0xad, 0x50, 0xfd, 0x7b,
+	0xa3, 0x59, 0xad, 0xad, 0x66, 0x75, 0xa0, 0x3a, 0x66, 0xca, 0xbf, 0xf6, 0xcc, 0xb7, 0xda, 0xa2,
+	0x19, 0xc4, 0x23, 0xe9, 0x9f, 0x7a, 0xa1, 0xe9, 0xb9, 0x35, 0x81, 0xc9, 0x30, 0xa5, 0xf8, 0x3c,
+	0x51, 0x52, 0xe7, 0xb9, 0x43, 0x57, 0x18, 0xf7, 0xf4, 0x05, 0xd7, 0x5f, 0x77, 0xd3, 0x64, 0x19,
+	0xc4, 0x1b, 0x32, 0xe2, 0x6f, 0x55, 0xdb, 0x78, 0xa6, 0xdd, 0x95, 0xa7, 0x30, 0x2a, 0x3e, 0x05,
+	0x5c, 0x21, 0xe2, 0x6c, 0xd4, 0xd7, 0x44, 0xf3, 0x8f, 0x32, 0xec, 0x98, 0x9b, 0xfa, 0x43, 0x72,
+	0xff, 0x18, 0x2a, 0x6a, 0x99, 0x98, 0xac, 0x0f, 0x5a, 0xfb, 0xfa, 0x05, 0x84, 0x8b, 0x87, 0xcb,
+	0x84, 0x53, 0x6d, 0x22, 0x8f, 0x61, 0x57, 0x98, 0xef, 0xb0, 0x99, 0xbf, 0x14, 0xa1, 0x6c, 0x4c,
+	0x29, 0xe6, 0x5f, 0xcf, 0xb3, 0x1a, 0x37, 0x68, 0x8e, 0xc1, 0xdb, 0x6b, 0x8d, 0xb4, 0x42, 0x66,
+	0xde, 0x36, 0xd8, 0x82, 0x4c, 0xd5, 0xf7, 0xcb, 0xb4, 0xf7, 0x9f, 0x32, 0xd5, 0xfe, 0x47, 0x26,
+	0xd8, 0x94, 0xe9, 0xef, 0x12, 0x40, 0x7b, 0x31, 0x09, 0x94, 0x8b, 0xa3, 0xf8, 0x41, 0x5a, 0x7d,
+	0x06, 0xbb, 0xcc, 0x5f, 0xbd, 0xfb, 0x0e, 0x5a, 0xf7, 0x51, 0x2d, 0xbd, 0x47, 0x5b, 0xd3, 0x34,
+	0x35, 0x63, 0x64, 0x15, 0xcc, 0xb9, 0x54, 0x6c, 0x9e, 0xa4, 0xa2, 0xad, 0x09, 0xf2, 0x10, 0x76,
+	0x98, 0xaf, 0xe2, 0xec, 0xd3, 0x6d, 0x00, 0x66, 0xca, 0xd2, 0xf7, 0x83, 0x91, 0x29, 0x83, 0xc4,
+	0x06, 0x4b, 0x24, 0x7e, 0xda, 0x26, 0xf8, 0x93, 0x7c, 0x0e, 0x55, 0xff, 0x9a, 0x45, 0x33, 0x8e,
+	0x6f, 0x34, 0xeb, 0xb8, 0x6e, 0x4e, 0xf2, 0x22, 0xe0, 0xe1, 0xa4, 0xa3, 0x79, 0x9a, 0xd9, 0x9b,
+	0xaf, 0xa1, 0x9e, 0xe3, 0x31, 0xf6, 0x14, 0xa1, 0xce, 0xb4, 0x46, 0x0d, 0xc0, 0x0a, 0x8f, 0xcd,
+	0xf7, 0xc5, 0x5c, 0x02, 0x29, 0xd2, 0x27, 0xd5, 0xdf, 0x13, 0x2b, 0x3d, 0x29, 0x82, 0x93, 0xbf,
+	0x4a, 0x70, 0xb8, 0xf5, 0x1e, 0x24, 0x0f, 0xe0, 0x7e, 0xaf, 0x7f, 0x75, 0xe9, 0x52, 0xef, 0x85,
+	0xd7, 0x69, 0x0f, 0xbd, 0x7e, 0xcf, 0xbe, 0x47, 0xf6, 0xa1, 0x36, 0x18, 0x3d, 0x7f, 0xe5, 0x0d,
+	0x87, 0x6e, 0xd7, 0x2e, 0x91, 0x3a, 0x54, 0xdd, 0x57, 0x6d, 0xef, 0xdc, 0xed, 0xda, 0x65, 0x42,
+	0xe0, 0xe0, 0xc2, 0xed, 0x75, 0xbd, 0xde, 0xcb, 0x2b, 0xea, 0x5e, 0x7a, 0xee, 0x1b, 0xdb, 0x22,
+	0x0d, 0xd8, 0x33, 0xbf, 0xdd, 0xae, 0x5d, 0x21, 0x4f, 0xe0, 0x81, 0x37, 0x18, 0x8c, 0xd0, 0xa3,
+	0xe3, 0xd2, 0xa1, 0xd9, 0xd8, 0xb5, 0x77, 0xd0, 0xcd, 0x04, 0x72, 0xbb, 0xf6, 0xae, 0x59, 0xf4,
+	0xbd, 0xdb, 0xc1, 0x18, 0x55, 0x8c, 0x41, 0xdd, 0xcb, 0xfe, 0x99, 0xdb, 0xb5, 0xf7, 0x74, 0x40,
+	0x4a, 0xfb, 0xd4, 0xed, 0xda, 0x35, 0xf4, 0xeb, 0xba, 0xe7, 0xde, 0x00, 0xfd, 0x20, 0x0d, 0xd5,
+	0x3f, 0xf3, 0x7a, 0x2f, 0xed, 0xfa, 0xc9, 0x0c, 0x6a, 0xab, 0xb6, 0x27, 0x87, 0xb0, 0x3f, 0xea,
+	0x9d, 0xf5, 0xfa, 0x6f, 0x7a, 0x57, 0xfa, 0xb8, 0xf6, 0x3d, 0x3c, 0xac, 0x8e, 0xf8, 0xc3, 0x55,
+	0xa7, 0xdf, 0x1b, 0xb6, 0x3b, 0x43, 0xbb, 0x84, 0x9c, 0x39, 0xec, 0x15, 0x75, 0x5f, 0x8f, 0xdc,
+	0xc1, 0xd0, 0x2e, 0x63, 0xc2, 0xe6, 0x2c, 0x98, 0xbf, 0x45, 0x6c, 0x68, 0xe4, 0x4e, 0x3e, 0xb0,
+	0x2b, 0x27, 0xdf, 0x41, 0x3d, 0xd7, 0x31, 0xb8, 0x47, 0x16, 0xaa, 0xdd, 0x49, 0x45, 0xab, 0x43,
+	0xb5, 0x43, 0xdd, 0xf6, 0x4a, 0xb2, 0xd1, 0x45, 0x57, 0x83, 0x32, 0x82, 0xae, 0x7b, 0xee, 0x22,
+	0xb0, 0xc6, 0xbb, 0xfa, 0x5f, 0xce, 0x57, 0xff, 0x0e, 0x00, 0x85, 0x0b, 0x04, 0x43, 0xf5, 0x0c,
+	0x00, 0x00,
 }
@@ -0,0 +1,212 @@
+/*
+Package pkcs decodes the PKCS#12 certificate bundles issued by Sectigo and maps the
+x509 certificates they contain into TRISA certification records.
+*/
+package pkcs
+
+import (
+	"archive/zip"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bbengfort/trisads/pb"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// Errors that may occur when decoding certificate bundles.
+var (
+	ErrNoPKCS12 = errors.New("could not find pkcs12 file in batch archive")
+)
+
+// Object identifiers of distinguished name attributes that are not parsed by pkix.Name
+var (
+	oidBusinessCategory = asn1.ObjectIdentifier{2, 5, 4, 15}
+	oidIncStateProvince = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 60, 2, 1, 2}
+	oidIncCountryRegion = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 311, 60, 2, 1, 3}
+)
+
+// File extensions of PKCS#12 files in the batch archive
+var extensions = []string{".p12", ".pfx"}
+
+// Names of the key usage bits in the order they are defined by x509.KeyUsage
+var keyUsageNames = []string{
+	"digitalSignature", "contentCommitment", "keyEncipherment", "dataEncipherment",
+	"keyAgreement", "keyCertSign", "cRLSign", "encipherOnly", "decipherOnly",
+}
+
+// Names of the extended key usages as defined by RFC 5280
+var extKeyUsageNames = map[x509.ExtKeyUsage]string{
+	x509.ExtKeyUsageAny:             "any",
+	x509.ExtKeyUsageServerAuth:      "serverAuth",
+	x509.ExtKeyUsageClientAuth:      "clientAuth",
+	x509.ExtKeyUsageCodeSigning:     "codeSigning",
+	x509.ExtKeyUsageEmailProtection: "emailProtection",
+	x509.ExtKeyUsageTimeStamping:    "timeStamping",
+	x509.ExtKeyUsageOCSPSigning:     "OCSPSigning",
+}
+
+// Bundle contains the decrypted contents of a PKCS#12 file: the private key, the
+// certificate issued for the key, and the certificate authority chain.
+type Bundle struct {
+	PrivateKey  interface{}
+	Certificate *x509.Certificate
+	CACerts     []*x509.Certificate
+}
+
+// Open a batch ZIP file downloaded from Sectigo and decrypt the PKCS#12 file it contains
+// using the pkcs12Password profile param that the batch was created with.
+func Open(path, password string) (_ *Bundle, err error) {
+	var archive *zip.ReadCloser
+	if archive, err = zip.OpenReader(path); err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	for _, f := range archive.File {
+		if !isPKCS12(f.Name) {
+			continue
+		}
+
+		var data []byte
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err = ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		var bundle *Bundle
+		if bundle, err = Decode(data, password); err != nil {
+			return nil, fmt.Errorf("could not decode %s: %s", f.Name, err)
+		}
+		return bundle, nil
+	}
+
+	return nil, ErrNoPKCS12
+}
+
+// Decode PKCS#12 data that is encrypted with the specified password.
+func Decode(data []byte, password string) (_ *Bundle, err error) {
+	bundle := &Bundle{}
+	if bundle.PrivateKey, bundle.Certificate, bundle.CACerts, err = pkcs12.DecodeChain(data, password); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// Certification returns the TRISA certification record for the bundle's certificate.
+func (b *Bundle) Certification() *pb.TRISACertification {
+	return Certification(b.Certificate)
+}
+
+// Certification maps an x509 certificate into a TRISA certification record.
+func Certification(cert *x509.Certificate) *pb.TRISACertification {
+	return &pb.TRISACertification{
+		SubjectName:        Name(cert.Subject),
+		IssuerName:         Name(cert.Issuer),
+		SerialNumber:       cert.SerialNumber.Bytes(),
+		Version:            fmt.Sprintf("%d", cert.Version),
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+		NotValidBefore:     cert.NotBefore.Format(time.RFC3339),
+		NotValidAfter:      cert.NotAfter.Format(time.RFC3339),
+		PublicKeyInfo:      PublicKeyInfo(cert),
+	}
+}
+
+// Name maps an x509 distinguished name into a TRISA name record. Multi-valued
+// attributes are joined with a comma.
+func Name(name pkix.Name) *pb.Name {
+	n := &pb.Name{
+		CommonName:         name.CommonName,
+		CountryRegion:      strings.Join(name.Country, ", "),
+		Organization:       strings.Join(name.Organization, ", "),
+		OrganizationalUnit: strings.Join(name.OrganizationalUnit, ", "),
+		Locality:           strings.Join(name.Locality, ", "),
+		StateProvince:      strings.Join(name.Province, ", "),
+		SerialNumber:       name.SerialNumber,
+	}
+
+	// Extended validation attributes must be read from the raw attributes
+	for _, attr := range name.Names {
+		value, ok := attr.Value.(string)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case attr.Type.Equal(oidBusinessCategory):
+			n.BusinessCategory = value
+		case attr.Type.Equal(oidIncStateProvince):
+			n.IncStateProvince = value
+		case attr.Type.Equal(oidIncCountryRegion):
+			n.IncCountryRegion = value
+		}
+	}
+	return n
+}
+
+// PublicKeyInfo maps the public key and usage of an x509 certificate into a TRISA
+// public key info record, including the certificate signature.
+func PublicKeyInfo(cert *x509.Certificate) *pb.PublicKeyInfo {
+	info := &pb.PublicKeyInfo{
+		Algorithm: cert.PublicKeyAlgorithm.String(),
+		PublicKey: cert.RawSubjectPublicKeyInfo,
+		KeyUsage:  KeyUsage(cert),
+		Signature: cert.Signature,
+	}
+
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		info.Exponent = int64(key.E)
+		info.KeySize = int64(key.N.BitLen())
+	case *ecdsa.PublicKey:
+		info.KeySize = int64(key.Curve.Params().BitSize)
+		info.Parameters = []string{key.Curve.Params().Name}
+	case ed25519.PublicKey:
+		info.KeySize = int64(len(key) * 8)
+	}
+
+	return info
+}
+
+// KeyUsage returns the names of the key usages and extended key usages of the
+// certificate as defined by RFC 5280.
+func KeyUsage(cert *x509.Certificate) []string {
+	usage := make([]string, 0)
+	for i, name := range keyUsageNames {
+		if cert.KeyUsage&(1<<uint(i)) != 0 {
+			usage = append(usage, name)
+		}
+	}
+
+	for _, ext := range cert.ExtKeyUsage {
+		if name, ok := extKeyUsageNames[ext]; ok {
+			usage = append(usage, name)
+		}
+	}
+	return usage
+}
+
+// returns true if the file name has a PKCS#12 file extension
+func isPKCS12(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, suffix := range extensions {
+		if ext == suffix {
+			return true
+		}
+	}
+	return false
+}
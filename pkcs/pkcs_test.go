@@ -0,0 +1,122 @@
+package pkcs
+
+import (
+	"archive/zip"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func TestOpen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pkcs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cert, key := makeCertificate(t)
+	data, err := pkcs12.Encode(rand.Reader, key, cert, nil, "supersecret")
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "batch.zip")
+	writeArchive(t, path, "example.com.p12", data)
+
+	// Decoding with the wrong password should fail
+	_, err = Open(path, "wrongpassword")
+	require.Error(t, err)
+
+	bundle, err := Open(path, "supersecret")
+	require.NoError(t, err)
+	require.Equal(t, cert.Raw, bundle.Certificate.Raw)
+
+	record := bundle.Certification()
+	require.Equal(t, "example.com", record.SubjectName.CommonName)
+	require.Equal(t, "US", record.SubjectName.CountryRegion)
+	require.Equal(t, "Example, Inc.", record.SubjectName.Organization)
+	require.Equal(t, "Delaware", record.SubjectName.IncStateProvince)
+	require.Equal(t, "Private Organization", record.SubjectName.BusinessCategory)
+	require.Equal(t, "TRISA Test CA", record.IssuerName.CommonName)
+	require.Equal(t, []byte{0x1, 0x2, 0x3}, record.SerialNumber)
+	require.Equal(t, "3", record.Version)
+	require.Equal(t, "SHA256-RSA", record.SignatureAlgorithm)
+	require.Equal(t, "2020-01-01T00:00:00Z", record.NotValidBefore)
+	require.Equal(t, "2021-01-01T00:00:00Z", record.NotValidAfter)
+
+	info := record.PublicKeyInfo
+	require.Equal(t, "RSA", info.Algorithm)
+	require.Equal(t, int64(2048), info.KeySize)
+	require.Equal(t, int64(65537), info.Exponent)
+	require.Equal(t, cert.RawSubjectPublicKeyInfo, info.PublicKey)
+	require.Equal(t, cert.Signature, info.Signature)
+	require.Equal(t, []string{"digitalSignature", "keyEncipherment", "serverAuth", "clientAuth"}, info.KeyUsage)
+}
+
+func TestOpenNoPKCS12(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pkcs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "batch.zip")
+	writeArchive(t, path, "README.txt", []byte("not a certificate"))
+
+	_, err = Open(path, "supersecret")
+	require.Equal(t, ErrNoPKCS12, err)
+}
+
+// creates a certificate for example.com signed by a throwaway CA key.
+func makeCertificate(t *testing.T) (*x509.Certificate, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(0x010203),
+		Subject: pkix.Name{
+			CommonName:   "example.com",
+			Country:      []string{"US"},
+			Organization: []string{"Example, Inc."},
+			ExtraNames: []pkix.AttributeTypeAndValue{
+				{Type: oidIncStateProvince, Value: "Delaware"},
+				{Type: oidBusinessCategory, Value: "Private Organization"},
+			},
+		},
+		Issuer:      pkix.Name{CommonName: "TRISA Test CA"},
+		NotBefore:   time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:    time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	parent := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "TRISA Test CA"},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+// writes a zip archive containing a single file to the specified path.
+func writeArchive(t *testing.T, path, name string, data []byte) {
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	archive := zip.NewWriter(f)
+	w, err := archive.Create(name)
+	require.NoError(t, err)
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, archive.Close())
+}
@@ -6,9 +6,11 @@ package sectigo
 
 import (
 	"bytes"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/big"
 	"mime"
 	"net/http"
 	"os"
@@ -549,6 +551,24 @@ func (s *Sectigo) Creds() Credentials {
 	return *s.creds
 }
 
+const pwcharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789#$%&*-<>~"
+
+// RandomPassword generates a password of the specified length that is suitable for use
+// as the pkcs12Password profile param when creating a certificate batch. This function
+// panics if the system's secure random number generator fails.
+func RandomPassword(length int) string {
+	max := big.NewInt(int64(len(pwcharset)))
+	buf := make([]byte, length)
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(fmt.Errorf("could not generate random password: %s", err))
+		}
+		buf[i] = pwcharset[n.Int64()]
+	}
+	return string(buf)
+}
+
 // Returns a request with default headers set along with the authentication header.
 // If the client has not been authenticated, then an error is returned.
 func (s *Sectigo) newRequest(method, url string, data interface{}) (req *http.Request, err error) {
@@ -0,0 +1,139 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/gogo/protobuf/jsonpb"
+)
+
+// Actor identifies who made a change to a VASP record, it is recorded in the audit
+// history of the VASP along with the change.
+type Actor struct {
+	Name    string // the identity of the client or the background process
+	Address string // the network address of the client, if any
+	RPC     string // the RPC (or the process) that made the change
+}
+
+// Value of secret fields in the audit history, which only records that they changed.
+const redacted = `"[redacted]"`
+
+// Fields whose values are redacted or that are omitted from the audit history.
+var (
+	auditRedacted = map[string]bool{"verificationToken": true, "pkcs12Password": true}
+	auditOmitted  = map[string]bool{"lastUpdated": true, "version": true}
+)
+
+// creates the audit entry for a change to a VASP by the actor; before is nil if the
+// VASP was created and after is nil if it was deleted. The store assigns the entry id.
+func newAuditEntry(action pb.AuditAction, before, after *pb.VASP, actor Actor) (entry *pb.AuditEntry, err error) {
+	entry = &pb.AuditEntry{
+		Action:    action,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Actor:     actor.Name,
+		Address:   actor.Address,
+		Rpc:       actor.RPC,
+	}
+
+	if after != nil {
+		entry.Vasp = after.Id
+	} else if before != nil {
+		entry.Vasp = before.Id
+	}
+
+	if entry.Changes, err = diff(before, after); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// diff returns the changes to the fields of a VASP ordered by their JSON path.
+func diff(before, after *pb.VASP) (changes []*pb.FieldChange, err error) {
+	var a, b map[string]string
+	if a, err = flatten(before); err != nil {
+		return nil, err
+	}
+	if b, err = flatten(after); err != nil {
+		return nil, err
+	}
+
+	for field, value := range a {
+		if b[field] != value {
+			changes = append(changes, change(field, value, b[field]))
+		}
+	}
+
+	for field, value := range b {
+		if _, ok := a[field]; !ok {
+			changes = append(changes, change(field, "", value))
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+// creates the change to the field, redacting the values of secret fields so that the
+// history only records that a secret was set, changed or removed.
+func change(field, before, after string) *pb.FieldChange {
+	if auditRedacted[field] {
+		if before != "" {
+			before = redacted
+		}
+		if after != "" {
+			after = redacted
+		}
+	}
+	return &pb.FieldChange{Field: field, Before: before, After: after}
+}
+
+// flattens the JSON representation of a VASP into a map of the JSON encoded values of
+// its fields by their path, e.g. vaspEntity.vaspURL. Lists are not flattened.
+func flatten(v *pb.VASP) (fields map[string]string, err error) {
+	fields = make(map[string]string)
+	if v == nil {
+		return fields, nil
+	}
+
+	var buf bytes.Buffer
+	if err = new(jsonpb.Marshaler).Marshal(&buf, v); err != nil {
+		return nil, err
+	}
+
+	var obj map[string]interface{}
+	if err = json.Unmarshal(buf.Bytes(), &obj); err != nil {
+		return nil, err
+	}
+
+	if err = flattenObject("", obj, fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// adds the fields of the JSON object to the map, prefixing their path
+func flattenObject(prefix string, obj map[string]interface{}, fields map[string]string) error {
+	for key, val := range obj {
+		path := prefix + key
+		if auditOmitted[path] {
+			continue
+		}
+
+		if nested, ok := val.(map[string]interface{}); ok {
+			if err := flattenObject(path+".", nested, fields); err != nil {
+				return err
+			}
+			continue
+		}
+
+		data, err := json.Marshal(val)
+		if err != nil {
+			return err
+		}
+		fields[path] = string(data)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/bbengfort/trisads/pb"
+)
+
+// Index identifies a unique secondary index of the VASP records. No two VASPs may have
+// the same (normalized) key in a unique index, so a VASP can be looked up by its key.
+// VASPs that do not have a value for the indexed field are not indexed.
+type Index uint8
+
+// The unique indices of the VASP records.
+const (
+	NameIndex       Index = iota + 1 // the normalized full legal name of the VASP
+	LEIIndex                         // the LEI number of the VASP
+	DomainIndex                      // the host of the VASP URL without the www subdomain
+	CommonNameIndex                  // the common name of the VASP's TRISA certificate
+	SerialIndex                      // the hex serial number of the VASP's TRISA certificate
+)
+
+// UniqueIndices are all of the unique indices that storage backends must maintain.
+var UniqueIndices = []Index{NameIndex, LEIIndex, DomainIndex, CommonNameIndex, SerialIndex}
+
+// Normalize returns the key of a value of the indexed field, so that e.g. lookups by
+// LEI are not sensitive to formatting. Keys that are empty are not indexed.
+func (i Index) Normalize(value string) string {
+	switch i {
+	case NameIndex:
+		return NormalizeName(value)
+	case LEIIndex:
+		return leiKey(value)
+	case DomainIndex:
+		return domainKey(value)
+	case CommonNameIndex:
+		return strings.ToLower(strings.TrimSpace(value))
+	case SerialIndex:
+		return serialKey(value)
+	default:
+		return ""
+	}
+}
+
+// Key returns the key of the VASP in the index or an empty string if the VASP does not
+// have a value for the indexed field.
+func (i Index) Key(v *pb.VASP) string {
+	if v.VaspEntity == nil {
+		return ""
+	}
+
+	switch i {
+	case NameIndex:
+		return i.Normalize(v.VaspEntity.VaspFullLegalName)
+	case LEIIndex:
+		return i.Normalize(v.VaspEntity.VaspLEINumber)
+	case DomainIndex:
+		return i.Normalize(v.VaspEntity.VaspURL)
+	case CommonNameIndex:
+		if cert := v.VaspTRISACertification; cert != nil && cert.SubjectName != nil {
+			return i.Normalize(cert.SubjectName.CommonName)
+		}
+	case SerialIndex:
+		if cert := v.VaspTRISACertification; cert != nil && len(cert.SerialNumber) > 0 {
+			return i.Normalize(hex.EncodeToString(cert.SerialNumber))
+		}
+	}
+	return ""
+}
+
+// changedIndices returns the unique indices in which the key of the updated VASP differs
+// from the key of the original VASP, which are the only entries an update has to change.
+func changedIndices(o, v *pb.VASP) (indices []Index) {
+	for _, index := range UniqueIndices {
+		if index.Key(o) != index.Key(v) {
+			indices = append(indices, index)
+		}
+	}
+	return indices
+}
+
+// String returns the plural name of the index, e.g. for use in database keys or reports.
+func (i Index) String() string {
+	switch i {
+	case NameIndex:
+		return "names"
+	case LEIIndex:
+		return "leis"
+	case DomainIndex:
+		return "domains"
+	case CommonNameIndex:
+		return "commonnames"
+	case SerialIndex:
+		return "serials"
+	default:
+		return "unknown"
+	}
+}
+
+// returns the lowercase hex serial number without separators or leading zeros, e.g.
+// "00:1A:2B" and "1a2b" are both "1a2b"
+func serialKey(serial string) string {
+	serial = strings.NewReplacer(":", "", " ", "", "-", "").Replace(strings.TrimSpace(serial))
+	serial = strings.TrimPrefix(strings.ToLower(serial), "0x")
+	return strings.TrimLeft(serial, "0")
+}
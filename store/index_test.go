@@ -0,0 +1,76 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexKeys(t *testing.T) {
+	vasp := &pb.VASP{
+		VaspEntity: &pb.Entity{
+			VaspFullLegalName: "Example Exchange Limited",
+			VaspLEINumber:     "5493 001K JTII GC8Y 1R12",
+			VaspURL:           "https://WWW.Example.com/about",
+		},
+		VaspTRISACertification: &pb.TRISACertification{
+			SubjectName:  &pb.Name{CommonName: " Trisa.Example.com"},
+			SerialNumber: []byte{0x00, 0x1a, 0x2b, 0x3c},
+		},
+	}
+
+	require.Equal(t, "example exchange ltd", NameIndex.Key(vasp))
+	require.Equal(t, "5493001KJTIIGC8Y1R12", LEIIndex.Key(vasp))
+	require.Equal(t, "example.com", DomainIndex.Key(vasp))
+	require.Equal(t, "trisa.example.com", CommonNameIndex.Key(vasp))
+	require.Equal(t, "1a2b3c", SerialIndex.Key(vasp))
+
+	// Lookup values are normalized in the same way as the keys
+	require.Equal(t, NameIndex.Key(vasp), NameIndex.Normalize("EXAMPLE EXCHANGE LTD."))
+	require.Equal(t, LEIIndex.Key(vasp), LEIIndex.Normalize("5493001kjtiigc8y1r12"))
+	require.Equal(t, DomainIndex.Key(vasp), DomainIndex.Normalize("example.com"))
+	require.Equal(t, DomainIndex.Key(vasp), DomainIndex.Normalize("http://www.example.com"))
+	require.Equal(t, CommonNameIndex.Key(vasp), CommonNameIndex.Normalize("TRISA.EXAMPLE.COM"))
+	require.Equal(t, SerialIndex.Key(vasp), SerialIndex.Normalize("00:1A:2B:3C"))
+	require.Equal(t, SerialIndex.Key(vasp), SerialIndex.Normalize("0x1A2B3C"))
+
+	// Missing fields are not indexed
+	vasp = &pb.VASP{VaspEntity: &pb.Entity{VaspFullLegalName: "Example"}}
+	for _, index := range UniqueIndices[1:] {
+		require.Empty(t, index.Key(vasp), "%s should not be indexed", index)
+	}
+	require.Empty(t, NameIndex.Key(&pb.VASP{}))
+}
+
+func TestChangedIndices(t *testing.T) {
+	o := &pb.VASP{
+		VaspEntity: &pb.Entity{
+			VaspFullLegalName: "Example Exchange Limited",
+			VaspLEINumber:     "5493001KJTIIGC8Y1R12",
+			VaspURL:           "https://example.com",
+		},
+		VaspTRISACertification: &pb.TRISACertification{
+			SubjectName: &pb.Name{CommonName: "trisa.example.com"},
+		},
+	}
+
+	// Changes that do not change the normalized keys do not change the indices
+	v := &pb.VASP{
+		VaspEntity: &pb.Entity{
+			VaspFullLegalName: "EXAMPLE EXCHANGE LTD.",
+			VaspLEINumber:     "5493 001K JTII GC8Y 1R12",
+			VaspURL:           "https://www.example.com/about",
+			VaspCountry:       "US",
+		},
+		VaspTRISACertification: &pb.TRISACertification{
+			SubjectName: &pb.Name{CommonName: "TRISA.example.com"},
+		},
+	}
+	require.Empty(t, changedIndices(o, v))
+
+	v.VaspEntity.VaspURL = "https://example.io"
+	v.VaspEntity.VaspLEINumber = ""
+	v.VaspTRISACertification.SerialNumber = []byte{0x1a}
+	require.Equal(t, []Index{LEIIndex, DomainIndex, SerialIndex}, changedIndices(o, v))
+}
@@ -2,7 +2,6 @@ package store
 
 import (
 	"encoding/binary"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
@@ -15,6 +14,7 @@ import (
 	"github.com/gogo/protobuf/proto"
 	log "github.com/sirupsen/logrus"
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 // OpenLevelDB directory Store at the specified path. This is the default storage provider.
@@ -44,14 +44,21 @@ var (
 	ErrIncompleteRecord  = errors.New("vasp record is missing required fields")
 	ErrEntityNotFound    = errors.New("entity not found")
 	ErrDuplicateEntity   = errors.New("entity unique constraints violated")
+	ErrInvalidTransition = errors.New("invalid verification state transition")
+	ErrConflict          = errors.New("vasp record was modified by a concurrent update")
+	ErrEmptyQuery        = errors.New("search query has no conditions")
 )
 
 // keys and prefixes for leveldb buckets and indices
 var (
 	keyAutoSequence = []byte("pks")
-	keyNameIndex    = []byte("names")
-	keyCountryIndex = []byte("countries")
+	keyNameIndex    = []byte("names")     // deprecated JSON index, migrated on open
+	keyCountryIndex = []byte("countries") // deprecated JSON index, migrated on open
 	preVASPS        = []byte("vasps")
+	preCertReqs     = []byte("certreqs")
+	preEmails       = []byte("emails")
+	preAudit        = []byte("audit::")
+	preCountryIndex = []byte("index::countries::")
 )
 
 // Implements Store for some basic LevelDB operations and simple protocol buffer storage.
@@ -59,41 +66,60 @@ type ldbStore struct {
 	sync.RWMutex
 	db        *leveldb.DB
 	sequence  uint64         // autoincrement sequence for ID values
-	names     uniqueIndex    // case insensitive name index
+	unique    uniqueIndices  // normalized name, LEI, domain, common name and serial indices
 	countries containerIndex // lookup vasps in a specific country
 }
 
-// Close the database, allowing no further interactions. This method also synchronizes
-// the indices to ensure that they are saved between sessions.
+// Close the database, allowing no further interactions. The indices and sequence are
+// written with every change so they do not need to be synchronized on close.
 func (s *ldbStore) Close() error {
-	defer s.db.Close()
-	if err := s.sync(); err != nil {
-		return err
-	}
-	return nil
+	return s.db.Close()
 }
 
 // Create a VASP into the directory. This method requires the VASP to have a unique
-// name and ignores any ID fields that are set on the VASP, instead assigning new IDs.
-func (s *ldbStore) Create(v pb.VASP) (id uint64, err error) {
-	// Create the name to check the uniqueness constraint
-	name := strings.TrimSpace(strings.ToLower(v.VaspEntity.VaspFullLegalName))
-	if name == "" {
+// name (as well as a unique LEI, domain, certificate common name and certificate serial
+// number if they are set) and ignores any ID fields that are set on the VASP, instead
+// assigning new IDs.
+func (s *ldbStore) Create(v pb.VASP, a Actor) (id uint64, err error) {
+	return s.create(v, nil, a)
+}
+
+// CreateWithEmail creates a VASP and queues the email in the outbox in a single batch
+// write, so that either both the record and the email are stored or neither are.
+func (s *ldbStore) CreateWithEmail(v pb.VASP, e pb.Email, a Actor) (id uint64, err error) {
+	return s.create(v, &e, a)
+}
+
+// creates the VASP record, writing the optional email about the VASP and the audit
+// entry of the actor in the same batch.
+func (s *ldbStore) create(v pb.VASP, e *pb.Email, a Actor) (id uint64, err error) {
+	if v.VaspEntity == nil {
 		return 0, ErrIncompleteRecord
 	}
 
+	// The name is required for the uniqueness constraint
+	if NameIndex.Key(&v) == "" {
+		return 0, ErrIncompleteRecord
+	}
+
+	// New records must start at the beginning of the verification workflow
+	if !v.VerificationStatus.Initial() {
+		return 0, ErrInvalidTransition
+	}
+
 	// Update management timestamps
 	v.LastUpdated = time.Now().Format(time.RFC3339)
 	if v.FirstListed == "" {
 		v.FirstListed = v.LastUpdated
 	}
+	v.Version = 1
 
 	// Critical section (optimizing for safety rather than speed)
 	s.Lock()
 	defer s.Unlock()
 
-	// Check the uniqueness constraint
-	if _, ok := s.names[name]; ok {
+	// Check the uniqueness constraints
+	if s.unique.conflicts(&v) {
 		return 0, ErrDuplicateEntity
 	}
 
@@ -101,17 +127,39 @@ func (s *ldbStore) Create(v pb.VASP) (id uint64, err error) {
 	s.checkIDs(&v, true)
 
 	var data []byte
-	key := s.vaspKey(v.Id)
+	batch := new(leveldb.Batch)
 	if data, err = proto.Marshal(&v); err != nil {
 		return 0, err
 	}
+	batch.Put(s.vaspKey(v.Id), data)
 
-	if err = s.db.Put(key, data, nil); err != nil {
+	if e != nil {
+		s.sequence++
+		e.Id = s.sequence
+		e.Vasp = v.Id
+		if e.Created == "" {
+			e.Created = v.LastUpdated
+		}
+
+		if data, err = proto.Marshal(e); err != nil {
+			return 0, err
+		}
+		batch.Put(s.emailKey(e.Id), data)
+	}
+
+	if err = s.putAudit(batch, pb.AuditAction_CREATED, nil, &v, a); err != nil {
+		return 0, err
+	}
+
+	// Write the index entries and the sequence with the records
+	s.putIndices(batch, v)
+	s.putSequence(batch)
+	if err = s.db.Write(batch, nil); err != nil {
 		return 0, err
 	}
 
 	// Update indices after successful insert
-	s.names[name] = v.Id
+	s.unique.add(&v)
 	s.countries.add(v.Id, v.VaspEntity.VaspCountry)
 	return v.Id, nil
 }
@@ -134,54 +182,95 @@ func (s *ldbStore) Retrieve(id uint64) (v pb.VASP, err error) {
 	return v, nil
 }
 
+// Lookup a VASP record by its key in one of the unique indices, e.g. by LEI number. The
+// key is normalized in the same manner as the index; returns ErrEntityNotFound if no
+// VASP has the key.
+func (s *ldbStore) Lookup(index Index, key string) (v pb.VASP, err error) {
+	if key = index.Normalize(key); key == "" {
+		return v, ErrEntityNotFound
+	}
+
+	s.RLock()
+	id, ok := s.unique[index][key]
+	s.RUnlock()
+
+	if !ok {
+		return v, ErrEntityNotFound
+	}
+	return s.Retrieve(id)
+}
+
 // Update the VASP entry by the VASP ID (required). This method simply overwrites the
-// entire VASP record and does not update individual fields.
-func (s *ldbStore) Update(v pb.VASP) (err error) {
-	if v.Id == 0 {
+// entire VASP record and does not update individual fields. The version of the update
+// must match the stored record, otherwise ErrConflict is returned.
+func (s *ldbStore) Update(v pb.VASP, a Actor) (err error) {
+	if v.Id == 0 || v.VaspEntity == nil {
 		return ErrIncompleteRecord
 	}
 
+	if NameIndex.Key(&v) == "" {
+		return ErrIncompleteRecord
+	}
+
+	// Critical section (optimizing for safety rather than speed)
+	s.Lock()
+	defer s.Unlock()
+
 	// Retrieve the original record to ensure that the indices are updated properly
-	key := s.vaspKey(v.Id)
 	o, err := s.Retrieve(v.Id)
 	if err != nil {
 		return err
 	}
 
-	// Critical section (optimizing for safety rather than speed)
-	s.Lock()
-	defer s.Unlock()
+	// Updates must be made to the current version of the record (compare-and-swap)
+	if v.Version != o.Version {
+		return ErrConflict
+	}
+
+	// Ensure the verification state machine is respected
+	if !o.VerificationStatus.CanTransition(v.VerificationStatus) {
+		return ErrInvalidTransition
+	}
+
+	// Check the uniqueness constraints if the VASP is being renamed, etc.
+	if s.unique.conflicts(&v) {
+		return ErrDuplicateEntity
+	}
 
 	// Check to ensure all subrecords have unique identifiers if not already set
 	s.checkIDs(&v, false)
+	v.LastUpdated = time.Now().Format(time.RFC3339)
+	v.Version = o.Version + 1
 
 	var val []byte
 	if val, err = proto.Marshal(&v); err != nil {
 		return err
 	}
 
-	// Insert the new record
-	if err = s.db.Put(key, val, nil); err != nil {
+	// Write the record, index changes, and sequence in a single batch
+	batch := new(leveldb.Batch)
+	batch.Put(s.vaspKey(v.Id), val)
+	if err = s.putAudit(batch, pb.AuditAction_UPDATED, &o, &v, a); err != nil {
 		return err
 	}
-
-	// Update indices if necessary
-	if v.VaspEntity.VaspFullLegalName != o.VaspEntity.VaspFullLegalName {
-		delete(s.names, o.VaspEntity.VaspFullLegalName)
-		s.names[v.VaspEntity.VaspFullLegalName] = v.Id
-	}
-
-	if v.VaspEntity.VaspCountry != o.VaspEntity.VaspCountry {
-		s.countries.rm(o.Id, o.VaspEntity.VaspCountry)
-		s.countries.add(v.Id, v.VaspEntity.VaspCountry)
+	s.updateIndices(batch, o, v)
+	s.putSequence(batch)
+	if err = s.db.Write(batch, nil); err != nil {
+		return err
 	}
 
+	// Update indices after successful write
+	s.unique.update(&o, &v)
+	s.countries.update(v.Id, o.VaspEntity.VaspCountry, v.VaspEntity.VaspCountry)
 	return nil
 }
 
-// Destroy a record, removing it completely from the database and indices.
-func (s *ldbStore) Destroy(id uint64) (err error) {
-	key := s.vaspKey(id)
+// Destroy a record, removing it completely from the database and indices. The audit
+// history of the record is retained.
+func (s *ldbStore) Destroy(id uint64, a Actor) (err error) {
+	// Critical section (optimizing for safety rather than speed)
+	s.Lock()
+	defer s.Unlock()
 
 	// Lookup the record in order to remove data from indices
 	record, err := s.Retrieve(id)
@@ -192,57 +281,105 @@ func (s *ldbStore) Destroy(id uint64) (err error) {
 		return err
 	}
 
-	// LevelDB will not return an error if the entity does not exist
-	if err = s.db.Delete(key, nil); err != nil {
+	// Remove the record and its index entries in a single batch
+	batch := new(leveldb.Batch)
+	batch.Delete(s.vaspKey(id))
+	if err = s.putAudit(batch, pb.AuditAction_DELETED, &record, nil, a); err != nil {
+		return err
+	}
+	s.deleteIndices(batch, record)
+	s.putSequence(batch)
+	if err = s.db.Write(batch, nil); err != nil {
 		return err
 	}
-
-	// Critical section (optimizing for safety rather than speed)
-	s.Lock()
-	defer s.Unlock()
 
 	// Remove the records from the indices
-	delete(s.names, record.VaspEntity.VaspFullLegalName)
+	s.unique.rm(&record)
 	s.countries.rm(id, record.VaspEntity.VaspCountry)
 	return nil
 }
 
-// Search uses the names and countries index to find VASPS that match the specified
-// query. This is a very simple search and is not intended for robust usage. To find a
-// VASP by name, a case insensitive search is performed if the query exists in
-// any of the VASP entity names. Alternatively a list of names can be given or a country
-// or list of countries for case-insensitive exact matches.
-func (s *ldbStore) Search(query map[string]interface{}) (vasps []pb.VASP, err error) {
-	// A set of records that match the query and need to be fetched
-	records := make(map[uint64]struct{})
+// List all of the VASP records in the database in ascending ID order.
+func (s *ldbStore) List() (vasps []pb.VASP, err error) {
+	iter := s.db.NewIterator(util.BytesPrefix(preVASPS), nil)
+	defer iter.Release()
 
-	s.RLock()
-	// Lookup by name
-	names, ok := parseQuery("name", query)
-	if ok {
-		log.WithField("name", names).Debug("search name query")
-		for _, name := range names {
-			if id := s.names[name]; id > 0 {
-				records[id] = struct{}{}
-			}
+	vasps = make([]pb.VASP, 0)
+	for iter.Next() {
+		var vasp pb.VASP
+		if err = proto.Unmarshal(iter.Value(), &vasp); err != nil {
+			return nil, err
 		}
+		vasps = append(vasps, vasp)
 	}
 
-	// Lookup by country
-	countries, ok := parseQuery("country", query)
-	if ok {
-		for _, country := range countries {
-			for _, id := range s.countries[country] {
-				records[id] = struct{}{}
-			}
+	if err = iter.Error(); err != nil {
+		return nil, err
+	}
+
+	// The uvarint encoded keys are not ordered numerically above 127
+	sort.Slice(vasps, func(i, j int) bool { return vasps[i].Id < vasps[j].Id })
+	return vasps, nil
+}
+
+// ListAfter returns up to limit VASP records with an ID greater than the specified ID in
+// ascending ID order, or all of them if the limit is zero. Since the uvarint encoded keys
+// are not ordered numerically, the keys are scanned to find the IDs, but only the records
+// that are returned are read and unmarshaled, from a snapshot of the database.
+func (s *ldbStore) ListAfter(after uint64, limit int) (vasps []pb.VASP, err error) {
+	var snap *leveldb.Snapshot
+	if snap, err = s.db.GetSnapshot(); err != nil {
+		return nil, err
+	}
+	defer snap.Release()
+
+	ids := make([]uint64, 0)
+	iter := snap.NewIterator(util.BytesPrefix(preVASPS), nil)
+	for iter.Next() {
+		if id, n := binary.Uvarint(iter.Key()[len(preVASPS):]); n > 0 && id > after {
+			ids = append(ids, id)
 		}
 	}
-	s.RUnlock()
+	iter.Release()
 
-	// Perform the lookup of records if there are any
-	if len(records) > 0 {
-		vasps = make([]pb.VASP, 0, len(records))
-		for id := range records {
+	if err = iter.Error(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	if limit > 0 && len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	vasps = make([]pb.VASP, len(ids))
+	for i, id := range ids {
+		var val []byte
+		if val, err = snap.Get(s.vaspKey(id), nil); err != nil {
+			return nil, err
+		}
+
+		if err = proto.Unmarshal(val, &vasps[i]); err != nil {
+			return nil, err
+		}
+	}
+	return vasps, nil
+}
+
+// Search returns the VASPs that match the query, evaluating the query against each
+// VASP record with Match and ordering the results by descending relevance. If the query
+// requires a name or country match, the names and countries indices are used to find
+// the VASPs that must be evaluated, otherwise all VASP records are scanned. Names are
+// matched by their Relevance to the query, so the name index is scanned rather than
+// looked up; prefixes, substrings and names with small typos are all matched.
+func (s *ldbStore) Search(query *pb.Query) (results []SearchResult, err error) {
+	if err = CheckQuery(query); err != nil {
+		return nil, err
+	}
+
+	var vasps []pb.VASP
+	if candidates, ok := s.candidates(query); ok {
+		vasps = make([]pb.VASP, 0, len(candidates))
+		for id := range candidates {
 			var vasp pb.VASP
 			if vasp, err = s.Retrieve(id); err != nil {
 				if err == ErrEntityNotFound {
@@ -252,196 +389,846 @@ func (s *ldbStore) Search(query map[string]interface{}) (vasps []pb.VASP, err er
 			}
 			vasps = append(vasps, vasp)
 		}
+	} else {
+		if vasps, err = s.List(); err != nil {
+			return nil, err
+		}
 	}
 
-	return vasps, nil
+	results = make([]SearchResult, 0)
+	for i := range vasps {
+		if ok, score := Match(query, &vasps[i]); ok {
+			results = append(results, SearchResult{VASP: vasps[i], Score: score})
+		}
+	}
+
+	SortResults(results)
+	return results, nil
 }
 
-// creates a []byte key from the vasp id using a prefix to act as a leveldb bucket
-func (s *ldbStore) vaspKey(id uint64) (key []byte) {
-	pre := len(preVASPS)
-	key = make([]byte, pre+binary.MaxVarintLen64)
-	copy(key, preVASPS)
-	binary.PutUvarint(key[pre:], id)
-	return key
+// uses the indices to find the ids of the VASPs that may match the query.
+func (s *ldbStore) candidates(query *pb.Query) (ids map[uint64]struct{}, ok bool) {
+	s.RLock()
+	defer s.RUnlock()
+	return indexCandidates(query, s.unique, s.countries)
 }
 
-// updates the ids of internal objects using the primary key sequence
-func (s *ldbStore) checkIDs(v *pb.VASP, insert bool) {
-	if insert || v.Id == 0 {
-		s.sequence++
-		v.Id = s.sequence
+// uses the name and country indices to find the ids of the VASPs that may match the
+// query. If the query does not require a name or country match, false is returned and
+// all VASPs must be evaluated. The candidates still have to be evaluated against the
+// entire query.
+func indexCandidates(query *pb.Query, unique uniqueIndices, countries containerIndex) (ids map[uint64]struct{}, ok bool) {
+	if query.Operator != pb.Query_AND || (len(query.Name) == 0 && len(query.Country) == 0) {
+		return nil, false
 	}
 
-	if v.VaspEntity != nil {
-		if insert || v.VaspEntity.Id == 0 {
-			s.sequence++
-			v.VaspEntity.Id = s.sequence
+	if len(query.Name) > 0 {
+		ids = make(map[uint64]struct{})
+		for _, name := range query.Name {
+			name = NormalizeName(name)
+			for indexed, id := range unique[NameIndex] {
+				if Relevance(name, indexed) > 0 {
+					ids[id] = struct{}{}
+				}
+			}
 		}
 	}
 
-	if v.VaspTRISACertification != nil {
-		if insert || v.VaspTRISACertification.Id == 0 {
-			s.sequence++
-			v.VaspTRISACertification.Id = s.sequence
+	if len(query.Country) > 0 {
+		matches := make(map[uint64]struct{})
+		for _, country := range query.Country {
+			for _, id := range countries[countryKey(country)] {
+				if _, ok := ids[id]; ids == nil || ok {
+					matches[id] = struct{}{}
+				}
+			}
 		}
+		ids = matches
+	}
 
-		if v.VaspTRISACertification.SubjectName != nil {
-			if insert || v.VaspTRISACertification.SubjectName.Id == 0 {
-				s.sequence++
-				v.VaspTRISACertification.SubjectName.Id = s.sequence
-			}
+	return ids, true
+}
+
+// History returns the audit entries of the VASP in the order they were appended, the
+// entries are stored under the audit prefix keyed by the VASP id and the entry id.
+func (s *ldbStore) History(id uint64) (entries []pb.AuditEntry, err error) {
+	iter := s.db.NewIterator(util.BytesPrefix(s.auditPrefix(id)), nil)
+	defer iter.Release()
+
+	entries = make([]pb.AuditEntry, 0)
+	for iter.Next() {
+		var entry pb.AuditEntry
+		if err = proto.Unmarshal(iter.Value(), &entry); err != nil {
+			return nil, err
 		}
+		entries = append(entries, entry)
+	}
 
-		if v.VaspTRISACertification.IssuerName != nil {
-			if insert || v.VaspTRISACertification.IssuerName.Id == 0 {
-				s.sequence++
-				v.VaspTRISACertification.IssuerName.Id = s.sequence
-			}
+	if err = iter.Error(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// CreateCertReq adds a certificate request to the queue, assigning it a new ID.
+func (s *ldbStore) CreateCertReq(r pb.CertificateRequest) (id uint64, err error) {
+	if r.Vasp == 0 {
+		return 0, ErrIncompleteRecord
+	}
+
+	if r.Created == "" {
+		r.Created = time.Now().Format(time.RFC3339)
+	}
+
+	// Critical section (optimizing for safety rather than speed)
+	s.Lock()
+	defer s.Unlock()
+
+	s.sequence++
+	r.Id = s.sequence
+
+	var data []byte
+	if data, err = proto.Marshal(&r); err != nil {
+		return 0, err
+	}
+
+	batch := new(leveldb.Batch)
+	batch.Put(s.certReqKey(r.Id), data)
+	s.putSequence(batch)
+	if err = s.db.Write(batch, nil); err != nil {
+		return 0, err
+	}
+	return r.Id, nil
+}
+
+// UpdateCertReq overwrites the certificate request with the specified ID (required).
+func (s *ldbStore) UpdateCertReq(r pb.CertificateRequest) (err error) {
+	if r.Id == 0 {
+		return ErrIncompleteRecord
+	}
+
+	key := s.certReqKey(r.Id)
+	if _, err = s.db.Get(key, nil); err != nil {
+		if err == leveldb.ErrNotFound {
+			return ErrEntityNotFound
 		}
+		return err
+	}
 
-		if v.VaspTRISACertification.PublicKeyInfo != nil {
-			if insert || v.VaspTRISACertification.PublicKeyInfo.Id == 0 {
-				s.sequence++
-				v.VaspTRISACertification.PublicKeyInfo.Id = s.sequence
-			}
+	var data []byte
+	if data, err = proto.Marshal(&r); err != nil {
+		return err
+	}
+	return s.db.Put(key, data, nil)
+}
+
+// DeleteCertReq removes a certificate request from the queue.
+func (s *ldbStore) DeleteCertReq(id uint64) error {
+	// LevelDB will not return an error if the request does not exist
+	return s.db.Delete(s.certReqKey(id), nil)
+}
+
+// ListCertReqs returns all of the certificate requests in the queue.
+func (s *ldbStore) ListCertReqs() (reqs []pb.CertificateRequest, err error) {
+	iter := s.db.NewIterator(util.BytesPrefix(preCertReqs), nil)
+	defer iter.Release()
+
+	reqs = make([]pb.CertificateRequest, 0)
+	for iter.Next() {
+		var r pb.CertificateRequest
+		if err = proto.Unmarshal(iter.Value(), &r); err != nil {
+			return nil, err
 		}
+		reqs = append(reqs, r)
+	}
+
+	if err = iter.Error(); err != nil {
+		return nil, err
+	}
+	return reqs, nil
+}
+
+// CreateEmail adds an email about a VASP to the outbox, assigning it a new ID.
+func (s *ldbStore) CreateEmail(e pb.Email) (id uint64, err error) {
+	if e.Vasp == 0 {
+		return 0, ErrIncompleteRecord
+	}
+
+	if e.Created == "" {
+		e.Created = time.Now().Format(time.RFC3339)
 	}
+
+	// Critical section (optimizing for safety rather than speed)
+	s.Lock()
+	defer s.Unlock()
+
+	s.sequence++
+	e.Id = s.sequence
+
+	var data []byte
+	if data, err = proto.Marshal(&e); err != nil {
+		return 0, err
+	}
+
+	batch := new(leveldb.Batch)
+	batch.Put(s.emailKey(e.Id), data)
+	s.putSequence(batch)
+	if err = s.db.Write(batch, nil); err != nil {
+		return 0, err
+	}
+	return e.Id, nil
+}
+
+// UpdateEmail overwrites the outbox email with the specified ID (required).
+func (s *ldbStore) UpdateEmail(e pb.Email) (err error) {
+	if e.Id == 0 {
+		return ErrIncompleteRecord
+	}
+
+	key := s.emailKey(e.Id)
+	if _, err = s.db.Get(key, nil); err != nil {
+		if err == leveldb.ErrNotFound {
+			return ErrEntityNotFound
+		}
+		return err
+	}
+
+	var data []byte
+	if data, err = proto.Marshal(&e); err != nil {
+		return err
+	}
+	return s.db.Put(key, data, nil)
+}
+
+// DeleteEmail removes an email from the outbox.
+func (s *ldbStore) DeleteEmail(id uint64) error {
+	// LevelDB will not return an error if the email does not exist
+	return s.db.Delete(s.emailKey(id), nil)
+}
+
+// ListEmails returns all of the emails in the outbox.
+func (s *ldbStore) ListEmails() (emails []pb.Email, err error) {
+	iter := s.db.NewIterator(util.BytesPrefix(preEmails), nil)
+	defer iter.Release()
+
+	emails = make([]pb.Email, 0)
+	for iter.Next() {
+		var e pb.Email
+		if err = proto.Unmarshal(iter.Value(), &e); err != nil {
+			return nil, err
+		}
+		emails = append(emails, e)
+	}
+
+	if err = iter.Error(); err != nil {
+		return nil, err
+	}
+	return emails, nil
+}
+
+// creates a []byte key from the vasp id using a prefix to act as a leveldb bucket
+func (s *ldbStore) vaspKey(id uint64) (key []byte) {
+	return makeKey(preVASPS, id)
+}
+
+// creates a []byte key from the certificate request id
+func (s *ldbStore) certReqKey(id uint64) (key []byte) {
+	return makeKey(preCertReqs, id)
+}
+
+// creates a []byte key from the outbox email id
+func (s *ldbStore) emailKey(id uint64) (key []byte) {
+	return makeKey(preEmails, id)
+}
+
+// creates the prefix of the audit entries of the VASP. Unlike the other keys, the ids
+// are big endian so that the entries are iterated in the order they were appended.
+func (s *ldbStore) auditPrefix(vasp uint64) (prefix []byte) {
+	pre := len(preAudit)
+	prefix = make([]byte, pre+8)
+	copy(prefix, preAudit)
+	binary.BigEndian.PutUint64(prefix[pre:], vasp)
+	return prefix
+}
+
+// creates a []byte key from the vasp id and the audit entry id
+func (s *ldbStore) auditKey(vasp, id uint64) (key []byte) {
+	key = append(s.auditPrefix(vasp), make([]byte, 8)...)
+	binary.BigEndian.PutUint64(key[len(key)-8:], id)
+	return key
+}
+
+// creates a []byte key from an id using a prefix to act as a leveldb bucket
+func makeKey(prefix []byte, id uint64) (key []byte) {
+	pre := len(prefix)
+	key = make([]byte, pre+binary.MaxVarintLen64)
+	copy(key, prefix)
+	binary.PutUvarint(key[pre:], id)
+	return key
+}
+
+// adds the audit entry of a change to a VASP by the actor to the batch, assigning the
+// entry an id from the primary key sequence; the lock must be held by the caller.
+func (s *ldbStore) putAudit(batch *leveldb.Batch, action pb.AuditAction, before, after *pb.VASP, a Actor) (err error) {
+	var entry *pb.AuditEntry
+	if entry, err = newAuditEntry(action, before, after, a); err != nil {
+		return err
+	}
+
+	s.sequence++
+	entry.Id = s.sequence
+
+	var data []byte
+	if data, err = proto.Marshal(entry); err != nil {
+		return err
+	}
+	batch.Put(s.auditKey(entry.Vasp, entry.Id), data)
+	return nil
+}
+
+// updates the ids of internal objects using the primary key sequence
+func (s *ldbStore) checkIDs(v *pb.VASP, insert bool) {
+	assignIDs(v, insert, func() (uint64, error) {
+		s.sequence++
+		return s.sequence, nil
+	})
 }
 
 // Helper indices for quick lookups and cheap constraints
 type uniqueIndex map[string]uint64
+type uniqueIndices map[Index]uniqueIndex
 type containerIndex map[string][]uint64
 
-// sync all indices with the underlying database
+// sync the in-memory indices and sequence with the database when it is opened. The
+// indices are rebuilt from the VASP records and compared to the index entries that are
+// stored in the database; if they disagree or cannot be parsed (e.g. because of a crash
+// or because the database uses the deprecated JSON indices) the stored index entries
+// are rewritten.
+// The sequence is advanced past the maximum ID in the database so IDs are never reused.
 func (s *ldbStore) sync() (err error) {
-	if err = s.seqsync(); err != nil {
+	// Critical section (optimizing for safety rather than speed)
+	s.Lock()
+	defer s.Unlock()
+
+	var pk uint64
+	if pk, err = s.storedSequence(); err != nil {
 		return err
 	}
 
-	if err = s.syncnames(); err != nil {
+	// Rebuild the indices and find the max ID from the records in the database
+	var scan *ldbScan
+	if scan, err = s.scan(); err != nil {
 		return err
 	}
 
-	if err = s.synccountries(); err != nil {
+	if len(scan.corrupted) > 0 {
+		return ErrCorruptedIndex
+	}
+
+	maxID := scan.maxID
+	s.unique, s.countries = scan.unique, scan.countries
+
+	// Stored index entries that cannot be parsed are rewritten like outdated entries
+	var unique uniqueIndices
+	var countries containerIndex
+	if unique, countries, err = s.storedIndices(); err != nil && err != ErrCorruptedIndex {
 		return err
 	}
 
+	// Rewrite the stored index entries if they do not match the records
+	if err == ErrCorruptedIndex || !s.unique.equal(unique) || !s.countries.equal(countries) || s.hasLegacyIndices() {
+		log.WithField("vasps", scan.records).Warn("stored indices are out of date, reindexing")
+		if err = s.writeIndices(); err != nil {
+			return err
+		}
+	}
+
+	// Local is behind database state, set and return
+	s.sequence = pk
+	if maxID > s.sequence {
+		log.WithField("pks", pk).WithField("max", maxID).Warn("primary key sequence is behind database, advancing")
+		s.sequence = maxID
+		batch := new(leveldb.Batch)
+		s.putSequence(batch)
+		if err = s.db.Write(batch, nil); err != nil {
+			log.WithError(err).Error("could not put primary key sequence value")
+			return ErrCorruptedSequence
+		}
+	}
+
 	return nil
 }
 
-// sync the autoincrement sequence with the leveldb auto sequence key
-func (s *ldbStore) seqsync() (err error) {
-	var pk uint64
+// reads the autoincrement sequence from the leveldb auto sequence key
+func (s *ldbStore) storedSequence() (pk uint64, err error) {
 	val, err := s.db.Get(keyAutoSequence, nil)
 	if err != nil {
 		// If the auto sequence key is not found, simply leave pk to 0
-		if err != leveldb.ErrNotFound {
-			return err
+		if err == leveldb.ErrNotFound {
+			return 0, nil
 		}
-	} else {
-		var n int
-		if pk, n = binary.Uvarint(val); n <= 0 {
-			log.WithField("n", n).Error("could not parse primary key sequence value")
-			return ErrCorruptedSequence
+		return 0, err
+	}
+
+	var n int
+	if pk, n = binary.Uvarint(val); n <= 0 {
+		log.WithField("n", n).Error("could not parse primary key sequence value")
+		return 0, ErrCorruptedSequence
+	}
+	return pk, nil
+}
+
+// the result of scanning the VASP records in the database to rebuild the indices
+type ldbScan struct {
+	records    int                           // the number of VASP records in the database
+	unique     uniqueIndices                 // the unique indices rebuilt from the records
+	countries  containerIndex                // the country index rebuilt from the records
+	maxID      uint64                        // the max ID of any record or subrecord
+	corrupted  []string                      // keys of records that could not be unmarshaled
+	collisions map[Index]map[string][]uint64 // unique keys shared by more than one record
+}
+
+// rebuilds the indices from the VASP records, finding the maximum ID of any record or
+// subrecord in the database (including certificate requests, emails and audit entries).
+// Records that cannot be unmarshaled are reported rather than returned as an error. If
+// more than one record has the same key in a unique index, the record with the lowest
+// ID is indexed.
+func (s *ldbStore) scan() (_ *ldbScan, err error) {
+	scan := &ldbScan{
+		unique:     newUniqueIndices(),
+		countries:  make(containerIndex),
+		collisions: make(map[Index]map[string][]uint64),
+	}
+
+	iter := s.db.NewIterator(util.BytesPrefix(preVASPS), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		scan.records++
+
+		var v pb.VASP
+		if err = proto.Unmarshal(iter.Value(), &v); err != nil {
+			key := fmt.Sprintf("%q", iter.Key())
+			if id, n := binary.Uvarint(iter.Key()[len(preVASPS):]); n > 0 {
+				key = fmt.Sprintf("%s:%d", preVASPS, id)
+			}
+
+			log.WithError(err).WithField("key", key).Error("could not unmarshal vasp record")
+			scan.corrupted = append(scan.corrupted, key)
+			continue
 		}
+
+		for _, id := range recordIDs(&v) {
+			if id > scan.maxID {
+				scan.maxID = id
+			}
+		}
+
+		if v.VaspEntity == nil {
+			continue
+		}
+
+		for _, index := range UniqueIndices {
+			key := index.Key(&v)
+			if key == "" {
+				continue
+			}
+
+			other, ok := scan.unique[index][key]
+			if ok {
+				if scan.collisions[index] == nil {
+					scan.collisions[index] = make(map[string][]uint64)
+				}
+				if len(scan.collisions[index][key]) == 0 {
+					scan.collisions[index][key] = []uint64{other}
+				}
+				scan.collisions[index][key] = append(scan.collisions[index][key], v.Id)
+			}
+
+			if !ok || v.Id < other {
+				scan.unique[index][key] = v.Id
+			}
+		}
+		scan.countries.add(v.Id, v.VaspEntity.VaspCountry)
 	}
 
-	// Critical section (optimizing for safety rather than speed)
-	s.Lock()
-	defer s.Unlock()
+	if err = iter.Error(); err != nil {
+		return nil, err
+	}
 
-	// Local is behind database state, set and return
-	if s.sequence <= pk {
-		s.sequence = pk
-		return nil
+	// Certificate requests and emails share the sequence with the VASP records
+	for _, prefix := range [][]byte{preCertReqs, preEmails} {
+		var id uint64
+		if id, err = s.maxQueueID(prefix); err != nil {
+			return nil, err
+		}
+		if id > scan.maxID {
+			scan.maxID = id
+		}
 	}
 
-	//  Update the database with the local state
-	buf := make([]byte, binary.MaxVarintLen64)
-	binary.PutUvarint(buf, s.sequence)
-	if err = s.db.Put(keyAutoSequence, buf, nil); err != nil {
-		log.WithError(err).Error("could not put primary key sequence value")
-		return ErrCorruptedSequence
+	// As do the audit entries of the VASP records
+	var auditID uint64
+	if auditID, err = s.maxAuditID(); err != nil {
+		return nil, err
+	}
+	if auditID > scan.maxID {
+		scan.maxID = auditID
 	}
 
-	return nil
+	for index, collisions := range scan.collisions {
+		for key, ids := range collisions {
+			log.WithField("index", index).WithField("key", key).WithField("ids", ids).Error("duplicate vasp unique key")
+		}
+	}
+	return scan, nil
 }
 
-// sync the names index with the leveldb names key
-func (s *ldbStore) syncnames() (err error) {
-	// Critical section (optimizing for safety rather than speed)
-	s.Lock()
-	defer s.Unlock()
+// returns the max ID of the certificate requests or emails stored under the prefix.
+func (s *ldbStore) maxQueueID(prefix []byte) (maxID uint64, err error) {
+	iter := s.db.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		if id, n := binary.Uvarint(iter.Key()[len(prefix):]); n > 0 && id > maxID {
+			maxID = id
+		}
+	}
+	return maxID, iter.Error()
+}
+
+// returns the max ID of the audit entries, which is the last 8 bytes of their keys.
+func (s *ldbStore) maxAuditID() (maxID uint64, err error) {
+	iter := s.db.NewIterator(util.BytesPrefix(preAudit), nil)
+	defer iter.Release()
 
-	if s.names == nil {
-		// fetch the names from the database
-		val, err := s.db.Get(keyNameIndex, nil)
-		if err != nil {
-			if err == leveldb.ErrNotFound {
-				s.names = make(uniqueIndex)
-				return nil
+	for iter.Next() {
+		if key := iter.Key(); len(key) == len(preAudit)+16 {
+			if id := binary.BigEndian.Uint64(key[len(key)-8:]); id > maxID {
+				maxID = id
 			}
-			return err
 		}
+	}
+	return maxID, iter.Error()
+}
+
+// reads the index entries that are stored in the database.
+func (s *ldbStore) storedIndices() (unique uniqueIndices, countries containerIndex, err error) {
+	unique = newUniqueIndices()
+	countries = make(containerIndex)
+
+	for _, index := range UniqueIndices {
+		prefix := uniqueIndexPrefix(index)
+		iter := s.db.NewIterator(util.BytesPrefix(prefix), nil)
+		for iter.Next() {
+			id, n := binary.Uvarint(iter.Value())
+			if n <= 0 {
+				iter.Release()
+				return nil, nil, ErrCorruptedIndex
+			}
+			unique[index][string(iter.Key()[len(prefix):])] = id
+		}
+		iter.Release()
+		if err = iter.Error(); err != nil {
+			return nil, nil, err
+		}
+	}
 
-		if err = json.Unmarshal(val, &s.names); err != nil {
-			log.WithError(err).Error("could not unmarshal names index")
-			return ErrCorruptedIndex
+	iter := s.db.NewIterator(util.BytesPrefix(preCountryIndex), nil)
+	for iter.Next() {
+		country, id, ok := parseCountryKey(iter.Key())
+		if !ok {
+			iter.Release()
+			return nil, nil, ErrCorruptedIndex
 		}
+		countries.add(id, country)
+	}
+	iter.Release()
+	if err = iter.Error(); err != nil {
+		return nil, nil, err
+	}
+	return unique, countries, nil
+}
 
+// returns true if the deprecated JSON index keys are still in the database.
+func (s *ldbStore) hasLegacyIndices() bool {
+	for _, key := range [][]byte{keyNameIndex, keyCountryIndex} {
+		if ok, _ := s.db.Has(key, nil); ok {
+			return true
+		}
 	}
+	return false
+}
 
-	// Put the current names back to the database
-	val, err := json.Marshal(s.names)
-	if err != nil {
-		log.WithError(err).Error("could not marshal names index")
-		return ErrCorruptedIndex
+// replaces all of the stored index entries with the in-memory indices in a single batch,
+// removing the deprecated JSON indices. Must be called while holding the lock.
+func (s *ldbStore) writeIndices() (err error) {
+	batch := new(leveldb.Batch)
+	batch.Delete(keyNameIndex)
+	batch.Delete(keyCountryIndex)
+
+	prefixes := [][]byte{preCountryIndex}
+	for _, index := range UniqueIndices {
+		prefixes = append(prefixes, uniqueIndexPrefix(index))
 	}
 
-	if err = s.db.Put(keyNameIndex, val, nil); err != nil {
-		log.WithError(err).Error("could not put names index")
+	for _, prefix := range prefixes {
+		iter := s.db.NewIterator(util.BytesPrefix(prefix), nil)
+		for iter.Next() {
+			batch.Delete(append([]byte(nil), iter.Key()...))
+		}
+		iter.Release()
+		if err = iter.Error(); err != nil {
+			return err
+		}
+	}
+
+	for index, keys := range s.unique {
+		for key, id := range keys {
+			batch.Put(uniqueIndexKey(index, key), encodeID(id))
+		}
+	}
+
+	for country, ids := range s.countries {
+		for _, id := range ids {
+			batch.Put(countryIndexKey(country, id), nil)
+		}
+	}
+
+	if err = s.db.Write(batch, nil); err != nil {
+		log.WithError(err).Error("could not write indices")
 		return ErrCorruptedIndex
 	}
 	return nil
 }
 
-// sync the countries index with the leveldb countries key
-func (s *ldbStore) synccountries() (err error) {
-	// Critical section (optimizing for safety rather than speed)
-	s.Lock()
-	defer s.Unlock()
+// adds the index entries of the VASP to the batch
+func (s *ldbStore) putIndices(batch *leveldb.Batch, v pb.VASP) {
+	if v.VaspEntity == nil {
+		return
+	}
+
+	for _, index := range UniqueIndices {
+		if key := index.Key(&v); key != "" {
+			batch.Put(uniqueIndexKey(index, key), encodeID(v.Id))
+		}
+	}
+
+	if country := countryKey(v.VaspEntity.VaspCountry); country != "" {
+		batch.Put(countryIndexKey(country, v.Id), nil)
+	}
+}
+
+// removes the index entries of the VASP in the batch
+func (s *ldbStore) deleteIndices(batch *leveldb.Batch, v pb.VASP) {
+	if v.VaspEntity == nil {
+		return
+	}
+
+	// Only remove unique entries that refer to this VASP (they may not if the keys of
+	// VASPs created before the index or its normalization was introduced collide)
+	for _, index := range UniqueIndices {
+		if key := index.Key(&v); key != "" && s.unique[index][key] == v.Id {
+			batch.Delete(uniqueIndexKey(index, key))
+		}
+	}
+
+	if country := countryKey(v.VaspEntity.VaspCountry); country != "" {
+		batch.Delete(countryIndexKey(country, v.Id))
+	}
+}
+
+// replaces the index entries of the original VASP with the entries of the updated VASP
+// in the batch, only changing the entries of the indices whose keys were updated
+func (s *ldbStore) updateIndices(batch *leveldb.Batch, o, v pb.VASP) {
+	for _, index := range changedIndices(&o, &v) {
+		if key := index.Key(&o); key != "" && s.unique[index][key] == o.Id {
+			batch.Delete(uniqueIndexKey(index, key))
+		}
+
+		if key := index.Key(&v); key != "" {
+			batch.Put(uniqueIndexKey(index, key), encodeID(v.Id))
+		}
+	}
+
+	before, after := countryKey(o.VaspEntity.GetVaspCountry()), countryKey(v.VaspEntity.GetVaspCountry())
+	if before != after {
+		if before != "" {
+			batch.Delete(countryIndexKey(before, o.Id))
+		}
+
+		if after != "" {
+			batch.Put(countryIndexKey(after, v.Id), nil)
+		}
+	}
+}
+
+// adds the current value of the autoincrement sequence to the batch
+func (s *ldbStore) putSequence(batch *leveldb.Batch) {
+	batch.Put(keyAutoSequence, encodeID(s.sequence))
+}
+
+// returns all of the IDs assigned to the record and its subrecords
+func recordIDs(v *pb.VASP) []uint64 {
+	ids := []uint64{v.Id}
+	if v.VaspEntity != nil {
+		ids = append(ids, v.VaspEntity.Id)
+	}
+
+	if c := v.VaspTRISACertification; c != nil {
+		ids = append(ids, c.Id)
+		if c.SubjectName != nil {
+			ids = append(ids, c.SubjectName.Id)
+		}
+		if c.IssuerName != nil {
+			ids = append(ids, c.IssuerName.Id)
+		}
+		if c.PublicKeyInfo != nil {
+			ids = append(ids, c.PublicKeyInfo.Id)
+		}
+	}
+	return ids
+}
+
+// returns the key of the country in the country index (case insensitive)
+func countryKey(country string) string {
+	return strings.ToLower(strings.TrimSpace(country))
+}
+
+// returns the prefix of the leveldb keys of a unique index, e.g. "index::names::"
+func uniqueIndexPrefix(index Index) []byte {
+	return []byte("index::" + index.String() + "::")
+}
+
+// creates the leveldb key of a unique index entry
+func uniqueIndexKey(index Index, key string) []byte {
+	return append(uniqueIndexPrefix(index), key...)
+}
+
+// creates the leveldb key of a country index entry, the id is big endian encoded so
+// that the entries of a country are sorted by id
+func countryIndexKey(country string, id uint64) []byte {
+	key := make([]byte, 0, len(preCountryIndex)+len(country)+9)
+	key = append(key, preCountryIndex...)
+	key = append(key, country...)
+	key = append(key, 0)
+
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, id)
+	return append(key, buf...)
+}
+
+// parses the country and id from a country index key
+func parseCountryKey(key []byte) (country string, id uint64, ok bool) {
+	key = key[len(preCountryIndex):]
+	if len(key) < 9 || key[len(key)-9] != 0 {
+		return "", 0, false
+	}
+	return string(key[:len(key)-9]), binary.BigEndian.Uint64(key[len(key)-8:]), true
+}
+
+// encodes an id as a uvarint value
+func encodeID(id uint64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, id)
+	return buf[:n]
+}
 
-	if s.countries == nil {
-		// fetch the countries from the database
-		val, err := s.db.Get(keyCountryIndex, nil)
-		if err != nil {
-			if err == leveldb.ErrNotFound {
-				s.countries = make(containerIndex)
-				return nil
+// returns true if both indices contain the same keys and ids
+func (u uniqueIndex) equal(o uniqueIndex) bool {
+	if len(u) != len(o) {
+		return false
+	}
+
+	for name, id := range u {
+		if oid, ok := o[name]; !ok || oid != id {
+			return false
+		}
+	}
+	return true
+}
+
+// creates an empty index for each of the unique indices
+func newUniqueIndices() uniqueIndices {
+	u := make(uniqueIndices, len(UniqueIndices))
+	for _, index := range UniqueIndices {
+		u[index] = make(uniqueIndex)
+	}
+	return u
+}
+
+// returns true if any of the unique keys of the VASP is indexed for another VASP
+func (u uniqueIndices) conflicts(v *pb.VASP) bool {
+	for _, index := range UniqueIndices {
+		if key := index.Key(v); key != "" {
+			if id, ok := u[index][key]; ok && id != v.Id {
+				return true
 			}
-			return err
 		}
+	}
+	return false
+}
 
-		if err = json.Unmarshal(val, &s.countries); err != nil {
-			log.WithError(err).Error("could not unmarshall country index")
-			return ErrCorruptedIndex
+// adds the unique keys of the VASP to the indices
+func (u uniqueIndices) add(v *pb.VASP) {
+	for _, index := range UniqueIndices {
+		if key := index.Key(v); key != "" {
+			u[index][key] = v.Id
 		}
 	}
+}
 
-	// Put the current countries back to the database
-	val, err := json.Marshal(s.countries)
-	if err != nil {
-		log.WithError(err).Error("could not marshal country index")
-		return ErrCorruptedIndex
+// removes the unique keys of the VASP from the indices if they refer to the VASP
+func (u uniqueIndices) rm(v *pb.VASP) {
+	for _, index := range UniqueIndices {
+		if key := index.Key(v); key != "" && u[index][key] == v.Id {
+			delete(u[index], key)
+		}
 	}
+}
 
-	if err = s.db.Put(keyCountryIndex, val, nil); err != nil {
-		log.WithError(err).Error("could not put country index")
-		return ErrCorruptedIndex
+// moves the keys of the VASP in the indices whose keys were changed by the update
+func (u uniqueIndices) update(o, v *pb.VASP) {
+	for _, index := range changedIndices(o, v) {
+		if key := index.Key(o); key != "" && u[index][key] == o.Id {
+			delete(u[index], key)
+		}
+
+		if key := index.Key(v); key != "" {
+			u[index][key] = v.Id
+		}
 	}
+}
 
-	return nil
+// returns true if all of the indices contain the same keys and ids
+func (u uniqueIndices) equal(o uniqueIndices) bool {
+	for _, index := range UniqueIndices {
+		if !u[index].equal(o[index]) {
+			return false
+		}
+	}
+	return true
+}
+
+// returns true if both indices contain the same countries and ids
+func (c containerIndex) equal(o containerIndex) bool {
+	if len(c) != len(o) {
+		return false
+	}
+
+	for country, ids := range c {
+		oids, ok := o[country]
+		if !ok || len(oids) != len(ids) {
+			return false
+		}
+
+		for i := range ids {
+			if ids[i] != oids[i] {
+				return false
+			}
+		}
+	}
+	return true
 }
 
 func (c containerIndex) add(id uint64, country string) {
@@ -450,7 +1237,7 @@ func (c containerIndex) add(id uint64, country string) {
 	}
 
 	// make country search case insensitive
-	country = strings.ToLower(country)
+	country = countryKey(country)
 
 	arr, ok := c[country]
 	if !ok {
@@ -475,7 +1262,7 @@ func (c containerIndex) add(id uint64, country string) {
 
 func (c containerIndex) rm(id uint64, country string) {
 	// make country search case insensitive
-	country = strings.ToLower(country)
+	country = countryKey(country)
 
 	arr, ok := c[country]
 	if !ok {
@@ -489,26 +1276,16 @@ func (c containerIndex) rm(id uint64, country string) {
 		arr = arr[:len(arr)-1]
 		c[country] = arr
 	}
-}
 
-// A helper function to fetch a list of values from a query
-func parseQuery(key string, query map[string]interface{}) ([]string, bool) {
-	val, ok := query[key]
-	if !ok {
-		return nil, false
-	}
-
-	if vals, ok := val.([]string); ok {
-		for i := range vals {
-			vals[i] = strings.ToLower(strings.TrimSpace(vals[i]))
-		}
-		return vals, true
+	if len(arr) == 0 {
+		delete(c, country)
 	}
+}
 
-	if vals, ok := val.(string); ok {
-		vals = strings.ToLower(strings.TrimSpace(vals))
-		return []string{vals}, true
+// moves the id to the updated country if the country was changed
+func (c containerIndex) update(id uint64, before, after string) {
+	if countryKey(before) != countryKey(after) {
+		c.rm(id, before)
+		c.add(id, after)
 	}
-
-	return nil, false
 }
@@ -0,0 +1,221 @@
+package store
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// IntegrityReport describes the consistency of the records, indices and primary key
+// sequence of a LevelDB directory store. Orphaned and missing index entries and unique
+// key collisions are reported by the name of the index (e.g. "names" or "countries").
+type IntegrityReport struct {
+	Records       int                            `json:"records"`
+	Corrupted     []string                       `json:"corrupted,omitempty"`
+	Orphaned      map[string][]string            `json:"orphaned,omitempty"`
+	Missing       map[string][]string            `json:"missing,omitempty"`
+	Collisions    map[string]map[string][]uint64 `json:"collisions,omitempty"`
+	MaxID         uint64                         `json:"max_id"`
+	Sequence      uint64                         `json:"sequence"`
+	LegacyIndices bool                           `json:"legacy_indices,omitempty"`
+	Reindexed     bool                           `json:"reindexed"`
+}
+
+// OK returns true if no integrity problems were found (or if they have been repaired).
+func (r *IntegrityReport) OK() bool {
+	if len(r.Corrupted) > 0 || len(r.Collisions) > 0 {
+		return false
+	}
+
+	if r.Reindexed {
+		return true
+	}
+
+	return len(r.Orphaned) == 0 && len(r.Missing) == 0 &&
+		r.Sequence >= r.MaxID && !r.LegacyIndices
+}
+
+// adds orphaned and missing index entries to the report
+func (r *IntegrityReport) add(index string, orphaned, missing []string) {
+	if len(orphaned) > 0 {
+		sort.Strings(orphaned)
+		r.Orphaned[index] = orphaned
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		r.Missing[index] = missing
+	}
+}
+
+// CheckLevelDB opens the existing LevelDB directory store at the specified path without
+// synchronizing its indices and checks that every VASP record can be unmarshaled, that
+// the stored index entries match the records, that no two records share a unique key
+// (name, LEI, domain, certificate common name or serial number), and that the primary
+// key sequence is ahead of the max ID in the database. If reindex is true, the indices
+// are rewritten from the records and the sequence is advanced. Corrupted records and
+// unique key collisions are reported but must be repaired manually. Only LevelDB stores
+// can be checked, and no database is created if the path does not exist.
+func CheckLevelDB(uri string, reindex bool) (report *IntegrityReport, err error) {
+	dsn, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse leveldb uri: %s", err)
+	}
+
+	if dsn.Scheme != "" && dsn.Scheme != "leveldb" {
+		return nil, fmt.Errorf("cannot check %q database, only leveldb stores can be checked", dsn.Scheme)
+	}
+
+	// The file storage creates the directory before the database is opened
+	if _, err = os.Stat(dsn.Path); err != nil {
+		return nil, fmt.Errorf("could not open leveldb database: %s", err)
+	}
+
+	db, err := leveldb.OpenFile(dsn.Path, &opt.Options{ErrorIfMissing: true})
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ldbStore{db: db}
+	defer s.Close()
+	return s.check(reindex)
+}
+
+// checks the integrity of the store, optionally rewriting the indices and sequence.
+func (s *ldbStore) check(reindex bool) (report *IntegrityReport, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	var scan *ldbScan
+	if scan, err = s.scan(); err != nil {
+		return nil, err
+	}
+
+	report = &IntegrityReport{
+		Records:       scan.records,
+		Corrupted:     scan.corrupted,
+		Orphaned:      make(map[string][]string),
+		Missing:       make(map[string][]string),
+		Collisions:    make(map[string]map[string][]uint64),
+		MaxID:         scan.maxID,
+		LegacyIndices: s.hasLegacyIndices(),
+	}
+
+	for index, collisions := range scan.collisions {
+		report.Collisions[index.String()] = collisions
+	}
+
+	if report.Sequence, err = s.storedSequence(); err != nil {
+		// A corrupted sequence is reported as zero so that it is rewritten
+		report.Sequence = 0
+	}
+
+	for _, index := range UniqueIndices {
+		if err = s.checkUnique(index, scan, report); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = s.checkCountries(scan, report); err != nil {
+		return nil, err
+	}
+
+	if !reindex {
+		return report, nil
+	}
+
+	s.unique, s.countries = scan.unique, scan.countries
+	if err = s.writeIndices(); err != nil {
+		return nil, err
+	}
+
+	if report.Sequence < report.MaxID {
+		s.sequence = report.MaxID
+		batch := new(leveldb.Batch)
+		s.putSequence(batch)
+		if err = s.db.Write(batch, nil); err != nil {
+			return nil, err
+		}
+		report.Sequence = s.sequence
+	}
+
+	report.Reindexed = true
+	return report, nil
+}
+
+// compares the stored entries of a unique index to the keys of the records.
+func (s *ldbStore) checkUnique(index Index, scan *ldbScan, report *IntegrityReport) error {
+	var orphaned, missing []string
+	stored := make(map[string]struct{})
+	prefix := uniqueIndexPrefix(index)
+	iter := s.db.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		key := string(iter.Key()[len(prefix):])
+		stored[key] = struct{}{}
+
+		id, n := binary.Uvarint(iter.Value())
+		if expected, ok := scan.unique[index][key]; n <= 0 || !ok || expected != id {
+			orphaned = append(orphaned, key)
+		}
+	}
+
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	for key := range scan.unique[index] {
+		if _, ok := stored[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+
+	report.add(index.String(), orphaned, missing)
+	return nil
+}
+
+// compares the stored country index entries to the countries of the records.
+func (s *ldbStore) checkCountries(scan *ldbScan, report *IntegrityReport) error {
+	var orphaned, missing []string
+	stored := make(containerIndex)
+	iter := s.db.NewIterator(util.BytesPrefix(preCountryIndex), nil)
+	defer iter.Release()
+
+	for iter.Next() {
+		country, id, ok := parseCountryKey(iter.Key())
+		if !ok || !scan.countries.contains(country, id) {
+			orphaned = append(orphaned, fmt.Sprintf("%q", iter.Key()[len(preCountryIndex):]))
+			continue
+		}
+		stored.add(id, country)
+	}
+
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	for country, ids := range scan.countries {
+		for _, id := range ids {
+			if !stored.contains(country, id) {
+				missing = append(missing, fmt.Sprintf("%s:%d", country, id))
+			}
+		}
+	}
+
+	report.add("countries", orphaned, missing)
+	return nil
+}
+
+// returns true if the id is indexed for the country
+func (c containerIndex) contains(country string, id uint64) bool {
+	arr := c[countryKey(country)]
+	i := sort.Search(len(arr), func(i int) bool { return arr[i] >= id })
+	return i < len(arr) && arr[i] == id
+}
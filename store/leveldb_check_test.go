@@ -0,0 +1,109 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+func TestCheckLevelDB(t *testing.T) {
+	path, ids, cleanup := levelDBFixture(t)
+	defer cleanup()
+	uri := "leveldb:///" + path
+
+	report, err := CheckLevelDB(uri, false)
+	require.NoError(t, err)
+	require.True(t, report.OK(), "%+v", report)
+	require.Equal(t, 3, report.Records)
+	require.False(t, report.Reindexed)
+
+	// Introduce a dangling index entry for a VASP that does not exist, and remove the
+	// index entries of an existing VASP
+	modifyLevelDB(t, path, func(db *leveldb.DB, batch *leveldb.Batch) {
+		batch.Put(uniqueIndexKey(NameIndex, "ghost exchange"), encodeID(9999))
+		batch.Put(countryIndexKey("fr", 9999), nil)
+		batch.Delete(uniqueIndexKey(DomainIndex, "beta.com"))
+		batch.Delete(countryIndexKey("gb", ids[1]))
+	})
+
+	report, err = CheckLevelDB(uri, false)
+	require.NoError(t, err)
+	require.False(t, report.OK())
+	require.Equal(t, []string{"ghost exchange"}, report.Orphaned["names"])
+	require.Len(t, report.Orphaned["countries"], 1)
+	require.Equal(t, []string{"beta.com"}, report.Missing["domains"])
+	require.Equal(t, []string{fmt.Sprintf("gb:%d", ids[1])}, report.Missing["countries"])
+	require.Empty(t, report.Corrupted)
+	require.Empty(t, report.Collisions)
+
+	// Checking does not modify the database
+	report, err = CheckLevelDB(uri, false)
+	require.NoError(t, err)
+	require.False(t, report.OK())
+
+	report, err = CheckLevelDB(uri, true)
+	require.NoError(t, err)
+	require.True(t, report.OK(), "%+v", report)
+	require.True(t, report.Reindexed)
+
+	report, err = CheckLevelDB(uri, false)
+	require.NoError(t, err)
+	require.True(t, report.OK(), "%+v", report)
+	require.Empty(t, report.Orphaned)
+	require.Empty(t, report.Missing)
+
+	// The repaired indices are used by the store
+	db, err := OpenLevelDB(uri)
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Lookup(NameIndex, "Ghost Exchange")
+	require.Equal(t, ErrEntityNotFound, err)
+
+	vasp, err := db.Lookup(DomainIndex, "beta.com")
+	require.NoError(t, err)
+	require.Equal(t, ids[1], vasp.Id)
+}
+
+func TestCheckLevelDBSequence(t *testing.T) {
+	path, ids, cleanup := levelDBFixture(t)
+	defer cleanup()
+	uri := "leveldb:///" + path
+
+	// A sequence that is behind the records is reported and advanced by reindexing
+	modifyLevelDB(t, path, func(db *leveldb.DB, batch *leveldb.Batch) {
+		batch.Put(keyAutoSequence, encodeID(1))
+	})
+
+	report, err := CheckLevelDB(uri, false)
+	require.NoError(t, err)
+	require.False(t, report.OK())
+	require.Equal(t, uint64(1), report.Sequence)
+	require.True(t, report.MaxID >= ids[2])
+
+	report, err = CheckLevelDB(uri, true)
+	require.NoError(t, err)
+	require.True(t, report.OK())
+	require.Equal(t, report.MaxID, report.Sequence)
+}
+
+func TestCheckLevelDBInvalid(t *testing.T) {
+	path, _, cleanup := levelDBFixture(t)
+	defer cleanup()
+
+	// A mistyped path is not created as an empty database
+	missing := filepath.Join(filepath.Dir(path), "missing")
+	_, err := CheckLevelDB("leveldb:///"+missing, false)
+	require.Error(t, err)
+
+	_, err = os.Stat(missing)
+	require.True(t, os.IsNotExist(err))
+
+	// Only leveldb stores can be checked
+	_, err = CheckLevelDB("postgres://localhost/trisads", false)
+	require.Error(t, err)
+}
@@ -0,0 +1,135 @@
+package store
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// creates a leveldb store in a temporary directory with VASPs that have a name, country
+// and certificate serial number to index, returning the path of the database.
+func levelDBFixture(t *testing.T) (path string, ids []uint64, cleanup func()) {
+	dir, err := ioutil.TempDir("", "trisads-leveldb")
+	require.NoError(t, err)
+	path = filepath.Join(dir, "db")
+
+	db, err := OpenLevelDB("leveldb:///" + path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	fixtures := []pb.VASP{
+		{
+			VaspEntity:             &pb.Entity{VaspFullLegalName: "Alpha Exchange", VaspCountry: "US", VaspURL: "https://alpha.io"},
+			VaspTRISACertification: &pb.TRISACertification{SerialNumber: []byte{0x1a, 0x2b}, SubjectName: &pb.Name{CommonName: "alpha.io"}},
+		},
+		{
+			VaspEntity:             &pb.Entity{VaspFullLegalName: "Beta Custody", VaspCountry: "GB", VaspURL: "https://beta.com"},
+			VaspTRISACertification: &pb.TRISACertification{SerialNumber: []byte{0x3c, 0x4d}, SubjectName: &pb.Name{CommonName: "beta.com"}},
+		},
+		{
+			VaspEntity: &pb.Entity{VaspFullLegalName: "Gamma Wallet", VaspCountry: "US"},
+		},
+	}
+
+	for _, vasp := range fixtures {
+		id, err := db.Create(vasp, testActor)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+	return path, ids, func() { os.RemoveAll(dir) }
+}
+
+// applies the modifications to the raw leveldb database at the path
+func modifyLevelDB(t *testing.T, path string, modify func(db *leveldb.DB, batch *leveldb.Batch)) {
+	db, err := leveldb.OpenFile(path, nil)
+	require.NoError(t, err)
+	defer db.Close()
+
+	batch := new(leveldb.Batch)
+	modify(db, batch)
+	require.NoError(t, db.Write(batch, nil))
+}
+
+// deletes all of the keys with the prefix
+func deletePrefix(db *leveldb.DB, batch *leveldb.Batch, prefix []byte) {
+	iter := db.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+	for iter.Next() {
+		batch.Delete(append([]byte(nil), iter.Key()...))
+	}
+}
+
+func TestLevelDBIndexRecovery(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(db *leveldb.DB, batch *leveldb.Batch, ids []uint64)
+	}{
+		{
+			name: "deleted",
+			modify: func(db *leveldb.DB, batch *leveldb.Batch, ids []uint64) {
+				deletePrefix(db, batch, []byte("index::"))
+			},
+		},
+		{
+			name: "corrupted",
+			modify: func(db *leveldb.DB, batch *leveldb.Batch, ids []uint64) {
+				// Index entries that point to the wrong VASP or cannot be parsed
+				batch.Put(uniqueIndexKey(NameIndex, "alpha exchange"), encodeID(ids[2]))
+				batch.Put(uniqueIndexKey(SerialIndex, "3c4d"), []byte{0x80})
+				batch.Put(append(append([]byte(nil), preCountryIndex...), "us"...), nil)
+				batch.Delete(countryIndexKey("us", ids[0]))
+			},
+		},
+		{
+			name: "legacy",
+			modify: func(db *leveldb.DB, batch *leveldb.Batch, ids []uint64) {
+				deletePrefix(db, batch, []byte("index::"))
+				batch.Put(keyNameIndex, []byte(`{"alpha exchange":2}`))
+				batch.Put(keyCountryIndex, []byte(`{"us":[2]}`))
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path, ids, cleanup := levelDBFixture(t)
+			defer cleanup()
+
+			modifyLevelDB(t, path, func(db *leveldb.DB, batch *leveldb.Batch) { tc.modify(db, batch, ids) })
+
+			db, err := OpenLevelDB("leveldb:///" + path)
+			require.NoError(t, err)
+
+			vasp, err := db.Lookup(NameIndex, "Alpha Exchange")
+			require.NoError(t, err)
+			require.Equal(t, ids[0], vasp.Id)
+
+			vasp, err = db.Lookup(SerialIndex, "3C:4D")
+			require.NoError(t, err)
+			require.Equal(t, ids[1], vasp.Id)
+
+			results, err := db.Search(&pb.Query{Country: []string{"US"}})
+			require.NoError(t, err)
+			require.Len(t, results, 2)
+			require.Equal(t, ids[0], results[0].VASP.Id)
+			require.Equal(t, ids[2], results[1].VASP.Id)
+
+			// New VASPs do not reuse the IDs of the existing VASPs
+			id, err := db.Create(pb.VASP{VaspEntity: &pb.Entity{VaspFullLegalName: "Delta Exchange", VaspCountry: "US"}}, testActor)
+			require.NoError(t, err)
+			require.True(t, id > ids[2])
+			require.NoError(t, db.Close())
+
+			// The stored index entries have been rewritten
+			report, err := CheckLevelDB("leveldb:///"+path, false)
+			require.NoError(t, err)
+			require.True(t, report.OK(), "%+v", report)
+		})
+	}
+}
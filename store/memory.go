@@ -0,0 +1,436 @@
+package store
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/gogo/protobuf/proto"
+)
+
+// OpenMemory returns an empty directory Store that is held in memory, e.g. for tests and
+// ephemeral deployments such as demos. All records are lost when the store is closed.
+func OpenMemory(uri string) (Store, error) {
+	return &memStore{
+		vasps:     make(map[uint64]*pb.VASP),
+		unique:    newUniqueIndices(),
+		countries: make(containerIndex),
+		certreqs:  make(map[uint64]*pb.CertificateRequest),
+		emails:    make(map[uint64]*pb.Email),
+		audit:     make(map[uint64][]*pb.AuditEntry),
+	}, nil
+}
+
+// Implements Store with in-memory maps and the same semantics as the LevelDB store.
+// Records are cloned when they are stored and when they are returned so that callers
+// cannot modify the stored records.
+type memStore struct {
+	sync.RWMutex
+	sequence  uint64 // autoincrement sequence for ID values
+	vasps     map[uint64]*pb.VASP
+	unique    uniqueIndices
+	countries containerIndex
+	certreqs  map[uint64]*pb.CertificateRequest
+	emails    map[uint64]*pb.Email
+	audit     map[uint64][]*pb.AuditEntry // audit entries by VASP id
+}
+
+// Close the store, discarding all of its records.
+func (s *memStore) Close() error {
+	s.Lock()
+	defer s.Unlock()
+	s.vasps, s.certreqs, s.emails, s.audit = nil, nil, nil, nil
+	s.unique, s.countries = newUniqueIndices(), make(containerIndex)
+	return nil
+}
+
+// Create a VASP into the directory. This method requires the VASP to have a unique
+// name (as well as a unique LEI, domain, certificate common name and certificate serial
+// number if they are set) and ignores any ID fields that are set on the VASP, instead
+// assigning new IDs.
+func (s *memStore) Create(v pb.VASP, a Actor) (id uint64, err error) {
+	return s.create(v, nil, a)
+}
+
+// CreateWithEmail creates a VASP and queues the email in the outbox.
+func (s *memStore) CreateWithEmail(v pb.VASP, e pb.Email, a Actor) (id uint64, err error) {
+	return s.create(v, &e, a)
+}
+
+// creates the VASP record, storing the optional email about the VASP with it.
+func (s *memStore) create(v pb.VASP, e *pb.Email, a Actor) (id uint64, err error) {
+	if v.VaspEntity == nil {
+		return 0, ErrIncompleteRecord
+	}
+
+	// The name is required for the uniqueness constraint
+	if NameIndex.Key(&v) == "" {
+		return 0, ErrIncompleteRecord
+	}
+
+	// New records must start at the beginning of the verification workflow
+	if !v.VerificationStatus.Initial() {
+		return 0, ErrInvalidTransition
+	}
+
+	// Update management timestamps
+	v.LastUpdated = time.Now().Format(time.RFC3339)
+	if v.FirstListed == "" {
+		v.FirstListed = v.LastUpdated
+	}
+	v.Version = 1
+
+	s.Lock()
+	defer s.Unlock()
+
+	// Check the uniqueness constraints
+	if s.unique.conflicts(&v) {
+		return 0, ErrDuplicateEntity
+	}
+
+	// Insert sets the IDs of the entity even if they are already set
+	record := proto.Clone(&v).(*pb.VASP)
+	assignIDs(record, true, s.next)
+	if err = s.appendAudit(pb.AuditAction_CREATED, nil, record, a); err != nil {
+		return 0, err
+	}
+	s.vasps[record.Id] = record
+
+	if e != nil {
+		e = proto.Clone(e).(*pb.Email)
+		e.Id, _ = s.next()
+		e.Vasp = record.Id
+		if e.Created == "" {
+			e.Created = record.LastUpdated
+		}
+		s.emails[e.Id] = e
+	}
+
+	s.unique.add(record)
+	s.countries.add(record.Id, record.VaspEntity.VaspCountry)
+	return record.Id, nil
+}
+
+// Retrieve a VASP record by id; returns an error if the record does not exist.
+func (s *memStore) Retrieve(id uint64) (v pb.VASP, err error) {
+	s.RLock()
+	defer s.RUnlock()
+	return s.retrieve(id)
+}
+
+// retrieves a copy of the VASP record, the lock must be held by the caller
+func (s *memStore) retrieve(id uint64) (v pb.VASP, err error) {
+	record, ok := s.vasps[id]
+	if !ok {
+		return v, ErrEntityNotFound
+	}
+	return *proto.Clone(record).(*pb.VASP), nil
+}
+
+// Lookup a VASP record by its key in one of the unique indices, e.g. by LEI number. The
+// key is normalized in the same manner as the index; returns ErrEntityNotFound if no
+// VASP has the key.
+func (s *memStore) Lookup(index Index, key string) (v pb.VASP, err error) {
+	if key = index.Normalize(key); key == "" {
+		return v, ErrEntityNotFound
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	id, ok := s.unique[index][key]
+	if !ok {
+		return v, ErrEntityNotFound
+	}
+	return s.retrieve(id)
+}
+
+// Update the VASP entry by the VASP ID (required). This method simply overwrites the
+// entire VASP record and does not update individual fields. The version of the update
+// must match the stored record, otherwise ErrConflict is returned.
+func (s *memStore) Update(v pb.VASP, a Actor) (err error) {
+	if v.Id == 0 || v.VaspEntity == nil {
+		return ErrIncompleteRecord
+	}
+
+	if NameIndex.Key(&v) == "" {
+		return ErrIncompleteRecord
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	o, ok := s.vasps[v.Id]
+	if !ok {
+		return ErrEntityNotFound
+	}
+
+	// Updates must be made to the current version of the record (compare-and-swap)
+	if v.Version != o.Version {
+		return ErrConflict
+	}
+
+	// Ensure the verification state machine is respected
+	if !o.VerificationStatus.CanTransition(v.VerificationStatus) {
+		return ErrInvalidTransition
+	}
+
+	// Check the uniqueness constraints if the VASP is being renamed, etc.
+	if s.unique.conflicts(&v) {
+		return ErrDuplicateEntity
+	}
+
+	// Check to ensure all subrecords have unique identifiers if not already set
+	record := proto.Clone(&v).(*pb.VASP)
+	assignIDs(record, false, s.next)
+	record.LastUpdated = time.Now().Format(time.RFC3339)
+	record.Version = o.Version + 1
+	if err = s.appendAudit(pb.AuditAction_UPDATED, o, record, a); err != nil {
+		return err
+	}
+	s.vasps[record.Id] = record
+
+	s.unique.update(o, record)
+	s.countries.update(record.Id, o.VaspEntity.VaspCountry, record.VaspEntity.VaspCountry)
+	return nil
+}
+
+// Destroy a record, removing it completely from the store and indices. The audit
+// history of the record is retained.
+func (s *memStore) Destroy(id uint64, a Actor) (err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	record, ok := s.vasps[id]
+	if !ok {
+		return nil
+	}
+
+	if err = s.appendAudit(pb.AuditAction_DELETED, record, nil, a); err != nil {
+		return err
+	}
+
+	delete(s.vasps, id)
+	s.unique.rm(record)
+	s.countries.rm(id, record.VaspEntity.VaspCountry)
+	return nil
+}
+
+// List all of the VASP records in the store ordered by ID.
+func (s *memStore) List() (vasps []pb.VASP, err error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	vasps = make([]pb.VASP, 0, len(s.vasps))
+	for id := range s.vasps {
+		vasp, _ := s.retrieve(id)
+		vasps = append(vasps, vasp)
+	}
+
+	sort.Slice(vasps, func(i, j int) bool { return vasps[i].Id < vasps[j].Id })
+	return vasps, nil
+}
+
+// ListAfter returns up to limit VASP records with an ID greater than the specified ID,
+// ordered by ID; all of the records after the ID are returned if the limit is zero.
+func (s *memStore) ListAfter(after uint64, limit int) (vasps []pb.VASP, err error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	ids := make([]uint64, 0)
+	for id := range s.vasps {
+		if id > after {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	if limit > 0 && len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	vasps = make([]pb.VASP, 0, len(ids))
+	for _, id := range ids {
+		vasp, _ := s.retrieve(id)
+		vasps = append(vasps, vasp)
+	}
+	return vasps, nil
+}
+
+// Search returns the VASPs that match the query, evaluating the query against each
+// VASP record with Match and ordering the results by descending relevance. If the query
+// requires a name or country match, the indices are used to find the VASPs that must
+// be evaluated, otherwise all VASP records are evaluated.
+func (s *memStore) Search(query *pb.Query) (results []SearchResult, err error) {
+	if err = CheckQuery(query); err != nil {
+		return nil, err
+	}
+
+	s.RLock()
+	defer s.RUnlock()
+
+	candidates, ok := indexCandidates(query, s.unique, s.countries)
+	if !ok {
+		candidates = make(map[uint64]struct{}, len(s.vasps))
+		for id := range s.vasps {
+			candidates[id] = struct{}{}
+		}
+	}
+
+	results = make([]SearchResult, 0)
+	for id := range candidates {
+		if record, ok := s.vasps[id]; ok {
+			if ok, score := Match(query, record); ok {
+				vasp, _ := s.retrieve(id)
+				results = append(results, SearchResult{VASP: vasp, Score: score})
+			}
+		}
+	}
+
+	SortResults(results)
+	return results, nil
+}
+
+// History returns the audit entries of the VASP in the order they were appended.
+func (s *memStore) History(id uint64) (entries []pb.AuditEntry, err error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	entries = make([]pb.AuditEntry, 0, len(s.audit[id]))
+	for _, entry := range s.audit[id] {
+		entries = append(entries, *proto.Clone(entry).(*pb.AuditEntry))
+	}
+	return entries, nil
+}
+
+// CreateCertReq adds a certificate request to the queue, assigning it a new ID.
+func (s *memStore) CreateCertReq(r pb.CertificateRequest) (id uint64, err error) {
+	if r.Vasp == 0 {
+		return 0, ErrIncompleteRecord
+	}
+
+	if r.Created == "" {
+		r.Created = time.Now().Format(time.RFC3339)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	r.Id, _ = s.next()
+	s.certreqs[r.Id] = proto.Clone(&r).(*pb.CertificateRequest)
+	return r.Id, nil
+}
+
+// UpdateCertReq overwrites the certificate request with the specified ID (required).
+func (s *memStore) UpdateCertReq(r pb.CertificateRequest) (err error) {
+	if r.Id == 0 {
+		return ErrIncompleteRecord
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.certreqs[r.Id]; !ok {
+		return ErrEntityNotFound
+	}
+	s.certreqs[r.Id] = proto.Clone(&r).(*pb.CertificateRequest)
+	return nil
+}
+
+// DeleteCertReq removes a certificate request from the queue.
+func (s *memStore) DeleteCertReq(id uint64) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.certreqs, id)
+	return nil
+}
+
+// ListCertReqs returns all of the certificate requests in the queue ordered by ID.
+func (s *memStore) ListCertReqs() (reqs []pb.CertificateRequest, err error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	reqs = make([]pb.CertificateRequest, 0, len(s.certreqs))
+	for _, r := range s.certreqs {
+		reqs = append(reqs, *proto.Clone(r).(*pb.CertificateRequest))
+	}
+
+	sort.Slice(reqs, func(i, j int) bool { return reqs[i].Id < reqs[j].Id })
+	return reqs, nil
+}
+
+// CreateEmail adds an email about a VASP to the outbox, assigning it a new ID.
+func (s *memStore) CreateEmail(e pb.Email) (id uint64, err error) {
+	if e.Vasp == 0 {
+		return 0, ErrIncompleteRecord
+	}
+
+	if e.Created == "" {
+		e.Created = time.Now().Format(time.RFC3339)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	e.Id, _ = s.next()
+	s.emails[e.Id] = proto.Clone(&e).(*pb.Email)
+	return e.Id, nil
+}
+
+// UpdateEmail overwrites the outbox email with the specified ID (required).
+func (s *memStore) UpdateEmail(e pb.Email) (err error) {
+	if e.Id == 0 {
+		return ErrIncompleteRecord
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.emails[e.Id]; !ok {
+		return ErrEntityNotFound
+	}
+	s.emails[e.Id] = proto.Clone(&e).(*pb.Email)
+	return nil
+}
+
+// DeleteEmail removes an email from the outbox.
+func (s *memStore) DeleteEmail(id uint64) error {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.emails, id)
+	return nil
+}
+
+// ListEmails returns all of the emails in the outbox ordered by ID.
+func (s *memStore) ListEmails() (emails []pb.Email, err error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	emails = make([]pb.Email, 0, len(s.emails))
+	for _, e := range s.emails {
+		emails = append(emails, *proto.Clone(e).(*pb.Email))
+	}
+
+	sort.Slice(emails, func(i, j int) bool { return emails[i].Id < emails[j].Id })
+	return emails, nil
+}
+
+// appends the audit entry of a change to a VASP by the actor to the history of the VASP,
+// the lock must be held by the caller
+func (s *memStore) appendAudit(action pb.AuditAction, before, after *pb.VASP, a Actor) (err error) {
+	var entry *pb.AuditEntry
+	if entry, err = newAuditEntry(action, before, after, a); err != nil {
+		return err
+	}
+
+	entry.Id, _ = s.next()
+	s.audit[entry.Vasp] = append(s.audit[entry.Vasp], entry)
+	return nil
+}
+
+// returns the next ID of the primary key sequence, the lock must be held by the caller
+func (s *memStore) next() (uint64, error) {
+	s.sequence++
+	return s.sequence, nil
+}
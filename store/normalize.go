@@ -0,0 +1,81 @@
+package store
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Legal entity suffixes that are normalized to a canonical abbreviation so that e.g.
+// "Example Limited" and "Example Ltd." refer to the same VASP. Suffixes are matched on
+// whole words at the end of the name after punctuation has been removed.
+var legalSuffixes = []struct {
+	words     []string
+	canonical string
+}{
+	{[]string{"limited", "liability", "company"}, "llc"},
+	{[]string{"limited", "liability", "partnership"}, "llp"},
+	{[]string{"public", "limited", "company"}, "plc"},
+	{[]string{"limited"}, "ltd"},
+	{[]string{"incorporated"}, "inc"},
+	{[]string{"corporation"}, "corp"},
+	{[]string{"company"}, "co"},
+	{[]string{"gesellschaft", "mit", "beschrankter", "haftung"}, "gmbh"},
+	{[]string{"aktiengesellschaft"}, "ag"},
+	{[]string{"societe", "anonyme"}, "sa"},
+	{[]string{"sociedad", "anonima"}, "sa"},
+}
+
+// NormalizeName returns the canonical form of a VASP name that is used to enforce the
+// uniqueness of names and to search for VASPs by name. Names are compared using Unicode
+// compatibility normalization and case folding; diacritics, periods and apostrophes
+// are removed (e.g. "S.A." is "sa"), other punctuation and whitespace separate words,
+// runs of whitespace are collapsed, and legal suffixes such as "Limited" are replaced
+// with their common abbreviation. All storage backends must index names with this
+// function so that lookups behave the same regardless of the database.
+func NormalizeName(name string) string {
+	// Decompose so that diacritics can be removed, then fold the case
+	name = norm.NFKD.String(name)
+	name = cases.Fold().String(name)
+
+	var sb strings.Builder
+	sb.Grow(len(name))
+	for _, r := range name {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// drop combining marks (diacritics)
+		case r == '.' || r == '\'' || r == '’':
+			// drop abbreviation and possessive punctuation
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			sb.WriteRune(' ')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	words := strings.Fields(norm.NFC.String(sb.String()))
+	for _, suffix := range legalSuffixes {
+		if hasSuffix(words, suffix.words) {
+			words = append(words[:len(words)-len(suffix.words)], suffix.canonical)
+			break
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// returns true if the last words match the suffix, the name must not be only a suffix
+func hasSuffix(words, suffix []string) bool {
+	if len(words) <= len(suffix) {
+		return false
+	}
+
+	offset := len(words) - len(suffix)
+	for i, word := range suffix {
+		if words[offset+i] != word {
+			return false
+		}
+	}
+	return true
+}
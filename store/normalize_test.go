@@ -0,0 +1,41 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"", ""},
+		{"  Example   VASP  ", "example vasp"},
+		{"Example\tVASP\n", "example vasp"},
+		{"EXAMPLE VASP", "example vasp"},
+		{"Example Ltd.", "example ltd"},
+		{"Example Limited", "example ltd"},
+		{"example ltd", "example ltd"},
+		{"Example, Inc.", "example inc"},
+		{"Example Incorporated", "example inc"},
+		{"Example Corporation", "example corp"},
+		{"Example Limited Liability Company", "example llc"},
+		{"Example L.L.C.", "example llc"},
+		{"Example Public Limited Company", "example plc"},
+		{"Exämple S.A.", "example sa"},
+		{"Société Anonyme Exemple", "societe anonyme exemple"},
+		{"Exemple Société Anonyme", "exemple sa"},
+		{"Straße Exchange GmbH", "strasse exchange gmbh"},
+		{"ＥＸＡＭＰＬＥ", "example"},
+		{"O'Neil's Exchange", "oneils exchange"},
+		{"Crypto-Exchange (Europe)", "crypto exchange europe"},
+		{"Limited", "limited"},
+		{"Limited Company", "limited co"},
+	}
+
+	for _, tc := range tests {
+		require.Equal(t, tc.expected, NormalizeName(tc.name), "could not normalize %q", tc.name)
+	}
+}
@@ -0,0 +1,45 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresFilter(t *testing.T) {
+	tests := []struct {
+		query *pb.Query
+		where string
+		nargs int
+	}{
+		{&pb.Query{Name: []string{"example"}}, "(name LIKE ANY($1))", 1},
+		{&pb.Query{Name: []string{"example", "exchange bank"}}, "((name LIKE ANY($1) OR (name LIKE ANY($2) AND name LIKE ANY($3))))", 3},
+		{&pb.Query{Name: []string{"..."}}, "", 0},
+		{&pb.Query{Certificate: pb.CertificateValidity_VALID_CERTIFICATE}, "", 0},
+		{&pb.Query{Country: []string{"us"}}, "(country = ANY($1))", 1},
+		{&pb.Query{Name: []string{"example"}, Country: []string{"us"}}, "(name LIKE ANY($1) AND country = ANY($2))", 2},
+		{&pb.Query{Country: []string{"us"}, Lei: []string{"5493001KJTIIGC8Y1R12"}}, "(country = ANY($1) AND lei = ANY($2))", 2},
+		{&pb.Query{Domain: []string{"example.com"}}, "((domain = ANY($1) OR domain LIKE ANY($2)))", 2},
+		{&pb.Query{VerificationStatus: []pb.VerificationState{pb.VerificationState_VERIFIED}}, "(state = ANY($1))", 1},
+		{&pb.Query{Operator: pb.Query_OR, Country: []string{"us"}, Category: []string{"atm"}}, "(country = ANY($1) OR category = ANY($2))", 2},
+		{&pb.Query{Operator: pb.Query_OR, Country: []string{"us"}, Name: []string{"example"}}, "(name LIKE ANY($1) OR country = ANY($2))", 2},
+		{&pb.Query{Operator: pb.Query_OR, Country: []string{"us"}, Certificate: pb.CertificateValidity_VALID_CERTIFICATE}, "", 0},
+		{
+			&pb.Query{
+				Country: []string{"us"},
+				Queries: []*pb.Query{
+					{Operator: pb.Query_OR, Category: []string{"atm"}, Name: []string{"example"}},
+					{Operator: pb.Query_OR, Category: []string{"exchange"}, Lei: []string{"5493001KJTIIGC8Y1R12"}},
+				},
+			},
+			"(country = ANY($1) AND (name LIKE ANY($2) OR category = ANY($3)) AND (category = ANY($4) OR lei = ANY($5)))", 5,
+		},
+	}
+
+	for _, tc := range tests {
+		var args []interface{}
+		require.Equal(t, tc.where, pgFilter(tc.query, &args), tc.query.String())
+		require.Len(t, args, tc.nargs, tc.query.String())
+	}
+}
@@ -0,0 +1,212 @@
+package store
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bbengfort/trisads/pb"
+)
+
+// CheckQuery returns ErrEmptyQuery if the query or any of its nested queries do not
+// have any conditions, which would otherwise match every VASP in the directory.
+func CheckQuery(q *pb.Query) error {
+	if q == nil {
+		return ErrEmptyQuery
+	}
+
+	conditions := len(q.Name) + len(q.Country) + len(q.Category) + len(q.Lei) +
+		len(q.Domain) + len(q.VerificationStatus) + len(q.Queries)
+	if q.Certificate != pb.CertificateValidity_ANY_CERTIFICATE {
+		conditions++
+	}
+
+	if conditions == 0 {
+		return ErrEmptyQuery
+	}
+
+	for _, sub := range q.Queries {
+		if err := CheckQuery(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Match evaluates the query against the VASP, returning true if it matches along with
+// the relevance score of the match. The score is the highest Relevance of the VASP name
+// to any of the name conditions that matched, or 0 if no name conditions matched. All
+// storage backends should use Match to evaluate queries so that they return the same
+// results, though they may use indices to find the VASPs that must be evaluated.
+func Match(q *pb.Query, v *pb.VASP) (ok bool, score float64) {
+	if q == nil || v.VaspEntity == nil {
+		return false, 0
+	}
+
+	// Collect the results of each condition that is set on the query
+	var matches []bool
+	if len(q.Name) > 0 {
+		s := matchName(q.Name, v.VaspEntity.VaspFullLegalName)
+		matches = append(matches, s > 0)
+		score = s
+	}
+
+	if len(q.Country) > 0 {
+		matches = append(matches, matchAny(q.Country, v.VaspEntity.VaspCountry, countryKey))
+	}
+
+	if len(q.Category) > 0 {
+		matches = append(matches, matchAny(q.Category, v.VaspEntity.VaspCategory, categoryKey))
+	}
+
+	if len(q.Lei) > 0 {
+		matches = append(matches, matchAny(q.Lei, v.VaspEntity.VaspLEINumber, leiKey))
+	}
+
+	if len(q.Domain) > 0 {
+		matches = append(matches, matchDomain(q.Domain, v.VaspEntity.VaspURL))
+	}
+
+	if len(q.VerificationStatus) > 0 {
+		matches = append(matches, matchState(q.VerificationStatus, v.VerificationStatus))
+	}
+
+	if q.Certificate != pb.CertificateValidity_ANY_CERTIFICATE {
+		matches = append(matches, certificateValidity(v.VaspTRISACertification, time.Now()) == q.Certificate)
+	}
+
+	for _, sub := range q.Queries {
+		m, s := Match(sub, v)
+		matches = append(matches, m)
+		if m && s > score {
+			score = s
+		}
+	}
+
+	if len(matches) == 0 {
+		return false, 0
+	}
+
+	switch q.Operator {
+	case pb.Query_OR:
+		for _, m := range matches {
+			if m {
+				return true, score
+			}
+		}
+		return false, 0
+	default:
+		for _, m := range matches {
+			if !m {
+				return false, 0
+			}
+		}
+		return true, score
+	}
+}
+
+// returns the best relevance of the name to any of the name queries
+func matchName(queries []string, name string) (score float64) {
+	name = NormalizeName(name)
+	for _, query := range queries {
+		if s := Relevance(NormalizeName(query), name); s > score {
+			score = s
+		}
+	}
+	return score
+}
+
+// returns true if the normalized value is equal to any of the normalized queries
+func matchAny(queries []string, value string, normalize func(string) string) bool {
+	if value = normalize(value); value == "" {
+		return false
+	}
+
+	for _, query := range queries {
+		if normalize(query) == value {
+			return true
+		}
+	}
+	return false
+}
+
+// returns true if the host of the URL is any of the domains or one of their subdomains
+func matchDomain(domains []string, vaspURL string) bool {
+	host := domainKey(vaspURL)
+	if host == "" {
+		return false
+	}
+
+	for _, domain := range domains {
+		if domain = domainKey(domain); domain == "" {
+			continue
+		}
+
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchState(states []pb.VerificationState, state pb.VerificationState) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// returns the validity of the certificate at the specified time
+func certificateValidity(cert *pb.TRISACertification, now time.Time) pb.CertificateValidity {
+	if cert == nil {
+		return pb.CertificateValidity_NO_CERTIFICATE
+	}
+
+	if cert.Revoked {
+		return pb.CertificateValidity_INVALID_CERTIFICATE
+	}
+
+	notBefore, err := time.Parse(time.RFC3339, cert.NotValidBefore)
+	if err != nil || now.Before(notBefore) {
+		return pb.CertificateValidity_INVALID_CERTIFICATE
+	}
+
+	notAfter, err := time.Parse(time.RFC3339, cert.NotValidAfter)
+	if err != nil || now.After(notAfter) {
+		return pb.CertificateValidity_INVALID_CERTIFICATE
+	}
+	return pb.CertificateValidity_VALID_CERTIFICATE
+}
+
+// returns the normalized category (case insensitive)
+func categoryKey(category string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(category), " "))
+}
+
+// returns the normalized LEI, which is often formatted with spaces or dashes
+func leiKey(lei string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(lei))
+}
+
+// returns the lowercase host of a URL or domain without the www subdomain, e.g. both
+// "https://WWW.example.com/about" and "example.com" are "example.com"
+func domainKey(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+
+	if !strings.Contains(s, "://") {
+		s = "http://" + s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return ""
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	return strings.TrimPrefix(host, "www.")
+}
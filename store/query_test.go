@@ -0,0 +1,115 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckQuery(t *testing.T) {
+	require.Equal(t, ErrEmptyQuery, CheckQuery(nil))
+	require.Equal(t, ErrEmptyQuery, CheckQuery(&pb.Query{}))
+	require.Equal(t, ErrEmptyQuery, CheckQuery(&pb.Query{Operator: pb.Query_OR}))
+	require.Equal(t, ErrEmptyQuery, CheckQuery(&pb.Query{Name: []string{"example"}, Queries: []*pb.Query{{}}}))
+	require.NoError(t, CheckQuery(&pb.Query{Name: []string{"example"}}))
+	require.NoError(t, CheckQuery(&pb.Query{Certificate: pb.CertificateValidity_NO_CERTIFICATE}))
+	require.NoError(t, CheckQuery(&pb.Query{Queries: []*pb.Query{{Country: []string{"US"}}}}))
+}
+
+func TestMatch(t *testing.T) {
+	now := time.Now()
+	vasp := &pb.VASP{
+		Id:                 42,
+		VerificationStatus: pb.VerificationState_VERIFIED,
+		VaspEntity: &pb.Entity{
+			VaspFullLegalName: "Example Exchange Ltd.",
+			VaspLEINumber:     "5493001KJTIIGC8Y1R12",
+			VaspURL:           "https://www.exchange.example.com/about",
+			VaspCategory:      "Exchange",
+			VaspCountry:       "US",
+		},
+		VaspTRISACertification: &pb.TRISACertification{
+			NotValidBefore: now.Add(-24 * time.Hour).Format(time.RFC3339),
+			NotValidAfter:  now.Add(24 * time.Hour).Format(time.RFC3339),
+		},
+	}
+
+	tests := []struct {
+		query *pb.Query
+		match bool
+	}{
+		{&pb.Query{}, false},
+		{&pb.Query{Name: []string{"example exchange limited"}}, true},
+		{&pb.Query{Name: []string{"exmaple"}}, true},
+		{&pb.Query{Name: []string{"another", "example"}}, true},
+		{&pb.Query{Name: []string{"another"}}, false},
+		{&pb.Query{Country: []string{"us"}}, true},
+		{&pb.Query{Country: []string{"GB", "FR"}}, false},
+		{&pb.Query{Category: []string{"EXCHANGE"}}, true},
+		{&pb.Query{Category: []string{"ATM"}}, false},
+		{&pb.Query{Lei: []string{"5493 001K JTII GC8Y 1R12"}}, true},
+		{&pb.Query{Lei: []string{"HWUPKR0MPOU8FGXBT394"}}, false},
+		{&pb.Query{Domain: []string{"example.com"}}, true},
+		{&pb.Query{Domain: []string{"https://exchange.example.com"}}, true},
+		{&pb.Query{Domain: []string{"www.exchange.example.com"}}, true},
+		{&pb.Query{Domain: []string{"ample.com"}}, false},
+		{&pb.Query{Domain: []string{"other.example.com"}}, false},
+		{&pb.Query{VerificationStatus: []pb.VerificationState{pb.VerificationState_VERIFIED}}, true},
+		{&pb.Query{VerificationStatus: []pb.VerificationState{pb.VerificationState_REVOKED}}, false},
+		{&pb.Query{Certificate: pb.CertificateValidity_VALID_CERTIFICATE}, true},
+		{&pb.Query{Certificate: pb.CertificateValidity_NO_CERTIFICATE}, false},
+
+		// Conditions are combined with AND by default
+		{&pb.Query{Name: []string{"example"}, Country: []string{"US"}}, true},
+		{&pb.Query{Name: []string{"example"}, Country: []string{"GB"}}, false},
+		{&pb.Query{Name: []string{"example"}, Country: []string{"GB"}, Operator: pb.Query_OR}, true},
+		{&pb.Query{Name: []string{"another"}, Country: []string{"GB"}, Operator: pb.Query_OR}, false},
+
+		// Nested queries
+		{&pb.Query{
+			Country: []string{"US"},
+			Queries: []*pb.Query{
+				{Operator: pb.Query_OR, Category: []string{"ATM"}, Lei: []string{"5493001KJTIIGC8Y1R12"}},
+			},
+		}, true},
+		{&pb.Query{
+			Country: []string{"US"},
+			Queries: []*pb.Query{
+				{Operator: pb.Query_OR, Category: []string{"ATM"}, Domain: []string{"example.org"}},
+			},
+		}, false},
+	}
+
+	for i, tc := range tests {
+		match, _ := Match(tc.query, vasp)
+		require.Equal(t, tc.match, match, "test case %d: %s", i, tc.query)
+	}
+
+	// Scores are the best relevance of any matching name condition
+	_, score := Match(&pb.Query{Name: []string{"EXAMPLE EXCHANGE LIMITED"}}, vasp)
+	require.Equal(t, ExactMatch, score)
+
+	_, score = Match(&pb.Query{Operator: pb.Query_OR, Name: []string{"another"}, Country: []string{"US"}}, vasp)
+	require.Zero(t, score)
+
+	_, score = Match(&pb.Query{Country: []string{"US"}, Queries: []*pb.Query{{Name: []string{"example"}}}}, vasp)
+	require.True(t, score > 0 && score < ExactMatch)
+}
+
+func TestCertificateValidity(t *testing.T) {
+	now := time.Now()
+	cert := &pb.TRISACertification{
+		NotValidBefore: now.Add(-24 * time.Hour).Format(time.RFC3339),
+		NotValidAfter:  now.Add(24 * time.Hour).Format(time.RFC3339),
+	}
+
+	require.Equal(t, pb.CertificateValidity_NO_CERTIFICATE, certificateValidity(nil, now))
+	require.Equal(t, pb.CertificateValidity_VALID_CERTIFICATE, certificateValidity(cert, now))
+	require.Equal(t, pb.CertificateValidity_INVALID_CERTIFICATE, certificateValidity(cert, now.Add(48*time.Hour)))
+	require.Equal(t, pb.CertificateValidity_INVALID_CERTIFICATE, certificateValidity(cert, now.Add(-48*time.Hour)))
+
+	cert.Revoked = true
+	require.Equal(t, pb.CertificateValidity_INVALID_CERTIFICATE, certificateValidity(cert, now))
+}
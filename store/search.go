@@ -0,0 +1,186 @@
+package store
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bbengfort/trisads/pb"
+)
+
+// Relevance scores of the different kinds of name matches. Exact matches always rank
+// first, followed by prefix matches, substring matches and finally typo-tolerant
+// matches; within each kind, names that are closer to the query rank higher.
+const (
+	ExactMatch     = 1.0
+	prefixMatch    = 0.8
+	substringMatch = 0.7
+	fuzzyMatch     = 0.6
+)
+
+// SearchResult is a VASP record that matched a search query along with the relevance of
+// the match (between 0 and 1). Records that only match non-name criteria such as the
+// country have a score of 0.
+type SearchResult struct {
+	VASP  pb.VASP
+	Score float64
+}
+
+// SortResults orders search results by descending score, breaking ties by ascending ID
+// so that the order is stable across requests.
+func SortResults(results []SearchResult) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].VASP.Id < results[j].VASP.Id
+	})
+}
+
+// Relevance returns the score of a VASP name for a name search query, or 0 if the name
+// does not match the query. Both the query and the name must already be normalized with
+// NormalizeName. The name matches if it is equal to the query, starts with or contains
+// the query, or if every word in the query is within a small edit distance of (or is a
+// prefix of) a word in the name, e.g. "exmaple exch" matches "example exchange ltd".
+func Relevance(query, name string) float64 {
+	if query == "" || name == "" {
+		return 0
+	}
+
+	if query == name {
+		return ExactMatch
+	}
+
+	// Longer queries that cover more of the name rank higher
+	coverage := float64(len(query)) / float64(len(name))
+	if strings.HasPrefix(name, query) {
+		return prefixMatch + 0.1*coverage
+	}
+
+	if strings.Contains(name, query) {
+		return substringMatch + 0.1*coverage
+	}
+
+	// Every query word must approximately match a word in the name
+	words := strings.Fields(name)
+	var similarity float64
+	for _, qword := range strings.Fields(query) {
+		best := 0.0
+		for _, word := range words {
+			if sim := wordSimilarity(qword, word); sim > best {
+				best = sim
+			}
+		}
+
+		if best == 0 {
+			return 0
+		}
+		similarity += best
+	}
+
+	similarity /= float64(len(strings.Fields(query)))
+	return fuzzyMatch * similarity
+}
+
+// returns the similarity of a query word to a word in the name between 0 and 1 if the
+// word is a prefix or the number of typos is tolerable for the length of the word.
+func wordSimilarity(query, word string) float64 {
+	q, w := []rune(query), []rune(word)
+	if strings.HasPrefix(word, query) {
+		// complete words are more similar than partially typed words
+		return 0.75 + 0.25*float64(len(q))/float64(len(w))
+	}
+
+	d := editDistance(q, w)
+	if d > maxEdits(len(q)) {
+		// Allow typos in words that are being typed, e.g. "exmap" for "example"
+		if len(w) > len(q) {
+			if d = editDistance(q, w[:len(q)]); d > maxEdits(len(q)) {
+				return 0
+			}
+			w = w[:len(q)]
+		} else {
+			return 0
+		}
+	}
+
+	longest := len(q)
+	if len(w) > longest {
+		longest = len(w)
+	}
+	return 1 - float64(d)/float64(longest)
+}
+
+// returns the number of typos tolerated for a word of the specified length
+func maxEdits(n int) int {
+	switch {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// returns the pieces of each word of a normalized name query, at least one of which
+// occurs in every name that the query matches with Relevance, so that databases can
+// select the names that must be scored with substring conditions. A word that matches
+// with up to k typos is split into 2k+1 pieces: each typo changes at most two adjacent
+// pieces (a transposition may span two), so at least one piece occurs unchanged.
+func namePieces(query string) (pieces [][]string) {
+	words := strings.Fields(query)
+	pieces = make([][]string, 0, len(words))
+	for _, word := range words {
+		w := []rune(word)
+		n := 2*maxEdits(len(w)) + 1
+		if n > len(w) {
+			n = len(w)
+		}
+
+		parts := make([]string, 0, n)
+		for i := 0; i < n; i++ {
+			parts = append(parts, string(w[i*len(w)/n:(i+1)*len(w)/n]))
+		}
+		pieces = append(pieces, parts)
+	}
+	return pieces
+}
+
+// computes the Damerau-Levenshtein (optimal string alignment) distance between two
+// words, counting insertions, deletions, substitutions and transpositions as one edit.
+func editDistance(a, b []rune) int {
+	// rows of the dynamic programming table: two rows back, previous row, current row
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = minInt(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
+
+func minInt(vals ...int) int {
+	m := vals[0]
+	for _, v := range vals[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
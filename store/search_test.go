@@ -0,0 +1,114 @@
+package store
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRelevance(t *testing.T) {
+	name := NormalizeName("Example Exchange Limited")
+
+	// Exact matches of the normalized name
+	require.Equal(t, ExactMatch, Relevance(NormalizeName("EXAMPLE EXCHANGE LTD."), name))
+
+	// Prefix, substring and fuzzy matches rank in that order
+	prefix := Relevance(NormalizeName("Example Ex"), name)
+	substring := Relevance(NormalizeName("exchange"), name)
+	typo := Relevance(NormalizeName("Exmaple Exchange"), name)
+	partial := Relevance(NormalizeName("exmaple exch"), name)
+	require.True(t, ExactMatch > prefix && prefix > substring && substring > typo, "%f > %f > %f", prefix, substring, typo)
+	require.True(t, typo > partial, "%f > %f", typo, partial)
+	require.True(t, partial > 0)
+
+	// Longer prefixes rank higher than shorter ones
+	require.True(t, prefix > Relevance("exa", name))
+
+	// Typos are only tolerated in longer words
+	require.True(t, Relevance("exchnage", name) > 0)
+	require.True(t, Relevance("exmap", name) > 0)
+	require.Zero(t, Relevance("exmpl", name))
+	require.Zero(t, Relevance("exa exchange", "exo exchange"))
+	require.Zero(t, Relevance("foo", name))
+	require.Zero(t, Relevance("example bank", name))
+	require.Zero(t, Relevance("", name))
+	require.Zero(t, Relevance("example", ""))
+}
+
+func TestNamePieces(t *testing.T) {
+	require.Equal(t, [][]string{{"foo"}}, namePieces("foo"))
+	require.Equal(t, [][]string{{"e", "xm", "ap"}, {"e", "xc", "ha", "ng", "es"}}, namePieces("exmap exchanges"))
+	require.Empty(t, namePieces(""))
+
+	// Every name that matches the query contains a piece of each of its words
+	matches := func(query, name string) bool {
+		for _, word := range namePieces(query) {
+			found := false
+			for _, piece := range word {
+				if strings.Contains(name, piece) {
+					found = true
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	}
+
+	name := NormalizeName("Example Exchange Limited")
+	queries := []string{
+		"example exchange ltd", "example ex", "exchange", "exmaple exchange", "exmaple exch",
+		"exa", "exchnage", "exmap", "xeample", "exampel", "exmple", "eaxmple exhcange",
+		"exmpl", "foo", "example bank", "xyz exchange",
+	}
+	for _, query := range queries {
+		if Relevance(query, name) > 0 {
+			require.True(t, matches(query, name), "%q matches %q but is not selected", query, name)
+		}
+	}
+	require.False(t, matches("foo", name))
+	require.False(t, matches("example bitcoin", name))
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"example", "example", 0},
+		{"example", "exmaple", 1},
+		{"example", "exampel", 1},
+		{"example", "exmple", 1},
+		{"example", "examples", 1},
+		{"example", "sample", 2},
+		{"kitten", "sitting", 3},
+		{"société", "societe", 2},
+	}
+
+	for _, tc := range tests {
+		require.Equal(t, tc.expected, editDistance([]rune(tc.a), []rune(tc.b)), "%q to %q", tc.a, tc.b)
+	}
+}
+
+func TestSortResults(t *testing.T) {
+	results := []SearchResult{
+		{VASP: pb.VASP{Id: 4}, Score: 0},
+		{VASP: pb.VASP{Id: 3}, Score: 0.5},
+		{VASP: pb.VASP{Id: 1}, Score: 0},
+		{VASP: pb.VASP{Id: 2}, Score: ExactMatch},
+		{VASP: pb.VASP{Id: 5}, Score: 0.5},
+	}
+
+	SortResults(results)
+	ids := make([]uint64, len(results))
+	for i, r := range results {
+		ids[i] = r.VASP.Id
+	}
+	require.Equal(t, []uint64{2, 3, 5, 1, 4}, ids)
+}
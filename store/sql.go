@@ -0,0 +1,65 @@
+package store
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// a database connection or transaction of the SQL storage backends
+type sqlQuerier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// a single row or the current row of a result set
+type sqlScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// escapes the wildcards of a LIKE pattern using the backslash escape character
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// translates name queries into a condition that selects the normalized names that may
+// match any of the queries, i.e. that contain a piece of every word of a query (see
+// namePieces), so that only those names are scored by Match. The like function returns
+// a condition that the name matches any of the LIKE patterns.
+func nameFilter(queries []string, like func(patterns []string) string) string {
+	alternatives := make([]string, 0, len(queries))
+	for _, query := range queries {
+		words := namePieces(NormalizeName(query))
+		if len(words) == 0 {
+			continue
+		}
+
+		conditions := make([]string, len(words))
+		for i, pieces := range words {
+			patterns := make([]string, len(pieces))
+			for j, piece := range pieces {
+				patterns[j] = "%" + escapeLike(piece) + "%"
+			}
+			conditions[i] = like(patterns)
+		}
+		alternatives = append(alternatives, group(conditions, " AND "))
+	}
+
+	if len(alternatives) == 0 {
+		return ""
+	}
+	return group(alternatives, " OR ")
+}
+
+// joins the conditions with the operator, parenthesized unless there is only one
+func group(conditions []string, op string) string {
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	return "(" + strings.Join(conditions, op) + ")"
+}
+
+// returns NULL for empty keys so that VASPs without a value are not constrained
+func nullable(key string) sql.NullString {
+	return sql.NullString{String: key, Valid: key != ""}
+}
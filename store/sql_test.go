@@ -0,0 +1,12 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscapeLike(t *testing.T) {
+	require.Equal(t, "my\\_domain.com", escapeLike("my_domain.com"))
+	require.Equal(t, "100\\%\\\\", escapeLike("100%\\"))
+}
@@ -0,0 +1,1124 @@
+package store
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/mattn/go-sqlite3"
+	log "github.com/sirupsen/logrus"
+)
+
+// OpenSQLite directory Store with the specified sqlite:///path/to/file.db URI, creating
+// the database if it does not exist and applying any schema migrations that have not
+// yet been applied. Unlike the other stores, VASP records are stored in relational
+// tables so that the directory can be queried directly with SQL, e.g. for reports.
+func OpenSQLite(uri string) (Store, error) {
+	dsn, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse sqlite uri: %s", err)
+	}
+
+	if dsn.Path == "" {
+		return nil, errors.New("sqlite uri requires a path to the database file")
+	}
+
+	db, err := sql.Open("sqlite3", dsn.Path+"?_foreign_keys=on&_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, fmt.Errorf("could not open sqlite database: %s", err)
+	}
+
+	// SQLite only allows a single writer, so serialize access to the database
+	db.SetMaxOpenConns(1)
+
+	store := &sqliteStore{db: db}
+	if err = store.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// Schema migrations are applied in order and recorded in the migrations table by their
+// version (the index in the list plus one); applied migrations must never be modified.
+var sqliteMigrations = []string{
+	`CREATE TABLE sequence (
+		name  TEXT PRIMARY KEY,
+		value INTEGER NOT NULL
+	);
+
+	INSERT INTO sequence (name, value) VALUES ('pks', 0);
+
+	CREATE TABLE names (
+		id                  INTEGER PRIMARY KEY,
+		common_name         TEXT NOT NULL,
+		country_region      TEXT NOT NULL,
+		organization        TEXT NOT NULL,
+		organizational_unit TEXT NOT NULL,
+		locality            TEXT NOT NULL,
+		state_province      TEXT NOT NULL,
+		serial_number       TEXT NOT NULL,
+		inc_country_region  TEXT NOT NULL,
+		inc_state_province  TEXT NOT NULL,
+		business_category   TEXT NOT NULL
+	);
+
+	CREATE TABLE public_key_infos (
+		id         INTEGER PRIMARY KEY,
+		algorithm  TEXT NOT NULL,
+		parameters TEXT NOT NULL,
+		public_key BLOB,
+		exponent   INTEGER NOT NULL,
+		key_size   INTEGER NOT NULL,
+		key_usage  TEXT NOT NULL,
+		signature  BLOB
+	);
+
+	CREATE TABLE certifications (
+		id                  INTEGER PRIMARY KEY,
+		subject_name        INTEGER REFERENCES names (id),
+		issuer_name         INTEGER REFERENCES names (id),
+		serial_number       BLOB,
+		version             TEXT NOT NULL,
+		signature_algorithm TEXT NOT NULL,
+		parameters          TEXT NOT NULL,
+		not_valid_before    TEXT NOT NULL,
+		not_valid_after     TEXT NOT NULL,
+		public_key_info     INTEGER REFERENCES public_key_infos (id),
+		revoked             BOOLEAN NOT NULL
+	);
+
+	CREATE TABLE entities (
+		id                   INTEGER PRIMARY KEY,
+		full_legal_name      TEXT NOT NULL,
+		full_legal_address   TEXT NOT NULL,
+		incorporation_date   TEXT NOT NULL,
+		incorporation_number TEXT NOT NULL,
+		lei_number           TEXT NOT NULL,
+		contact_email        TEXT NOT NULL,
+		url                  TEXT NOT NULL,
+		category             TEXT NOT NULL,
+		country              TEXT NOT NULL
+	);
+
+	CREATE TABLE vasps (
+		id                  INTEGER PRIMARY KEY,
+		entity              INTEGER NOT NULL REFERENCES entities (id),
+		certification       INTEGER REFERENCES certifications (id),
+		first_listed        TEXT NOT NULL,
+		last_updated        TEXT NOT NULL,
+		verified_on         TEXT NOT NULL,
+		verification_token  TEXT NOT NULL,
+		verification_status INTEGER NOT NULL,
+		pkcs12_password     TEXT NOT NULL,
+		name_key            TEXT NOT NULL UNIQUE,
+		lei_key             TEXT UNIQUE,
+		domain_key          TEXT UNIQUE,
+		common_name_key     TEXT UNIQUE,
+		serial_key          TEXT UNIQUE,
+		country_key         TEXT NOT NULL,
+		category_key        TEXT NOT NULL
+	);
+
+	CREATE INDEX vasps_country_key_idx ON vasps (country_key);
+
+	CREATE TABLE certreqs (
+		id           INTEGER PRIMARY KEY,
+		vasp         INTEGER NOT NULL,
+		common_name  TEXT NOT NULL,
+		batch_id     INTEGER NOT NULL,
+		batch_name   TEXT NOT NULL,
+		attempts     INTEGER NOT NULL,
+		created      TEXT NOT NULL,
+		next_attempt TEXT NOT NULL,
+		last_error   TEXT NOT NULL
+	);
+
+	CREATE TABLE emails (
+		id              INTEGER PRIMARY KEY,
+		vasp            INTEGER NOT NULL,
+		type            INTEGER NOT NULL,
+		reason          TEXT NOT NULL,
+		attachment      BLOB,
+		attachment_name TEXT NOT NULL,
+		attempts        INTEGER NOT NULL,
+		created         TEXT NOT NULL,
+		next_attempt    TEXT NOT NULL,
+		last_error      TEXT NOT NULL
+	);`,
+
+	`CREATE TABLE audit (
+		id        INTEGER PRIMARY KEY,
+		vasp      INTEGER NOT NULL,
+		action    INTEGER NOT NULL,
+		timestamp TEXT NOT NULL,
+		actor     TEXT NOT NULL,
+		address   TEXT NOT NULL,
+		rpc       TEXT NOT NULL
+	);
+
+	CREATE INDEX audit_vasp_idx ON audit (vasp, id);
+
+	CREATE TABLE audit_changes (
+		audit        INTEGER NOT NULL REFERENCES audit (id),
+		field        TEXT NOT NULL,
+		before_value TEXT NOT NULL,
+		after_value  TEXT NOT NULL,
+		PRIMARY KEY (audit, field)
+	);`,
+
+	`ALTER TABLE vasps ADD COLUMN delisted_on TEXT NOT NULL DEFAULT '';
+	ALTER TABLE vasps ADD COLUMN delist_reason TEXT NOT NULL DEFAULT '';
+	ALTER TABLE vasps ADD COLUMN purge_after TEXT NOT NULL DEFAULT '';`,
+
+	`ALTER TABLE vasps ADD COLUMN version INTEGER NOT NULL DEFAULT 0;`,
+}
+
+// Columns of the vasps table that store the keys of each unique index.
+var sqliteIndexColumns = map[Index]string{
+	NameIndex:       "name_key",
+	LEIIndex:        "lei_key",
+	DomainIndex:     "domain_key",
+	CommonNameIndex: "common_name_key",
+	SerialIndex:     "serial_key",
+}
+
+// Selects the VASP records along with their entity and certification. The certification
+// columns are NULL if the VASP does not have a certificate, so they are coalesced into
+// zero values; subrecords with a zero ID do not exist.
+const sqliteSelectVASPs = `SELECT
+	v.id, v.first_listed, v.last_updated, v.verified_on, v.verification_token,
+	v.verification_status, v.pkcs12_password, v.delisted_on, v.delist_reason, v.purge_after,
+	v.version, e.id, e.full_legal_name, e.full_legal_address, e.incorporation_date,
+	e.incorporation_number, e.lei_number, e.contact_email, e.url, e.category, e.country,
+	COALESCE(c.id, 0), c.serial_number, COALESCE(c.version, ''),
+	COALESCE(c.signature_algorithm, ''), COALESCE(c.parameters, '[]'),
+	COALESCE(c.not_valid_before, ''), COALESCE(c.not_valid_after, ''), COALESCE(c.revoked, 0),
+	COALESCE(sn.id, 0), COALESCE(sn.common_name, ''), COALESCE(sn.country_region, ''),
+	COALESCE(sn.organization, ''), COALESCE(sn.organizational_unit, ''),
+	COALESCE(sn.locality, ''), COALESCE(sn.state_province, ''),
+	COALESCE(sn.serial_number, ''), COALESCE(sn.inc_country_region, ''),
+	COALESCE(sn.inc_state_province, ''), COALESCE(sn.business_category, ''),
+	COALESCE(isn.id, 0), COALESCE(isn.common_name, ''), COALESCE(isn.country_region, ''),
+	COALESCE(isn.organization, ''), COALESCE(isn.organizational_unit, ''),
+	COALESCE(isn.locality, ''), COALESCE(isn.state_province, ''),
+	COALESCE(isn.serial_number, ''), COALESCE(isn.inc_country_region, ''),
+	COALESCE(isn.inc_state_province, ''), COALESCE(isn.business_category, ''),
+	COALESCE(k.id, 0), COALESCE(k.algorithm, ''), COALESCE(k.parameters, '[]'),
+	k.public_key, COALESCE(k.exponent, 0), COALESCE(k.key_size, 0),
+	COALESCE(k.key_usage, '[]'), k.signature
+	FROM vasps v
+	JOIN entities e ON e.id = v.entity
+	LEFT JOIN certifications c ON c.id = v.certification
+	LEFT JOIN names sn ON sn.id = c.subject_name
+	LEFT JOIN names isn ON isn.id = c.issuer_name
+	LEFT JOIN public_key_infos k ON k.id = c.public_key_info`
+
+// Implements Store on an embedded SQLite database. VASP records are normalized into
+// relational tables for the entity, certification, names and public key info, and the
+// normalized keys of the unique indices are stored on the vasps table where they are
+// enforced by unique constraints. IDs are assigned from a sequence that is shared by
+// all records so that they are unique across tables.
+type sqliteStore struct {
+	db *sql.DB
+}
+
+// applies the schema migrations that have not been applied to the database.
+func (s *sqliteStore) migrate() (err error) {
+	var tx *sql.Tx
+	if tx, err = s.db.Begin(); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.Exec(`CREATE TABLE IF NOT EXISTS migrations (
+		version INTEGER PRIMARY KEY,
+		applied TEXT NOT NULL
+	)`); err != nil {
+		return err
+	}
+
+	var version int
+	if err = tx.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM migrations`).Scan(&version); err != nil {
+		return err
+	}
+
+	if version > len(sqliteMigrations) {
+		return fmt.Errorf("database schema version %d is newer than supported version %d", version, len(sqliteMigrations))
+	}
+
+	for ; version < len(sqliteMigrations); version++ {
+		if _, err = tx.Exec(sqliteMigrations[version]); err != nil {
+			return fmt.Errorf("could not apply migration %d: %s", version+1, err)
+		}
+
+		if _, err = tx.Exec(`INSERT INTO migrations (version, applied) VALUES (?, ?)`, version+1, time.Now().Format(time.RFC3339)); err != nil {
+			return err
+		}
+		log.WithField("version", version+1).Info("applied sqlite schema migration")
+	}
+	return tx.Commit()
+}
+
+// Close the database, allowing no further interactions.
+func (s *sqliteStore) Close() error {
+	return s.db.Close()
+}
+
+// Create a VASP into the directory. This method requires the VASP to have a unique
+// name (as well as a unique LEI, domain, certificate common name and certificate serial
+// number if they are set) and ignores any ID fields that are set on the VASP, instead
+// assigning new IDs.
+func (s *sqliteStore) Create(v pb.VASP, a Actor) (id uint64, err error) {
+	return s.create(v, nil, a)
+}
+
+// CreateWithEmail creates a VASP and queues the email in the outbox in a single
+// transaction, so that either both the record and the email are stored or neither are.
+func (s *sqliteStore) CreateWithEmail(v pb.VASP, e pb.Email, a Actor) (id uint64, err error) {
+	return s.create(v, &e, a)
+}
+
+// creates the VASP records, inserting the optional email about the VASP and the audit
+// entry of the actor in the same transaction.
+func (s *sqliteStore) create(v pb.VASP, e *pb.Email, a Actor) (id uint64, err error) {
+	if v.VaspEntity == nil {
+		return 0, ErrIncompleteRecord
+	}
+
+	// The name is required for the uniqueness constraint
+	if NameIndex.Key(&v) == "" {
+		return 0, ErrIncompleteRecord
+	}
+
+	// New records must start at the beginning of the verification workflow
+	if !v.VerificationStatus.Initial() {
+		return 0, ErrInvalidTransition
+	}
+
+	// Update management timestamps
+	v.LastUpdated = time.Now().Format(time.RFC3339)
+	if v.FirstListed == "" {
+		v.FirstListed = v.LastUpdated
+	}
+	v.Version = 1
+
+	var tx *sql.Tx
+	if tx, err = s.db.Begin(); err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Insert sets the IDs of the entity even if they are already set
+	if err = assignIDs(&v, true, s.next(tx)); err != nil {
+		return 0, err
+	}
+
+	if err = s.insertVASP(tx, &v); err != nil {
+		return 0, err
+	}
+
+	if e != nil {
+		if e.Id, err = s.next(tx)(); err != nil {
+			return 0, err
+		}
+
+		e.Vasp = v.Id
+		if e.Created == "" {
+			e.Created = v.LastUpdated
+		}
+
+		if err = s.insertEmail(tx, e); err != nil {
+			return 0, err
+		}
+	}
+
+	if err = s.insertAudit(tx, pb.AuditAction_CREATED, nil, &v, a); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return v.Id, nil
+}
+
+// Retrieve a VASP record by id; returns an error if the record does not exist.
+func (s *sqliteStore) Retrieve(id uint64) (v pb.VASP, err error) {
+	return s.retrieve(s.db, id)
+}
+
+// retrieves the VASP record in a transaction or from the database
+func (s *sqliteStore) retrieve(q sqlQuerier, id uint64) (v pb.VASP, err error) {
+	row := q.QueryRow(sqliteSelectVASPs+` WHERE v.id=?`, id)
+	return v, sqliteScanVASP(row, &v)
+}
+
+// Lookup a VASP record by its key in one of the unique indices, e.g. by LEI number. The
+// key is normalized in the same manner as the index; returns ErrEntityNotFound if no
+// VASP has the key.
+func (s *sqliteStore) Lookup(index Index, key string) (v pb.VASP, err error) {
+	column, ok := sqliteIndexColumns[index]
+	if key = index.Normalize(key); !ok || key == "" {
+		return v, ErrEntityNotFound
+	}
+
+	row := s.db.QueryRow(sqliteSelectVASPs+` WHERE v.`+column+`=?`, key)
+	return v, sqliteScanVASP(row, &v)
+}
+
+// Update the VASP entry by the VASP ID (required). This method simply overwrites the
+// entire VASP record and does not update individual fields: the rows of the original
+// entity and certification are replaced by the rows of the updated VASP. The version of
+// the update must match the stored record, otherwise ErrConflict is returned.
+func (s *sqliteStore) Update(v pb.VASP, a Actor) (err error) {
+	if v.Id == 0 || v.VaspEntity == nil {
+		return ErrIncompleteRecord
+	}
+
+	if NameIndex.Key(&v) == "" {
+		return ErrIncompleteRecord
+	}
+
+	var tx *sql.Tx
+	if tx, err = s.db.Begin(); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Retrieve the original record to ensure that its subrecords are replaced
+	var o pb.VASP
+	if o, err = s.retrieve(tx, v.Id); err != nil {
+		return err
+	}
+
+	// Updates must be made to the current version of the record (compare-and-swap)
+	if v.Version != o.Version {
+		return ErrConflict
+	}
+
+	// Ensure the verification state machine is respected
+	if !o.VerificationStatus.CanTransition(v.VerificationStatus) {
+		return ErrInvalidTransition
+	}
+
+	// Check to ensure all subrecords have unique identifiers if not already set
+	if err = assignIDs(&v, false, s.next(tx)); err != nil {
+		return err
+	}
+	v.LastUpdated = time.Now().Format(time.RFC3339)
+	v.Version = o.Version + 1
+
+	// The unique constraints are checked when the updated VASP is inserted
+	if err = s.deleteVASP(tx, &o); err != nil {
+		return err
+	}
+
+	if err = s.insertVASP(tx, &v); err != nil {
+		return err
+	}
+
+	if err = s.insertAudit(tx, pb.AuditAction_UPDATED, &o, &v, a); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Destroy a record, removing it and its entity and certification from the database.
+// The audit history of the record is retained.
+func (s *sqliteStore) Destroy(id uint64, a Actor) (err error) {
+	var tx *sql.Tx
+	if tx, err = s.db.Begin(); err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var o pb.VASP
+	if o, err = s.retrieve(tx, id); err != nil {
+		if err == ErrEntityNotFound {
+			return nil
+		}
+		return err
+	}
+
+	if err = s.deleteVASP(tx, &o); err != nil {
+		return err
+	}
+
+	if err = s.insertAudit(tx, pb.AuditAction_DELETED, &o, nil, a); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// List all of the VASP records in the database ordered by ID.
+func (s *sqliteStore) List() (vasps []pb.VASP, err error) {
+	return s.listVASPs(sqliteSelectVASPs + ` ORDER BY v.id`)
+}
+
+// ListAfter returns up to limit VASP records with an ID greater than the specified ID
+// ordered by ID; all of the records after the ID are returned if the limit is zero.
+func (s *sqliteStore) ListAfter(after uint64, limit int) (vasps []pb.VASP, err error) {
+	if limit <= 0 {
+		limit = -1
+	}
+	return s.listVASPs(sqliteSelectVASPs+` WHERE v.id > ? ORDER BY v.id LIMIT ?`, after, limit)
+}
+
+// Search returns the VASPs that match the query, evaluating the query against each
+// VASP record with Match and ordering the results by descending relevance. Conditions
+// that have exact matches (e.g. countries, categories and LEI numbers) are translated
+// into SQL to select the VASPs that must be evaluated. Names are matched by their
+// Relevance to the query, which tolerates typos, so SQL only selects the names that
+// contain a piece of every query word that survives the tolerated typos, and the names
+// are then scored by Match.
+func (s *sqliteStore) Search(query *pb.Query) (results []SearchResult, err error) {
+	if err = CheckQuery(query); err != nil {
+		return nil, err
+	}
+
+	stmt := sqliteSelectVASPs
+	var args []interface{}
+	if where := sqliteFilter(query, &args); where != "" {
+		stmt += ` WHERE ` + where
+	}
+
+	var vasps []pb.VASP
+	if vasps, err = s.listVASPs(stmt, args...); err != nil {
+		return nil, err
+	}
+
+	results = make([]SearchResult, 0)
+	for i := range vasps {
+		if ok, score := Match(query, &vasps[i]); ok {
+			results = append(results, SearchResult{VASP: vasps[i], Score: score})
+		}
+	}
+
+	SortResults(results)
+	return results, nil
+}
+
+// History returns the audit entries of the VASP in the order they were appended. The
+// changes of each entry are joined from the audit_changes table, ordered by field.
+func (s *sqliteStore) History(id uint64) (entries []pb.AuditEntry, err error) {
+	var rows *sql.Rows
+	if rows, err = s.db.Query(`SELECT
+		a.id, a.vasp, a.action, a.timestamp, a.actor, a.address, a.rpc,
+		c.field, c.before_value, c.after_value
+		FROM audit a
+		LEFT JOIN audit_changes c ON c.audit = a.id
+		WHERE a.vasp=? ORDER BY a.id, c.field`, id); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries = make([]pb.AuditEntry, 0)
+	for rows.Next() {
+		var entry pb.AuditEntry
+		var field, before, after sql.NullString
+		if err = rows.Scan(append(auditFields(&entry), &field, &before, &after)...); err != nil {
+			return nil, err
+		}
+
+		// Each change of an entry is a row, so only the first row appends the entry
+		if n := len(entries); n == 0 || entries[n-1].Id != entry.Id {
+			entries = append(entries, entry)
+		}
+
+		if field.Valid {
+			last := &entries[len(entries)-1]
+			last.Changes = append(last.Changes, &pb.FieldChange{Field: field.String, Before: before.String, After: after.String})
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// CreateCertReq adds a certificate request to the queue, assigning it a new ID.
+func (s *sqliteStore) CreateCertReq(r pb.CertificateRequest) (id uint64, err error) {
+	if r.Vasp == 0 {
+		return 0, ErrIncompleteRecord
+	}
+
+	if r.Created == "" {
+		r.Created = time.Now().Format(time.RFC3339)
+	}
+
+	var tx *sql.Tx
+	if tx, err = s.db.Begin(); err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if r.Id, err = s.next(tx)(); err != nil {
+		return 0, err
+	}
+
+	if _, err = tx.Exec(`INSERT INTO certreqs
+		(id, vasp, common_name, batch_id, batch_name, attempts, created, next_attempt, last_error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, certReqFields(&r)...); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return r.Id, nil
+}
+
+// UpdateCertReq overwrites the certificate request with the specified ID (required).
+func (s *sqliteStore) UpdateCertReq(r pb.CertificateRequest) (err error) {
+	if r.Id == 0 {
+		return ErrIncompleteRecord
+	}
+
+	return sqliteUpdated(s.db.Exec(`UPDATE certreqs SET
+		vasp=?2, common_name=?3, batch_id=?4, batch_name=?5, attempts=?6, created=?7, next_attempt=?8, last_error=?9
+		WHERE id=?1`, certReqFields(&r)...))
+}
+
+// DeleteCertReq removes a certificate request from the queue.
+func (s *sqliteStore) DeleteCertReq(id uint64) (err error) {
+	_, err = s.db.Exec(`DELETE FROM certreqs WHERE id=?`, id)
+	return err
+}
+
+// ListCertReqs returns all of the certificate requests in the queue.
+func (s *sqliteStore) ListCertReqs() (reqs []pb.CertificateRequest, err error) {
+	var rows *sql.Rows
+	if rows, err = s.db.Query(`SELECT
+		id, vasp, common_name, batch_id, batch_name, attempts, created, next_attempt, last_error
+		FROM certreqs ORDER BY id`); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reqs = make([]pb.CertificateRequest, 0)
+	for rows.Next() {
+		var r pb.CertificateRequest
+		if err = rows.Scan(certReqFields(&r)...); err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, r)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return reqs, nil
+}
+
+// CreateEmail adds an email about a VASP to the outbox, assigning it a new ID.
+func (s *sqliteStore) CreateEmail(e pb.Email) (id uint64, err error) {
+	if e.Vasp == 0 {
+		return 0, ErrIncompleteRecord
+	}
+
+	if e.Created == "" {
+		e.Created = time.Now().Format(time.RFC3339)
+	}
+
+	var tx *sql.Tx
+	if tx, err = s.db.Begin(); err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if e.Id, err = s.next(tx)(); err != nil {
+		return 0, err
+	}
+
+	if err = s.insertEmail(tx, &e); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+	return e.Id, nil
+}
+
+// UpdateEmail overwrites the outbox email with the specified ID (required).
+func (s *sqliteStore) UpdateEmail(e pb.Email) (err error) {
+	if e.Id == 0 {
+		return ErrIncompleteRecord
+	}
+
+	return sqliteUpdated(s.db.Exec(`UPDATE emails SET
+		vasp=?2, type=?3, reason=?4, attachment=?5, attachment_name=?6, attempts=?7, created=?8, next_attempt=?9, last_error=?10
+		WHERE id=?1`, emailFields(&e)...))
+}
+
+// DeleteEmail removes an email from the outbox.
+func (s *sqliteStore) DeleteEmail(id uint64) (err error) {
+	_, err = s.db.Exec(`DELETE FROM emails WHERE id=?`, id)
+	return err
+}
+
+// ListEmails returns all of the emails in the outbox.
+func (s *sqliteStore) ListEmails() (emails []pb.Email, err error) {
+	var rows *sql.Rows
+	if rows, err = s.db.Query(`SELECT
+		id, vasp, type, reason, attachment, attachment_name, attempts, created, next_attempt, last_error
+		FROM emails ORDER BY id`); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	emails = make([]pb.Email, 0)
+	for rows.Next() {
+		var e pb.Email
+		if err = rows.Scan(emailFields(&e)...); err != nil {
+			return nil, err
+		}
+		emails = append(emails, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return emails, nil
+}
+
+// returns a function that fetches the next ID from the primary key sequence
+func (s *sqliteStore) next(tx *sql.Tx) func() (uint64, error) {
+	return func() (id uint64, err error) {
+		if _, err = tx.Exec(`UPDATE sequence SET value = value + 1 WHERE name = 'pks'`); err != nil {
+			return 0, err
+		}
+
+		if err = tx.QueryRow(`SELECT value FROM sequence WHERE name = 'pks'`).Scan(&id); err != nil {
+			return 0, err
+		}
+		return id, nil
+	}
+}
+
+// inserts the rows of the VASP, its entity and its certification
+func (s *sqliteStore) insertVASP(tx *sql.Tx, v *pb.VASP) (err error) {
+	var certID interface{}
+	if cert := v.VaspTRISACertification; cert != nil {
+		var subject, issuer, key interface{}
+		if cert.SubjectName != nil {
+			if subject, err = s.insertName(tx, cert.SubjectName); err != nil {
+				return err
+			}
+		}
+
+		if cert.IssuerName != nil {
+			if issuer, err = s.insertName(tx, cert.IssuerName); err != nil {
+				return err
+			}
+		}
+
+		if info := cert.PublicKeyInfo; info != nil {
+			if _, err = tx.Exec(`INSERT INTO public_key_infos
+				(id, algorithm, parameters, public_key, exponent, key_size, key_usage, signature)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, publicKeyInfoFields(info)...); err != nil {
+				return sqliteError(err)
+			}
+			key = info.Id
+		}
+
+		args := append(certificationFields(cert), subject, issuer, key)
+		if _, err = tx.Exec(`INSERT INTO certifications
+			(id, serial_number, version, signature_algorithm, parameters, not_valid_before,
+			not_valid_after, revoked, subject_name, issuer_name, public_key_info)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, args...); err != nil {
+			return sqliteError(err)
+		}
+		certID = cert.Id
+	}
+
+	if _, err = tx.Exec(`INSERT INTO entities
+		(id, full_legal_name, full_legal_address, incorporation_date, incorporation_number,
+		lei_number, contact_email, url, category, country)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, entityFields(v.VaspEntity)...); err != nil {
+		return sqliteError(err)
+	}
+
+	args := append(vaspFields(v), v.VaspEntity.Id, certID,
+		NameIndex.Key(v),
+		nullable(LEIIndex.Key(v)),
+		nullable(DomainIndex.Key(v)),
+		nullable(CommonNameIndex.Key(v)),
+		nullable(SerialIndex.Key(v)),
+		countryKey(v.VaspEntity.VaspCountry),
+		categoryKey(v.VaspEntity.VaspCategory),
+	)
+
+	if _, err = tx.Exec(`INSERT INTO vasps
+		(id, first_listed, last_updated, verified_on, verification_token, verification_status,
+		pkcs12_password, delisted_on, delist_reason, purge_after, version, entity,
+		certification, name_key, lei_key, domain_key, common_name_key, serial_key, country_key,
+		category_key)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, args...); err != nil {
+		return sqliteError(err)
+	}
+	return nil
+}
+
+// inserts a certificate name, returning its ID
+func (s *sqliteStore) insertName(tx *sql.Tx, n *pb.Name) (id interface{}, err error) {
+	if _, err = tx.Exec(`INSERT INTO names
+		(id, common_name, country_region, organization, organizational_unit, locality,
+		state_province, serial_number, inc_country_region, inc_state_province, business_category)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, nameFields(n)...); err != nil {
+		return nil, sqliteError(err)
+	}
+	return n.Id, nil
+}
+
+// inserts an email into the outbox
+func (s *sqliteStore) insertEmail(tx *sql.Tx, e *pb.Email) (err error) {
+	_, err = tx.Exec(`INSERT INTO emails
+		(id, vasp, type, reason, attachment, attachment_name, attempts, created, next_attempt, last_error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, emailFields(e)...)
+	return err
+}
+
+// inserts the audit entry of a change to a VASP by the actor and the changed fields
+func (s *sqliteStore) insertAudit(tx *sql.Tx, action pb.AuditAction, before, after *pb.VASP, a Actor) (err error) {
+	var entry *pb.AuditEntry
+	if entry, err = newAuditEntry(action, before, after, a); err != nil {
+		return err
+	}
+
+	if entry.Id, err = s.next(tx)(); err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(`INSERT INTO audit
+		(id, vasp, action, timestamp, actor, address, rpc)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`, auditFields(entry)...); err != nil {
+		return err
+	}
+
+	for _, change := range entry.Changes {
+		if _, err = tx.Exec(`INSERT INTO audit_changes
+			(audit, field, before_value, after_value) VALUES (?, ?, ?, ?)`,
+			entry.Id, change.Field, change.Before, change.After); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deletes the rows of the VASP, its entity and its certification
+func (s *sqliteStore) deleteVASP(tx *sql.Tx, v *pb.VASP) (err error) {
+	stmts := []string{`DELETE FROM vasps WHERE id=?`, `DELETE FROM entities WHERE id=?`}
+	args := []interface{}{v.Id, v.VaspEntity.Id}
+
+	if cert := v.VaspTRISACertification; cert != nil {
+		stmts = append(stmts, `DELETE FROM certifications WHERE id=?`)
+		args = append(args, cert.Id)
+
+		for _, n := range []*pb.Name{cert.SubjectName, cert.IssuerName} {
+			if n != nil {
+				stmts = append(stmts, `DELETE FROM names WHERE id=?`)
+				args = append(args, n.Id)
+			}
+		}
+
+		if cert.PublicKeyInfo != nil {
+			stmts = append(stmts, `DELETE FROM public_key_infos WHERE id=?`)
+			args = append(args, cert.PublicKeyInfo.Id)
+		}
+	}
+
+	for i, stmt := range stmts {
+		if _, err = tx.Exec(stmt, args[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selects the VASP records with the statement
+func (s *sqliteStore) listVASPs(stmt string, args ...interface{}) (vasps []pb.VASP, err error) {
+	var rows *sql.Rows
+	if rows, err = s.db.Query(stmt, args...); err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	vasps = make([]pb.VASP, 0)
+	for rows.Next() {
+		var vasp pb.VASP
+		if err = sqliteScanVASP(rows, &vasp); err != nil {
+			return nil, err
+		}
+		vasps = append(vasps, vasp)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return vasps, nil
+}
+
+// scans a row selected by sqliteSelectVASPs into the VASP, returning ErrEntityNotFound
+// if no rows were selected.
+func sqliteScanVASP(row sqlScanner, v *pb.VASP) (err error) {
+	v.VaspEntity = &pb.Entity{}
+	cert := &pb.TRISACertification{SubjectName: &pb.Name{}, IssuerName: &pb.Name{}, PublicKeyInfo: &pb.PublicKeyInfo{}}
+
+	dest := append(vaspFields(v), entityFields(v.VaspEntity)...)
+	dest = append(dest, certificationFields(cert)...)
+	dest = append(dest, nameFields(cert.SubjectName)...)
+	dest = append(dest, nameFields(cert.IssuerName)...)
+	dest = append(dest, publicKeyInfoFields(cert.PublicKeyInfo)...)
+
+	if err = row.Scan(dest...); err != nil {
+		if err == sql.ErrNoRows {
+			return ErrEntityNotFound
+		}
+		return err
+	}
+
+	// Subrecords that were not joined have zero IDs
+	if cert.Id != 0 {
+		if cert.SubjectName.Id == 0 {
+			cert.SubjectName = nil
+		}
+		if cert.IssuerName.Id == 0 {
+			cert.IssuerName = nil
+		}
+		if cert.PublicKeyInfo.Id == 0 {
+			cert.PublicKeyInfo = nil
+		}
+		v.VaspTRISACertification = cert
+	}
+	return nil
+}
+
+// The fields functions return pointers to the fields of a record in the order of the
+// columns of its table, which are used both to scan rows and as the values of inserts.
+
+func vaspFields(v *pb.VASP) []interface{} {
+	return []interface{}{
+		&v.Id, &v.FirstListed, &v.LastUpdated, &v.VerifiedOn, &v.VerificationToken,
+		&v.VerificationStatus, &v.Pkcs12Password, &v.DelistedOn, &v.DelistReason, &v.PurgeAfter,
+		&v.Version,
+	}
+}
+
+func entityFields(e *pb.Entity) []interface{} {
+	return []interface{}{
+		&e.Id, &e.VaspFullLegalName, &e.VaspFullLegalAddress, &e.VaspIncorporationDate,
+		&e.VaspIncorporationNumber, &e.VaspLEINumber, &e.VaspContactEmail, &e.VaspURL,
+		&e.VaspCategory, &e.VaspCountry,
+	}
+}
+
+func certificationFields(c *pb.TRISACertification) []interface{} {
+	return []interface{}{
+		&c.Id, &c.SerialNumber, &c.Version, &c.SignatureAlgorithm, sqliteList{&c.Parameters},
+		&c.NotValidBefore, &c.NotValidAfter, &c.Revoked,
+	}
+}
+
+func nameFields(n *pb.Name) []interface{} {
+	return []interface{}{
+		&n.Id, &n.CommonName, &n.CountryRegion, &n.Organization, &n.OrganizationalUnit,
+		&n.Locality, &n.StateProvince, &n.SerialNumber, &n.IncCountryRegion,
+		&n.IncStateProvince, &n.BusinessCategory,
+	}
+}
+
+func publicKeyInfoFields(k *pb.PublicKeyInfo) []interface{} {
+	return []interface{}{
+		&k.Id, &k.Algorithm, sqliteList{&k.Parameters}, &k.PublicKey, &k.Exponent, &k.KeySize,
+		sqliteList{&k.KeyUsage}, &k.Signature,
+	}
+}
+
+func certReqFields(r *pb.CertificateRequest) []interface{} {
+	return []interface{}{
+		&r.Id, &r.Vasp, &r.CommonName, &r.BatchId, &r.BatchName, &r.Attempts, &r.Created,
+		&r.NextAttempt, &r.LastError,
+	}
+}
+
+func emailFields(e *pb.Email) []interface{} {
+	return []interface{}{
+		&e.Id, &e.Vasp, &e.Type, &e.Reason, &e.Attachment, &e.AttachmentName, &e.Attempts,
+		&e.Created, &e.NextAttempt, &e.LastError,
+	}
+}
+
+func auditFields(e *pb.AuditEntry) []interface{} {
+	return []interface{}{&e.Id, &e.Vasp, &e.Action, &e.Timestamp, &e.Actor, &e.Address, &e.Rpc}
+}
+
+// sqliteList stores repeated string fields as a JSON array in a text column.
+type sqliteList struct {
+	list *[]string
+}
+
+// Value implements driver.Valuer
+func (l sqliteList) Value() (driver.Value, error) {
+	if len(*l.list) == 0 {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(*l.list)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements sql.Scanner
+func (l sqliteList) Scan(src interface{}) error {
+	var data []byte
+	switch val := src.(type) {
+	case string:
+		data = []byte(val)
+	case []byte:
+		data = val
+	default:
+		return fmt.Errorf("cannot scan %T into list", src)
+	}
+
+	*l.list = nil
+	if err := json.Unmarshal(data, l.list); err != nil {
+		return err
+	}
+
+	if len(*l.list) == 0 {
+		*l.list = nil
+	}
+	return nil
+}
+
+// translates the conditions of the query into a SQL condition that selects a superset
+// of the VASPs that match the query, appending the values of the condition's parameters
+// to args. Conditions that cannot be translated (e.g. name relevance and certificate
+// validity, which depends on the current time) select every VASP and are left to
+// Match. An empty string is returned if the query cannot narrow the VASPs at all.
+func sqliteFilter(q *pb.Query, args *[]interface{}) string {
+	in := func(column string, values []string, normalize func(string) string) string {
+		params := make([]string, len(values))
+		for i, value := range values {
+			params[i] = "?"
+			*args = append(*args, normalize(value))
+		}
+		return column + " IN (" + strings.Join(params, ", ") + ")"
+	}
+
+	// Keep track of the number of args so that untranslatable OR queries can be undone
+	nargs := len(*args)
+	conditions := make([]string, 0)
+	narrowed := true
+	add := func(condition string) {
+		if condition == "" {
+			narrowed = false
+			return
+		}
+		conditions = append(conditions, condition)
+	}
+
+	if len(q.Name) > 0 {
+		add(nameFilter(q.Name, func(patterns []string) string {
+			likes := make([]string, len(patterns))
+			for i, pattern := range patterns {
+				likes[i] = `v.name_key LIKE ? ESCAPE '\'`
+				*args = append(*args, pattern)
+			}
+			return group(likes, " OR ")
+		}))
+	}
+
+	if len(q.Country) > 0 {
+		add(in("v.country_key", q.Country, countryKey))
+	}
+
+	if len(q.Category) > 0 {
+		add(in("v.category_key", q.Category, categoryKey))
+	}
+
+	if len(q.Lei) > 0 {
+		add(in("v.lei_key", q.Lei, leiKey))
+	}
+
+	if len(q.Domain) > 0 {
+		// Match the domain itself or any of its subdomains
+		domains := []string{in("v.domain_key", q.Domain, domainKey)}
+		for _, domain := range q.Domain {
+			domains = append(domains, `v.domain_key LIKE ? ESCAPE '\'`)
+			*args = append(*args, "%."+escapeLike(domainKey(domain)))
+		}
+		add("(" + strings.Join(domains, " OR ") + ")")
+	}
+
+	if len(q.VerificationStatus) > 0 {
+		params := make([]string, len(q.VerificationStatus))
+		for i, state := range q.VerificationStatus {
+			params[i] = "?"
+			*args = append(*args, int32(state))
+		}
+		add("v.verification_status IN (" + strings.Join(params, ", ") + ")")
+	}
+
+	if q.Certificate != pb.CertificateValidity_ANY_CERTIFICATE {
+		add("")
+	}
+
+	for _, sub := range q.Queries {
+		add(sqliteFilter(sub, args))
+	}
+
+	if q.Operator == pb.Query_OR {
+		// A disjunction can only be narrowed if every condition can be translated
+		if !narrowed || len(conditions) == 0 {
+			*args = (*args)[:nargs]
+			return ""
+		}
+		return "(" + strings.Join(conditions, " OR ") + ")"
+	}
+
+	if len(conditions) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(conditions, " AND ") + ")"
+}
+
+// returns ErrEntityNotFound if an update statement did not affect any rows
+func sqliteUpdated(res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+
+	var n int64
+	if n, err = res.RowsAffected(); err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return ErrEntityNotFound
+	}
+	return nil
+}
+
+// converts unique and primary key constraint violations into ErrDuplicateEntity
+func sqliteError(err error) error {
+	var serr sqlite3.Error
+	if errors.As(err, &serr) && serr.Code == sqlite3.ErrConstraint {
+		switch serr.ExtendedCode {
+		case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+			return ErrDuplicateEntity
+		}
+	}
+	return err
+}
@@ -0,0 +1,181 @@
+package store
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteFilter(t *testing.T) {
+	// Names that contain a piece of "example" that survives a typo
+	exampleLike := `(v.name_key LIKE ? ESCAPE '\' OR v.name_key LIKE ? ESCAPE '\' OR v.name_key LIKE ? ESCAPE '\')`
+
+	tests := []struct {
+		query *pb.Query
+		where string
+		nargs int
+	}{
+		{&pb.Query{Name: []string{"example"}}, "(" + exampleLike + ")", 3},
+		{&pb.Query{Name: []string{"ex co"}}, `((v.name_key LIKE ? ESCAPE '\' AND v.name_key LIKE ? ESCAPE '\'))`, 2},
+		{&pb.Query{Name: []string{"..."}}, "", 0},
+		{&pb.Query{Country: []string{"us", "gb"}}, "(v.country_key IN (?, ?))", 2},
+		{&pb.Query{Domain: []string{"example.com"}}, `((v.domain_key IN (?) OR v.domain_key LIKE ? ESCAPE '\'))`, 2},
+		{&pb.Query{Operator: pb.Query_OR, Country: []string{"us"}, Name: []string{"example"}}, "(" + exampleLike + " OR v.country_key IN (?))", 4},
+		{
+			&pb.Query{
+				Name: []string{"example"},
+				Queries: []*pb.Query{
+					{Operator: pb.Query_OR, Category: []string{"atm"}, VerificationStatus: []pb.VerificationState{pb.VerificationState_VERIFIED}},
+				},
+			},
+			"(" + exampleLike + " AND (v.category_key IN (?) OR v.verification_status IN (?)))", 5,
+		},
+	}
+
+	for _, tc := range tests {
+		var args []interface{}
+		require.Equal(t, tc.where, sqliteFilter(tc.query, &args), tc.query.String())
+		require.Len(t, args, tc.nargs, tc.query.String())
+	}
+}
+
+func TestSQLiteStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trisads-sqlite")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "trisads.db")
+	db, err := Open("sqlite://" + path)
+	require.NoError(t, err)
+
+	vasp := pb.VASP{
+		VaspEntity: &pb.Entity{
+			VaspFullLegalName: "Example Exchange Ltd.",
+			VaspLEINumber:     "5493001KJTIIGC8Y1R12",
+			VaspURL:           "https://www.example.com",
+			VaspCountry:       "US",
+		},
+	}
+
+	eid, err := db.CreateWithEmail(vasp, pb.Email{Type: pb.EmailType_VERIFY_CONTACT}, testActor)
+	require.NoError(t, err)
+
+	// Unique constraints are enforced by the database
+	_, err = db.Create(pb.VASP{VaspEntity: &pb.Entity{VaspFullLegalName: "EXAMPLE EXCHANGE LIMITED"}}, testActor)
+	require.Equal(t, ErrDuplicateEntity, err)
+	_, err = db.Create(pb.VASP{VaspEntity: &pb.Entity{VaspFullLegalName: "Other", VaspURL: "example.com"}}, testActor)
+	require.Equal(t, ErrDuplicateEntity, err)
+
+	oid, err := db.Create(pb.VASP{VaspEntity: &pb.Entity{VaspFullLegalName: "Other Exchange", VaspCountry: "GB"}}, testActor)
+	require.NoError(t, err)
+
+	vasp, err = db.Lookup(LEIIndex, "5493-001K-JTII-GC8Y-1R12")
+	require.NoError(t, err)
+	require.Equal(t, eid, vasp.Id)
+	require.Equal(t, "Example Exchange Ltd.", vasp.VaspEntity.VaspFullLegalName)
+	require.Nil(t, vasp.VaspTRISACertification)
+
+	// Certificates are stored in their own tables and retrieved with the VASP
+	vasp.VerificationStatus = pb.VerificationState_SUBMITTED
+	vasp.VaspTRISACertification = &pb.TRISACertification{
+		SubjectName:    &pb.Name{CommonName: "trisa.example.com", Organization: "Example Exchange"},
+		SerialNumber:   []byte{0x1a, 0x2b, 0x3c},
+		Parameters:     []string{"a", "b"},
+		NotValidBefore: "2020-01-01T00:00:00Z",
+		NotValidAfter:  "2021-01-01T00:00:00Z",
+		PublicKeyInfo:  &pb.PublicKeyInfo{Algorithm: "RSA", PublicKey: []byte("key"), KeySize: 2048},
+		Revoked:        true,
+	}
+	require.NoError(t, db.Update(vasp, testActor))
+
+	vasp, err = db.Lookup(SerialIndex, "1A:2B:3C")
+	require.NoError(t, err)
+	require.Equal(t, eid, vasp.Id)
+	require.Equal(t, pb.VerificationState_SUBMITTED, vasp.VerificationStatus)
+
+	cert := vasp.VaspTRISACertification
+	require.NotNil(t, cert)
+	require.NotZero(t, cert.Id)
+	require.Equal(t, "trisa.example.com", cert.SubjectName.CommonName)
+	require.Nil(t, cert.IssuerName)
+	require.Equal(t, []string{"a", "b"}, cert.Parameters)
+	require.Equal(t, []byte{0x1a, 0x2b, 0x3c}, cert.SerialNumber)
+	require.Equal(t, int64(2048), cert.PublicKeyInfo.KeySize)
+	require.Nil(t, cert.PublicKeyInfo.KeyUsage)
+	require.True(t, cert.Revoked)
+
+	// Renaming a VASP to the name of another VASP violates the unique constraint
+	vasp.VaspEntity.VaspFullLegalName = "other exchange"
+	require.Equal(t, ErrDuplicateEntity, db.Update(vasp, testActor))
+
+	vasp.VaspEntity.VaspFullLegalName = "Example Exchange Ltd."
+	vasp.VerificationStatus = pb.VerificationState_VERIFIED
+	require.Equal(t, ErrInvalidTransition, db.Update(vasp, testActor))
+
+	results, err := db.Search(&pb.Query{Name: []string{"exmaple"}, Country: []string{"us"}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, eid, results[0].VASP.Id)
+
+	results, err = db.Search(&pb.Query{Operator: pb.Query_OR, Country: []string{"gb"}, Domain: []string{"example.com"}})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	vasps, err := db.List()
+	require.NoError(t, err)
+	require.Len(t, vasps, 2)
+
+	// The email was queued with the VASP
+	emails, err := db.ListEmails()
+	require.NoError(t, err)
+	require.Len(t, emails, 1)
+	require.Equal(t, eid, emails[0].Vasp)
+	require.Equal(t, pb.EmailType_VERIFY_CONTACT, emails[0].Type)
+
+	emails[0].Attempts++
+	require.NoError(t, db.UpdateEmail(emails[0]))
+	require.NoError(t, db.DeleteEmail(emails[0].Id))
+	require.Equal(t, ErrEntityNotFound, db.UpdateEmail(emails[0]))
+
+	rid, err := db.CreateCertReq(pb.CertificateRequest{Vasp: eid, CommonName: "trisa.example.com"})
+	require.NoError(t, err)
+	require.True(t, rid > oid)
+
+	reqs, err := db.ListCertReqs()
+	require.NoError(t, err)
+	require.Len(t, reqs, 1)
+	require.Equal(t, "trisa.example.com", reqs[0].CommonName)
+	require.NoError(t, db.DeleteCertReq(rid))
+
+	// Destroying a VASP removes the rows of its entity and certificate
+	require.NoError(t, db.Destroy(eid, testActor))
+	require.NoError(t, db.Destroy(eid, testActor))
+	_, err = db.Retrieve(eid)
+	require.Equal(t, ErrEntityNotFound, err)
+	require.NoError(t, db.Close())
+
+	// The directory can be queried with SQL, e.g. for reports
+	conn, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var entities, certifications int
+	require.NoError(t, conn.QueryRow(`SELECT COUNT(*) FROM entities`).Scan(&entities))
+	require.NoError(t, conn.QueryRow(`SELECT COUNT(*) FROM certifications`).Scan(&certifications))
+	require.Equal(t, 1, entities)
+	require.Equal(t, 0, certifications)
+
+	// Reopening the database does not reapply migrations or reset the sequence
+	db, err = Open("sqlite://" + path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	id, err := db.Create(pb.VASP{VaspEntity: &pb.Entity{VaspFullLegalName: "Example Exchange Ltd."}}, testActor)
+	require.NoError(t, err)
+	require.True(t, id > rid)
+}
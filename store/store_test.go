@@ -0,0 +1,491 @@
+package store
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/stretchr/testify/require"
+)
+
+// Every Store backend must pass the same conformance tests so that the directory service
+// behaves identically regardless of the database it is deployed with. The postgres
+// backend is only tested if $TRISADS_TEST_POSTGRES is set to a database that is created
+// for the test, e.g. postgres://localhost/trisads_test?sslmode=disable, since its
+// tables are truncated before the tests are run.
+func TestStoreConformance(t *testing.T) {
+	backends := map[string]func(t *testing.T) (Store, func()){
+		"memory": func(t *testing.T) (Store, func()) {
+			db, err := Open("memory://")
+			require.NoError(t, err)
+			return db, func() { db.Close() }
+		},
+		"leveldb": func(t *testing.T) (Store, func()) {
+			dir, err := ioutil.TempDir("", "trisads-leveldb")
+			require.NoError(t, err)
+			db, err := Open("leveldb:///" + filepath.Join(dir, "db"))
+			require.NoError(t, err)
+			return db, func() { db.Close(); os.RemoveAll(dir) }
+		},
+		"sqlite": func(t *testing.T) (Store, func()) {
+			dir, err := ioutil.TempDir("", "trisads-sqlite")
+			require.NoError(t, err)
+			db, err := Open("sqlite:///" + filepath.Join(dir, "trisads.db"))
+			require.NoError(t, err)
+			return db, func() { db.Close(); os.RemoveAll(dir) }
+		},
+		"postgres": func(t *testing.T) (Store, func()) {
+			uri := os.Getenv("TRISADS_TEST_POSTGRES")
+			if uri == "" {
+				t.Skip("set $TRISADS_TEST_POSTGRES to test the postgres store")
+			}
+			db, err := Open(uri)
+			require.NoError(t, err)
+			_, err = db.(*pgStore).db.Exec(`TRUNCATE vasps, certreqs, emails, audit`)
+			require.NoError(t, err)
+			return db, func() { db.Close() }
+		},
+	}
+
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			db, cleanup := open(t)
+			defer cleanup()
+			testStoreVASPs(t, db)
+			testStoreSearch(t, db)
+			testStoreQueues(t, db)
+			testStoreHistory(t, db)
+			testStoreList(t, db)
+		})
+	}
+}
+
+// The actor that makes the changes in the store tests
+var testActor = Actor{Name: "tester", Address: "127.0.0.1:4435", RPC: "/pb.TRISAAdmin/Test"}
+
+// tests the VASP create, retrieve, lookup, update and destroy semantics
+func testStoreVASPs(t *testing.T, db Store) {
+	vasp := pb.VASP{
+		Id: 42,
+		VaspEntity: &pb.Entity{
+			Id:                42,
+			VaspFullLegalName: "Conformance Exchange Ltd.",
+			VaspLEINumber:     "5493001KJTIIGC8Y1R12",
+			VaspURL:           "https://www.conformance.io",
+			VaspCountry:       "US",
+		},
+		VaspTRISACertification: &pb.TRISACertification{
+			SubjectName:  &pb.Name{CommonName: "trisa.conformance.io"},
+			SerialNumber: []byte{0x0a, 0xbc},
+		},
+	}
+
+	// Records must be complete and start at the beginning of the verification workflow
+	_, err := db.Create(pb.VASP{}, testActor)
+	require.Equal(t, ErrIncompleteRecord, err)
+	_, err = db.Create(pb.VASP{VaspEntity: &pb.Entity{VaspCountry: "US"}}, testActor)
+	require.Equal(t, ErrIncompleteRecord, err)
+	_, err = db.Create(pb.VASP{VaspEntity: &pb.Entity{VaspFullLegalName: "Verified"}, VerificationStatus: pb.VerificationState_VERIFIED}, testActor)
+	require.Equal(t, ErrInvalidTransition, err)
+
+	// Create ignores the IDs on the record and assigns new ones
+	id, err := db.Create(vasp, testActor)
+	require.NoError(t, err)
+	require.NotZero(t, id)
+	require.NotEqual(t, uint64(42), id)
+
+	vasp, err = db.Retrieve(id)
+	require.NoError(t, err)
+	require.Equal(t, id, vasp.Id)
+	require.NotZero(t, vasp.VaspEntity.Id)
+	require.NotEqual(t, uint64(42), vasp.VaspEntity.Id)
+	require.NotZero(t, vasp.VaspTRISACertification.Id)
+	require.NotZero(t, vasp.VaspTRISACertification.SubjectName.Id)
+	require.Equal(t, "Conformance Exchange Ltd.", vasp.VaspEntity.VaspFullLegalName)
+	require.Equal(t, vasp.LastUpdated, vasp.FirstListed)
+	require.Equal(t, uint64(1), vasp.Version)
+	_, err = time.Parse(time.RFC3339, vasp.LastUpdated)
+	require.NoError(t, err)
+
+	_, err = db.Retrieve(id + 1000)
+	require.Equal(t, ErrEntityNotFound, err)
+
+	// Every unique index is enforced on its normalized key
+	duplicates := []*pb.Entity{
+		{VaspFullLegalName: "CONFORMANCE EXCHANGE LIMITED"},
+		{VaspFullLegalName: "Duplicate LEI", VaspLEINumber: "5493-001k-jtii-gc8y-1r12"},
+		{VaspFullLegalName: "Duplicate Domain", VaspURL: "conformance.io"},
+	}
+	for _, entity := range duplicates {
+		_, err = db.Create(pb.VASP{VaspEntity: entity}, testActor)
+		require.Equal(t, ErrDuplicateEntity, err, entity.VaspFullLegalName)
+	}
+
+	_, err = db.Create(pb.VASP{
+		VaspEntity:             &pb.Entity{VaspFullLegalName: "Duplicate Certificate"},
+		VaspTRISACertification: &pb.TRISACertification{SubjectName: &pb.Name{CommonName: "TRISA.conformance.io"}},
+	}, testActor)
+	require.Equal(t, ErrDuplicateEntity, err)
+
+	_, err = db.Create(pb.VASP{
+		VaspEntity:             &pb.Entity{VaspFullLegalName: "Duplicate Serial"},
+		VaspTRISACertification: &pb.TRISACertification{SerialNumber: []byte{0x0a, 0xbc}},
+	}, testActor)
+	require.Equal(t, ErrDuplicateEntity, err)
+
+	// Lookups normalize the key in the same manner as the index
+	lookups := map[Index]string{
+		NameIndex:       "conformance exchange limited",
+		LEIIndex:        "5493001kjtiigc8y1r12",
+		DomainIndex:     "WWW.CONFORMANCE.IO",
+		CommonNameIndex: "trisa.conformance.io",
+		SerialIndex:     "0A:BC",
+	}
+	for index, key := range lookups {
+		v, err := db.Lookup(index, key)
+		require.NoError(t, err, index.String())
+		require.Equal(t, id, v.Id, index.String())
+	}
+
+	_, err = db.Lookup(NameIndex, "")
+	require.Equal(t, ErrEntityNotFound, err)
+	_, err = db.Lookup(LEIIndex, "5493001KJTIIGC8Y1R99")
+	require.Equal(t, ErrEntityNotFound, err)
+
+	other, err := db.Create(pb.VASP{VaspEntity: &pb.Entity{VaspFullLegalName: "Other Exchange", VaspCountry: "GB"}}, testActor)
+	require.NoError(t, err)
+	require.True(t, other > id)
+
+	// Update requires an existing, complete record
+	require.Equal(t, ErrIncompleteRecord, db.Update(pb.VASP{VaspEntity: vasp.VaspEntity}, testActor))
+	require.Equal(t, ErrIncompleteRecord, db.Update(pb.VASP{Id: id}, testActor))
+	require.Equal(t, ErrEntityNotFound, db.Update(pb.VASP{Id: id + 1000, VaspEntity: &pb.Entity{VaspFullLegalName: "Missing"}}, testActor))
+
+	// Update respects the verification state machine and the unique indices
+	vasp.VerificationStatus = pb.VerificationState_VERIFIED
+	require.Equal(t, ErrInvalidTransition, db.Update(vasp, testActor))
+
+	vasp.VerificationStatus = pb.VerificationState_SUBMITTED
+	vasp.VaspEntity.VaspFullLegalName = "other exchange"
+	require.Equal(t, ErrDuplicateEntity, db.Update(vasp, testActor))
+
+	// Renaming a VASP frees its old name and assigns IDs to new subrecords
+	vasp.VaspEntity.VaspFullLegalName = "Renamed Exchange"
+	vasp.VaspTRISACertification.IssuerName = &pb.Name{CommonName: "Issuer"}
+	require.NoError(t, db.Update(vasp, testActor))
+
+	vasp, err = db.Lookup(NameIndex, "renamed exchange")
+	require.NoError(t, err)
+	require.Equal(t, id, vasp.Id)
+	require.Equal(t, pb.VerificationState_SUBMITTED, vasp.VerificationStatus)
+	require.NotZero(t, vasp.VaspTRISACertification.IssuerName.Id)
+
+	_, err = db.Lookup(NameIndex, "Conformance Exchange Ltd.")
+	require.Equal(t, ErrEntityNotFound, err)
+
+	// Updates must be made to the current version of the record
+	require.Equal(t, uint64(2), vasp.Version)
+	stale := vasp
+	vasp.VaspEntity = &pb.Entity{VaspFullLegalName: "Renamed Exchange", VaspCountry: "US", VaspURL: "https://renamed.io"}
+	require.NoError(t, db.Update(vasp, testActor))
+	require.Equal(t, ErrConflict, db.Update(stale, testActor))
+
+	vasp, err = db.Retrieve(id)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), vasp.Version)
+	require.Equal(t, "https://renamed.io", vasp.VaspEntity.VaspURL)
+
+	// Only the index keys that were changed by the update are replaced
+	for index, key := range map[Index]string{DomainIndex: "conformance.io", LEIIndex: "5493001KJTIIGC8Y1R12"} {
+		_, err = db.Lookup(index, key)
+		require.Equal(t, ErrEntityNotFound, err, index.String())
+	}
+
+	for index, key := range map[Index]string{DomainIndex: "renamed.io", CommonNameIndex: "trisa.conformance.io", SerialIndex: "0abc"} {
+		v, err := db.Lookup(index, key)
+		require.NoError(t, err, index.String())
+		require.Equal(t, id, v.Id, index.String())
+	}
+
+	vasps, err := db.List()
+	require.NoError(t, err)
+	require.Len(t, vasps, 2)
+	require.Equal(t, id, vasps[0].Id)
+	require.Equal(t, other, vasps[1].Id)
+
+	// Delisted VASPs keep their tombstone fields and their unique keys
+	vasp.VerificationStatus = pb.VerificationState_DELISTED
+	vasp.DelistedOn = "2020-06-01T12:00:00Z"
+	vasp.DelistReason = "ceased operations"
+	vasp.PurgeAfter = "2020-07-01T12:00:00Z"
+	require.NoError(t, db.Update(vasp, testActor))
+
+	vasp, err = db.Lookup(NameIndex, "renamed exchange")
+	require.NoError(t, err)
+	require.Equal(t, pb.VerificationState_DELISTED, vasp.VerificationStatus)
+	require.Equal(t, "2020-06-01T12:00:00Z", vasp.DelistedOn)
+	require.Equal(t, "ceased operations", vasp.DelistReason)
+	require.Equal(t, "2020-07-01T12:00:00Z", vasp.PurgeAfter)
+
+	vasp.VerificationStatus = pb.VerificationState_SUBMITTED
+	require.Equal(t, ErrInvalidTransition, db.Update(vasp, testActor))
+
+	// Destroy is idempotent and frees the unique keys of the record
+	require.NoError(t, db.Destroy(other, testActor))
+	require.NoError(t, db.Destroy(other, testActor))
+	_, err = db.Retrieve(other)
+	require.Equal(t, ErrEntityNotFound, err)
+
+	_, err = db.Lookup(NameIndex, "other exchange")
+	require.Equal(t, ErrEntityNotFound, err)
+
+	other, err = db.Create(pb.VASP{VaspEntity: &pb.Entity{VaspFullLegalName: "Other Exchange", VaspCountry: "GB"}}, testActor)
+	require.NoError(t, err)
+	require.NoError(t, db.Destroy(other, testActor))
+	require.NoError(t, db.Destroy(id, testActor))
+
+	vasps, err = db.List()
+	require.NoError(t, err)
+	require.Len(t, vasps, 0)
+}
+
+// tests that VASPs are listed in ID order, including IDs whose keys are encoded with
+// more than one byte
+func testStoreList(t *testing.T, db Store) {
+	for i := 0; i < 300; i++ {
+		_, err := db.Create(pb.VASP{VaspEntity: &pb.Entity{VaspFullLegalName: fmt.Sprintf("Listed Exchange %d", i)}}, testActor)
+		require.NoError(t, err)
+	}
+
+	vasps, err := db.List()
+	require.NoError(t, err)
+	require.True(t, len(vasps) >= 300)
+	require.True(t, vasps[len(vasps)-1].Id > 256)
+	for i := 1; i < len(vasps); i++ {
+		require.True(t, vasps[i-1].Id < vasps[i].Id, "VASP %d listed before VASP %d", vasps[i-1].Id, vasps[i].Id)
+	}
+
+	// Listing in batches after the last ID of each batch returns the same VASPs
+	var batched []pb.VASP
+	for after := uint64(0); ; {
+		batch, err := db.ListAfter(after, 128)
+		require.NoError(t, err)
+		require.True(t, len(batch) <= 128)
+		if len(batch) == 0 {
+			break
+		}
+		batched = append(batched, batch...)
+		after = batch[len(batch)-1].Id
+	}
+	require.Equal(t, len(vasps), len(batched))
+	for i := range vasps {
+		require.Equal(t, vasps[i].Id, batched[i].Id)
+	}
+
+	rest, err := db.ListAfter(vasps[len(vasps)-3].Id, 0)
+	require.NoError(t, err)
+	require.Len(t, rest, 2)
+}
+
+// tests that search results are matched and ordered identically by every backend
+func testStoreSearch(t *testing.T, db Store) {
+	fixtures := []*pb.Entity{
+		{VaspFullLegalName: "Alpha Exchange", VaspCountry: "US", VaspURL: "https://alpha.io", VaspCategory: "Exchange"},
+		{VaspFullLegalName: "Alpha ATM Network", VaspCountry: "GB", VaspURL: "https://atm.alpha.io", VaspCategory: "ATM"},
+		{VaspFullLegalName: "Beta Custody", VaspCountry: "US", VaspURL: "https://beta.com"},
+	}
+
+	ids := make([]uint64, 0, len(fixtures))
+	for _, entity := range fixtures {
+		id, err := db.Create(pb.VASP{VaspEntity: entity}, testActor)
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	_, err := db.Search(&pb.Query{})
+	require.Equal(t, ErrEmptyQuery, err)
+
+	results, err := db.Search(&pb.Query{Name: []string{"alhpa exchange"}})
+	require.NoError(t, err)
+	require.True(t, len(results) >= 1)
+	require.Equal(t, ids[0], results[0].VASP.Id)
+
+	results, err = db.Search(&pb.Query{Country: []string{"us"}})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	results, err = db.Search(&pb.Query{Name: []string{"alpha"}, Country: []string{"gb"}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, ids[1], results[0].VASP.Id)
+
+	results, err = db.Search(&pb.Query{Domain: []string{"alpha.io"}})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	results, err = db.Search(&pb.Query{Operator: pb.Query_OR, Country: []string{"gb"}, Name: []string{"beta custody"}})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	results, err = db.Search(&pb.Query{Category: []string{"atm"}, Country: []string{"us"}})
+	require.NoError(t, err)
+	require.Len(t, results, 0)
+
+	for _, id := range ids {
+		require.NoError(t, db.Destroy(id, testActor))
+	}
+}
+
+// tests the certificate request queue and the email outbox
+func testStoreQueues(t *testing.T, db Store) {
+	vasp := pb.VASP{VaspEntity: &pb.Entity{VaspFullLegalName: "Queued Exchange"}}
+
+	_, err := db.CreateCertReq(pb.CertificateRequest{})
+	require.Equal(t, ErrIncompleteRecord, err)
+	_, err = db.CreateEmail(pb.Email{})
+	require.Equal(t, ErrIncompleteRecord, err)
+
+	// CreateWithEmail sets the VASP of the email and queues it with the record
+	id, err := db.CreateWithEmail(vasp, pb.Email{Type: pb.EmailType_VERIFY_CONTACT}, testActor)
+	require.NoError(t, err)
+
+	vasp, err = db.Retrieve(id)
+	require.NoError(t, err)
+
+	emails, err := db.ListEmails()
+	require.NoError(t, err)
+	require.Len(t, emails, 1)
+	require.Equal(t, id, emails[0].Vasp)
+	require.Equal(t, pb.EmailType_VERIFY_CONTACT, emails[0].Type)
+	require.NotEmpty(t, emails[0].Created)
+
+	// Queued records share the ID sequence of the VASP records
+	eid, err := db.CreateEmail(pb.Email{Vasp: id, Type: pb.EmailType_REVIEW_REQUEST})
+	require.NoError(t, err)
+	require.True(t, eid > vasp.VaspEntity.Id)
+
+	rid, err := db.CreateCertReq(pb.CertificateRequest{Vasp: id, CommonName: "trisa.queued.io"})
+	require.NoError(t, err)
+	require.True(t, rid > eid)
+
+	emails, err = db.ListEmails()
+	require.NoError(t, err)
+	require.Len(t, emails, 2)
+	require.Equal(t, eid, emails[1].Id)
+
+	emails[1].Attempts++
+	require.NoError(t, db.UpdateEmail(emails[1]))
+	emails, err = db.ListEmails()
+	require.NoError(t, err)
+	require.Equal(t, int32(1), emails[1].Attempts)
+
+	reqs, err := db.ListCertReqs()
+	require.NoError(t, err)
+	require.Len(t, reqs, 1)
+	require.Equal(t, rid, reqs[0].Id)
+	require.Equal(t, "trisa.queued.io", reqs[0].CommonName)
+
+	reqs[0].BatchId = 7
+	reqs[0].LastError = "batch failed"
+	require.NoError(t, db.UpdateCertReq(reqs[0]))
+	reqs, err = db.ListCertReqs()
+	require.NoError(t, err)
+	require.Equal(t, int64(7), reqs[0].BatchId)
+	require.Equal(t, "batch failed", reqs[0].LastError)
+
+	// Updates require an ID of a queued record; deletes are idempotent
+	require.Equal(t, ErrIncompleteRecord, db.UpdateCertReq(pb.CertificateRequest{Vasp: id}))
+	require.Equal(t, ErrIncompleteRecord, db.UpdateEmail(pb.Email{Vasp: id}))
+
+	for _, e := range emails {
+		require.NoError(t, db.DeleteEmail(e.Id))
+		require.NoError(t, db.DeleteEmail(e.Id))
+	}
+	require.NoError(t, db.DeleteCertReq(rid))
+	require.NoError(t, db.DeleteCertReq(rid))
+
+	require.Equal(t, ErrEntityNotFound, db.UpdateCertReq(reqs[0]))
+	require.Equal(t, ErrEntityNotFound, db.UpdateEmail(emails[0]))
+
+	emails, err = db.ListEmails()
+	require.NoError(t, err)
+	require.Len(t, emails, 0)
+	reqs, err = db.ListCertReqs()
+	require.NoError(t, err)
+	require.Len(t, reqs, 0)
+
+	require.NoError(t, db.Destroy(id, testActor))
+}
+
+// tests that every change to a VASP appends an entry to its audit history
+func testStoreHistory(t *testing.T, db Store) {
+	entries, err := db.History(4242)
+	require.NoError(t, err)
+	require.Len(t, entries, 0)
+
+	vasp := pb.VASP{
+		VaspEntity: &pb.Entity{VaspFullLegalName: "Audited Exchange", VaspCountry: "US"},
+	}
+
+	id, err := db.Create(vasp, testActor)
+	require.NoError(t, err)
+
+	// Failed changes are not recorded
+	_, err = db.Create(vasp, testActor)
+	require.Equal(t, ErrDuplicateEntity, err)
+
+	vasp, err = db.Retrieve(id)
+	require.NoError(t, err)
+
+	vasp.VaspEntity.VaspCountry = "GB"
+	vasp.VaspEntity.VaspURL = "https://audited.io"
+	vasp.VerificationStatus = pb.VerificationState_SUBMITTED
+	vasp.VerificationToken = "secret"
+	require.NoError(t, db.Update(vasp, Actor{Name: "admin", RPC: "/pb.TRISAAdmin/UpdateVASP"}))
+
+	vasp.Version++
+	vasp.VerificationStatus = pb.VerificationState_VERIFIED
+	require.Equal(t, ErrInvalidTransition, db.Update(vasp, testActor))
+
+	require.NoError(t, db.Destroy(id, Actor{Name: "admin", RPC: "/pb.TRISAAdmin/DeleteVASP"}))
+	require.NoError(t, db.Destroy(id, testActor))
+
+	// The history is retained after the VASP is destroyed
+	entries, err = db.History(id)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	for i, action := range []pb.AuditAction{pb.AuditAction_CREATED, pb.AuditAction_UPDATED, pb.AuditAction_DELETED} {
+		require.Equal(t, action, entries[i].Action)
+		require.Equal(t, id, entries[i].Vasp)
+		require.NotEmpty(t, entries[i].Timestamp)
+		if i > 0 {
+			require.True(t, entries[i].Id > entries[i-1].Id)
+		}
+	}
+
+	created := entries[0]
+	require.Equal(t, testActor.Name, created.Actor)
+	require.Equal(t, testActor.Address, created.Address)
+	require.Equal(t, testActor.RPC, created.Rpc)
+	require.Contains(t, created.Changes, &pb.FieldChange{Field: "vaspEntity.vaspFullLegalName", After: `"Audited Exchange"`})
+
+	// Only the changed fields are recorded and secrets are redacted
+	updated := entries[1]
+	require.Equal(t, "admin", updated.Actor)
+	require.Equal(t, "/pb.TRISAAdmin/UpdateVASP", updated.Rpc)
+	require.Equal(t, []*pb.FieldChange{
+		{Field: "vaspEntity.vaspCountry", Before: `"US"`, After: `"GB"`},
+		{Field: "vaspEntity.vaspURL", After: `"https://audited.io"`},
+		{Field: "verificationStatus", Before: "", After: `"SUBMITTED"`},
+		{Field: "verificationToken", After: `"[redacted]"`},
+	}, updated.Changes)
+
+	deleted := entries[2]
+	require.Equal(t, "/pb.TRISAAdmin/DeleteVASP", deleted.Rpc)
+	require.Contains(t, deleted.Changes, &pb.FieldChange{Field: "vaspEntity.vaspCountry", Before: `"GB"`})
+}
@@ -0,0 +1,88 @@
+package trisads
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Errors that may occur when configuring TLS.
+var (
+	ErrIncompleteTLS = errors.New("both a tls certificate and key are required to serve tls")
+	ErrNoClientCAs   = errors.New("client certificate authorities are required for mutual tls")
+	ErrNoTLS         = errors.New("a tls certificate and key are required for mutual tls")
+)
+
+// serverCredentials returns the gRPC server options to serve TLS and optionally verify
+// client certificates (mTLS) so that VASPs holding a TRISA certificate can authenticate.
+// If no certificate is configured the server listens in plaintext, which is only
+// suitable for development or when TLS is terminated by a proxy.
+func (s *Server) serverCredentials() (opts []grpc.ServerOption, err error) {
+	clientAuth := tls.ClientAuthType(s.conf.TLSClientAuth)
+	if s.conf.TLSCertFile == "" && s.conf.TLSKeyFile == "" {
+		if clientAuth != tls.NoClientCert {
+			return nil, ErrNoTLS
+		}
+		log.Warn().Msg("no tls certificate configured, serving in plaintext")
+		return nil, nil
+	}
+
+	var conf *tls.Config
+	if conf, err = serverTLSConfig(s.conf.TLSCertFile, s.conf.TLSKeyFile, s.conf.TLSClientCAs, clientAuth); err != nil {
+		return nil, err
+	}
+
+	log.Info().Bool("mtls", clientAuth != tls.NoClientCert).Msg("serving tls")
+	return []grpc.ServerOption{grpc.Creds(credentials.NewTLS(conf))}, nil
+}
+
+// creates the server tls configuration from the certificate and key files, loading the
+// client certificate authorities from the PEM encoded pool file if mTLS is enabled.
+func serverTLSConfig(certFile, keyFile, poolFile string, clientAuth tls.ClientAuthType) (_ *tls.Config, err error) {
+	if certFile == "" || keyFile == "" {
+		return nil, ErrIncompleteTLS
+	}
+
+	var cert tls.Certificate
+	if cert, err = tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+		return nil, fmt.Errorf("could not load tls certificate: %s", err)
+	}
+
+	conf := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		ClientAuth:   clientAuth,
+	}
+
+	if clientAuth != tls.NoClientCert {
+		if poolFile == "" {
+			return nil, ErrNoClientCAs
+		}
+
+		if conf.ClientCAs, err = loadCertPool(poolFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return conf, nil
+}
+
+// loads a pool of certificate authorities from a PEM encoded file.
+func loadCertPool(path string) (_ *x509.CertPool, err error) {
+	var data []byte
+	if data, err = ioutil.ReadFile(path); err != nil {
+		return nil, fmt.Errorf("could not read certificate pool: %s", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
@@ -2,18 +2,27 @@ package trisads
 
 import (
 	"context"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	"os/signal"
+	"sync"
+	"time"
 
 	"github.com/bbengfort/trisads/pb"
 	"github.com/bbengfort/trisads/sectigo"
 	"github.com/bbengfort/trisads/store"
+	"github.com/bbengfort/trisads/validate"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/sendgrid/sendgrid-go"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func init() {
@@ -35,11 +44,16 @@ func New(conf *Settings) (s *Server, err error) {
 	zerolog.SetGlobalLevel(zerolog.Level(conf.LogLevel))
 
 	// Create the server and open the connection to the database
-	s = &Server{conf: conf}
+	s = &Server{conf: conf, stop: make(chan struct{}), outbox: make(chan struct{}, 1)}
 	if s.db, err = store.Open(conf.DatabaseDSN); err != nil {
 		return nil, err
 	}
 
+	// Create the codec that signs the page tokens and cursors returned to clients
+	if s.cursors, err = newCursorCodec(conf.CursorSecret, conf.CursorTTL); err != nil {
+		return nil, err
+	}
+
 	// Create the Sectigo API client
 	if s.certs, err = sectigo.New(conf.SectigoUsername, conf.SectigoPassword); err != nil {
 		return nil, err
@@ -48,24 +62,84 @@ func New(conf *Settings) (s *Server, err error) {
 	// Create the SendGrid API client
 	s.email = sendgrid.NewSendClient(conf.SendGridAPIKey)
 
+	// Load the certificate authorities that issue TRISA certificates, if configured
+	if conf.TLSClientCAs != "" {
+		if s.trust, err = loadCertPool(conf.TLSClientCAs); err != nil {
+			return nil, err
+		}
+	}
+
 	// Configuration complete!
 	return s, nil
 }
 
+// Open creates a server that only opens the directory store, without the API clients
+// that are required to serve requests, so that CLI commands can run the steps of the
+// verification workflow that only change the store (e.g. approving VASPs) while the
+// directory service is not running.
+func Open(conf *Settings) (s *Server, err error) {
+	if conf == nil {
+		if conf, err = Config(); err != nil {
+			return nil, err
+		}
+	}
+
+	s = &Server{conf: conf, stop: make(chan struct{}), outbox: make(chan struct{}, 1)}
+	if s.db, err = store.Open(conf.DatabaseDSN); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
 // Server implements the GRPC TRISADirectoryService.
 type Server struct {
-	db    store.Store
-	srv   *grpc.Server
-	conf  *Settings
-	certs *sectigo.Sectigo
-	email *sendgrid.Client
+	db      store.Store
+	srv     *grpc.Server
+	conf    *Settings
+	certs   *sectigo.Sectigo
+	email   *sendgrid.Client
+	trust   *x509.CertPool
+	cursors cursorCodec
+	stop    chan struct{}
+	outbox  chan struct{}
+	wg      sync.WaitGroup
 }
 
 // Serve GRPC requests on the specified address.
 func (s *Server) Serve() (err error) {
-	// Initialize the gRPC server
-	s.srv = grpc.NewServer()
+	// Initialize the gRPC server with TLS credentials if configured
+	var opts []grpc.ServerOption
+	if opts, err = s.serverCredentials(); err != nil {
+		return err
+	}
+
+	// Admin requests must be authenticated with the admin token
+	opts = append(opts, grpc.UnaryInterceptor(s.authenticate))
+
+	s.srv = grpc.NewServer(opts...)
 	pb.RegisterTRISADirectoryServer(s.srv, s)
+	pb.RegisterTRISAAdminServer(s.srv, s)
+
+	// Start processing the certificate request queue and email outbox and compacting the
+	// delisted VASPs in the background, unless they are processed by another replica that
+	// shares the directory store
+	if s.conf.Workers {
+		s.wg.Add(3)
+		go func() {
+			defer s.wg.Done()
+			s.CertManager(s.stop)
+		}()
+
+		go func() {
+			defer s.wg.Done()
+			s.EmailManager(s.stop)
+		}()
+
+		go func() {
+			defer s.wg.Done()
+			s.Compactor(s.stop)
+		}()
+	}
 
 	// Catch OS signals for graceful shutdowns
 	quit := make(chan os.Signal, 1)
@@ -93,7 +167,14 @@ func (s *Server) Serve() (err error) {
 // Shutdown the TRISA Directory Service gracefully
 func (s *Server) Shutdown() (err error) {
 	log.Info().Msg("gracefully shutting down")
-	s.srv.GracefulStop()
+	if s.srv != nil {
+		s.srv.GracefulStop()
+	}
+
+	// Wait for background routines to finish before closing the database
+	close(s.stop)
+	s.wg.Wait()
+
 	if err = s.db.Close(); err != nil {
 		log.Error().Err(err)
 		return err
@@ -106,115 +187,370 @@ func (s *Server) Shutdown() (err error) {
 // status of verification can be obtained by using the lookup RPC call.
 func (s *Server) Register(ctx context.Context, in *pb.RegisterRequest) (out *pb.RegisterReply, err error) {
 	out = &pb.RegisterReply{}
-	vasp := pb.VASP{VaspEntity: in.Entity}
 
-	if out.Id, err = s.db.Create(vasp); err != nil {
-		log.Warn().Err(err).Msg("could not register VASP")
-		out.Error = &pb.Error{
-			Code:    400,
-			Message: err.Error(),
-		}
+	// Validate the entity before it is stored, VASPs that request verification must also
+	// provide the contact email and url required to issue certificates.
+	validate.Normalize(in.Entity)
+	if in.Verify {
+		err = validate.Verifiable(in.Entity)
 	} else {
-		log.Info().Str("name", in.Entity.VaspFullLegalName).Msg("registered VASP")
+		err = validate.Entity(in.Entity)
+	}
+
+	if err != nil {
+		log.Warn().Err(err).Msg("invalid registration")
+		return out, s.fail(&out.Error, err, fieldViolations("entity", err.(validate.Violations)))
 	}
 
-	// TODO: if verify is true: send verification request
-	if err = s.SendVerificationEmail(vasp); err != nil {
-		log.Error().Err(err).Msg("could not send verification email")
-		out.Error = &pb.Error{
-			Code:    500,
-			Message: err.Error(),
+	// VASPs that do not request verification are simply listed in the directory
+	vasp := pb.VASP{VaspEntity: in.Entity}
+	if !in.Verify {
+		if out.Id, err = s.db.Create(vasp, actor(ctx)); err != nil {
+			return out, s.registerError(&out.Error, in, err)
 		}
-	} else {
-		log.Info().Msg("verification email sent")
+		log.Info().Str("name", in.Entity.VaspFullLegalName).Uint64("id", out.Id).Msg("registered VASP")
+		return out, nil
+	}
+
+	// Create the token the VASP contact uses to verify their email address
+	vasp.VerificationStatus = pb.VerificationState_SUBMITTED
+	if vasp.VerificationToken, err = createToken(); err != nil {
+		log.Error().Err(err).Msg("could not create verification token")
+		return out, s.fail(&out.Error, err)
+	}
+
+	// Create the password the issued certificates will be encrypted with
+	vasp.Pkcs12Password = sectigo.RandomPassword(pkcs12PasswordLength)
+
+	// Store the VASP and queue the verification email together so that no email is sent
+	// if the VASP could not be stored, and the email is retried if it cannot be delivered.
+	if out.Id, err = s.db.CreateWithEmail(vasp, pb.Email{Type: pb.EmailType_VERIFY_CONTACT}, actor(ctx)); err != nil {
+		return out, s.registerError(&out.Error, in, err)
 	}
+	log.Info().Str("name", in.Entity.VaspFullLegalName).Uint64("id", out.Id).Msg("registered VASP")
+	s.wakeOutbox()
 
+	// The password is only returned once, it is removed when certificates are issued
+	out.Pkcs12Password = vasp.Pkcs12Password
 	return out, nil
 }
 
-// Lookup a VASP entity by name or ID to get full details including the TRISA certification
-// if it exists and the entity has been verified.
+// returns the error for a VASP that could not be stored during registration.
+func (s *Server) registerError(dst **pb.Error, in *pb.RegisterRequest, err error) error {
+	log.Warn().Err(err).Msg("could not register VASP")
+	switch {
+	case errors.Is(err, store.ErrDuplicateEntity):
+		return s.fail(dst, err, vaspResource(0, in.Entity.VaspFullLegalName))
+	case errors.Is(err, store.ErrIncompleteRecord):
+		return s.fail(dst, err, badRequest("entity", err.Error()))
+	default:
+		return s.fail(dst, err)
+	}
+}
+
+// VerifyEmail checks the token that was sent to the VASP contact email address during
+// registration and if it matches, moves the VASP into the pending review state and
+// notifies the TRISA admins. Tokens can only be used once; after verification the token
+// is removed from the VASP record.
+func (s *Server) VerifyEmail(ctx context.Context, in *pb.VerifyEmailRequest) (out *pb.VerifyEmailReply, err error) {
+	var vasp pb.VASP
+	out = &pb.VerifyEmailReply{Id: in.Id}
+
+	if vasp, err = s.db.Retrieve(in.Id); err != nil {
+		log.Warn().Err(err).Uint64("id", in.Id).Msg("could not retrieve VASP to verify")
+		return out, s.fail(&out.Error, err, vaspResource(in.Id, ""))
+	}
+
+	if vasp.VerificationStatus != pb.VerificationState_EMAILED {
+		log.Warn().Uint64("id", in.Id).Str("status", vasp.VerificationStatus.String()).Msg("cannot verify email")
+		err = status.Errorf(codes.FailedPrecondition, "cannot verify email for VASP in %s state", vasp.VerificationStatus)
+		return out, s.fail(&out.Error, err)
+	}
+
+	if in.Token == "" || subtle.ConstantTimeCompare([]byte(in.Token), []byte(vasp.VerificationToken)) != 1 {
+		log.Warn().Uint64("id", in.Id).Msg("invalid verification token")
+		return out, s.fail(&out.Error, ErrInvalidToken, badRequest("token", ErrInvalidToken.Error()))
+	}
+
+	vasp.VerificationStatus = pb.VerificationState_PENDING_REVIEW
+	vasp.VerificationToken = ""
+	if err = s.db.Update(vasp, actor(ctx)); err != nil {
+		log.Error().Err(err).Uint64("id", in.Id).Msg("could not update VASP verification")
+		return out, s.fail(&out.Error, err, vaspResource(in.Id, ""))
+	}
+	log.Info().Uint64("id", vasp.Id).Msg("VASP email verified")
+
+	// Failing to notify the admins does not affect the email verification
+	if err = s.Notify(pb.Email{Vasp: vasp.Id, Type: pb.EmailType_REVIEW_REQUEST}); err != nil {
+		log.Error().Err(err).Msg("could not queue review request email")
+	}
+	return out, nil
+}
+
+// Lookup a VASP entity by ID or by one of its unique keys (name, LEI number, web domain
+// or certificate common name) to get full details including the TRISA certification if
+// it exists and the entity has been verified. Clients should check the verification
+// status in the reply before trusting the counterparty.
 func (s *Server) Lookup(ctx context.Context, in *pb.LookupRequest) (out *pb.LookupReply, err error) {
 	var vasp pb.VASP
 	out = &pb.LookupReply{}
 
-	if in.Id > 0 {
-		if vasp, err = s.db.Retrieve(in.Id); err != nil {
-			out.Error = &pb.Error{
-				Code:    404,
-				Message: err.Error(),
+	var index store.Index
+	var key string
+	switch q := in.Query.(type) {
+	case *pb.LookupRequest_Id:
+		if vasp, err = s.db.Retrieve(q.Id); err != nil {
+			// VASPs that were delisted and then purged are still reported as delisted
+			if t, ok := s.purged(q.Id); ok && errors.Is(err, store.ErrEntityNotFound) {
+				log.Info().Uint64("id", q.Id).Msg("purged VASP lookup")
+				return &pb.LookupReply{Vasp: t, VerificationStatus: t.VerificationStatus, Delisted: true}, nil
 			}
+			log.Warn().Err(err).Uint64("id", q.Id).Msg("could not lookup VASP")
+			return out, s.fail(&out.Error, err, vaspResource(q.Id, ""))
 		}
+	case *pb.LookupRequest_Name:
+		index, key = store.NameIndex, q.Name
+	case *pb.LookupRequest_Lei:
+		index, key = store.LEIIndex, q.Lei
+	case *pb.LookupRequest_Domain:
+		index, key = store.DomainIndex, q.Domain
+	case *pb.LookupRequest_CommonName:
+		index, key = store.CommonNameIndex, q.CommonName
+	default:
+		err = status.Error(codes.InvalidArgument, "no lookup query provided")
+		return out, s.fail(&out.Error, err, badRequest("query", "specify the id, name, lei, domain or common name to lookup"))
+	}
 
-	} else if in.Name != "" {
-		var vasps []pb.VASP
-		if vasps, err = s.db.Search(map[string]interface{}{"name": in.Name}); err != nil {
-			out.Error = &pb.Error{
-				Code:    404,
-				Message: err.Error(),
-			}
+	if index != 0 {
+		if vasp, err = s.db.Lookup(index, key); err != nil {
+			log.Warn().Err(err).Str("index", index.String()).Str("key", key).Msg("could not lookup VASP")
+			return out, s.fail(&out.Error, err, vaspResource(0, key))
 		}
+	}
 
-		if len(vasps) == 1 {
-			vasp = vasps[0]
-		} else {
-			out.Error = &pb.Error{
-				Code:    404,
-				Message: "not found",
-			}
-		}
-	} else {
-		out.Error = &pb.Error{
-			Code:    400,
-			Message: "no lookup query provided",
-		}
+	// Delisted VASPs are distinguished from VASPs that were never listed by their tombstone
+	if vasp.VerificationStatus == pb.VerificationState_DELISTED {
+		out.Vasp = tombstone(vasp)
+		out.VerificationStatus = vasp.VerificationStatus
+		out.Delisted = true
+		log.Info().Uint64("id", vasp.Id).Msg("delisted VASP lookup")
 		return out, nil
 	}
 
-	if out.Error == nil {
-		out.Vasp = &vasp
-		log.Info().Uint64("id", vasp.Id).Msg("VASP lookup succeeded")
-	} else {
-		log.Warn().Err(out.Error).Msg("could not lookup VASP")
+	redact(&vasp)
+	out.Vasp = &vasp
+	out.VerificationStatus = vasp.VerificationStatus
+	log.Info().Uint64("id", vasp.Id).Msg("VASP lookup succeeded")
+	return out, nil
+}
+
+// VerifyCertificate looks up the VASP that was issued a TRISA certificate by the serial
+// number of the certificate, or by the certificate itself in PEM or DER format, so that
+// a counterparty can check that a certificate presented to it belongs to a verified VASP
+// and has not been revoked. If a certificate is presented, its public key must match the
+// issued certificate and its chain must be trusted by the directory service.
+func (s *Server) VerifyCertificate(ctx context.Context, in *pb.VerifyCertificateRequest) (out *pb.VerifyCertificateReply, err error) {
+	out = &pb.VerifyCertificateReply{}
+
+	var cert *x509.Certificate
+	var intermediates *x509.CertPool
+	var serial string
+	switch q := in.Query.(type) {
+	case *pb.VerifyCertificateRequest_Certificate:
+		if cert, intermediates, err = parseCertificate(q.Certificate); err != nil {
+			log.Warn().Err(err).Msg("could not parse certificate to verify")
+			err = status.Error(codes.InvalidArgument, err.Error())
+			return out, s.fail(&out.Error, err, badRequest("certificate", "specify a PEM or DER encoded x509 certificate"))
+		}
+		serial = hex.EncodeToString(cert.SerialNumber.Bytes())
+	case *pb.VerifyCertificateRequest_SerialNumber:
+		serial = q.SerialNumber
+	}
+
+	if store.SerialIndex.Normalize(serial) == "" {
+		err = status.Error(codes.InvalidArgument, "no certificate or serial number provided")
+		return out, s.fail(&out.Error, err, badRequest("query", "specify the certificate or its hex serial number"))
 	}
+
+	var vasp pb.VASP
+	if vasp, err = s.db.Lookup(store.SerialIndex, serial); err != nil {
+		log.Warn().Err(err).Str("serial", serial).Msg("could not lookup certificate")
+		return out, s.fail(&out.Error, err, vaspResource(0, serial))
+	}
+
+	issued := vasp.VaspTRISACertification
+	redact(&vasp)
+	out.Vasp = &vasp
+	if vasp.VerificationStatus == pb.VerificationState_DELISTED {
+		out.Vasp = tombstone(vasp)
+	}
+	out.VerificationStatus = vasp.VerificationStatus
+	out.Revoked = issued.Revoked
+	out.NotValidBefore = issued.NotValidBefore
+	out.NotValidAfter = issued.NotValidAfter
+
+	out.Reason = s.verifyCertificate(&vasp, issued, cert, intermediates, time.Now())
+	out.Valid = out.Reason == ""
+	log.Info().Uint64("id", vasp.Id).Str("serial", serial).Bool("valid", out.Valid).Msg("certificate verified")
 	return out, nil
 }
 
-// Search for VASP entity records by name or by country in order to perform more detailed
-// Lookup requests. The search process is purposefully simplistic at the moment.
+// Search for VASP entity records by name and country or with a structured query in order
+// to perform more detailed Lookup requests. Names do not have to be exact, VASPs whose
+// names start with, contain or approximately match the query are returned ordered by
+// their relevance score. VASPs must match all of the criteria in the request.
 func (s *Server) Search(ctx context.Context, in *pb.SearchRequest) (out *pb.SearchReply, err error) {
 	out = &pb.SearchReply{}
-	query := make(map[string]interface{})
-	query["name"] = in.Name
-	query["country"] = in.Country
+	query := &pb.Query{Name: in.Name, Country: in.Country}
+	if in.Query != nil {
+		query.Queries = []*pb.Query{in.Query}
+	}
+	normalizeCountries(query)
 
-	var vasps []pb.VASP
-	if vasps, err = s.db.Search(query); err != nil {
-		out.Error = &pb.Error{
-			Code:    400,
-			Message: err.Error(),
+	entry := log.With().
+		Strs("name", in.Name).
+		Strs("country", in.Country).
+		Str("query", in.Query.String()).
+		Logger()
+
+	var results []store.SearchResult
+	if results, err = s.db.Search(query); err != nil {
+		entry.Warn().Err(err).Msg("unsuccessful search")
+		if errors.Is(err, store.ErrEmptyQuery) {
+			return out, s.fail(&out.Error, err, badRequest("query", "specify at least one search condition"))
+		}
+		return out, s.fail(&out.Error, err)
+	}
+
+	// Delisted VASPs are not returned by searches, only by Lookup
+	listed := results[:0]
+	for _, result := range results {
+		if result.VASP.VerificationStatus != pb.VerificationState_DELISTED {
+			listed = append(listed, result)
 		}
 	}
+	results = listed
+
+	total := len(results)
+	if results, out.NextPageToken, err = s.cursors.paginate(results, in.PageSize, in.PageToken); err != nil {
+		entry.Warn().Err(err).Msg("unsuccessful search")
+		return out, s.fail(&out.Error, err, badRequest("pageToken", err.Error()))
+	}
 
-	out.Vasps = make([]*pb.VASP, len(vasps))
-	for i := 0; i < len(vasps); i++ {
+	out.Vasps = make([]*pb.VASP, len(results))
+	out.Scores = make([]float64, len(results))
+	for i := 0; i < len(results); i++ {
 		// avoid pointer errors from range
-		out.Vasps[i] = &vasps[i]
+		out.Vasps[i] = &results[i].VASP
+		out.Scores[i] = results[i].Score
 
 		// return only entities, remove certificate info until lookup
 		out.Vasps[i].VaspTRISACertification = nil
+		redact(out.Vasps[i])
 	}
 
-	entry := log.With().
-		Strs("name", in.Name).
-		Strs("country", in.Country).
-		Int("results", len(out.Vasps)).
-		Logger()
+	entry.Info().Int("results", len(out.Vasps)).Int("total", total).Msg("search succeeded")
+	return out, nil
+}
+
+// List streams every VASP in the directory in a stable order, e.g. to mirror the
+// directory into another system. Each VASP is sent with a cursor that can be used to
+// resume the listing after it; the VASPs are not read from a snapshot, so a VASP whose
+// name or timestamp changes while the listing is resumed may be skipped or repeated.
+// Secrets are removed from the VASPs but certificates are included. Delisted VASPs are
+// sent as tombstones so that mirrors can remove them. Listings in ascending ID order
+// seek to the cursor in the store, other orders are read in batches keeping only the
+// VASPs of the listing. Errors are always returned as gRPC status errors.
+func (s *Server) List(in *pb.ListRequest, stream pb.TRISADirectory_ListServer) (err error) {
+	var start *cursor
+	if in.Cursor != "" {
+		var c cursor
+		if c, err = s.cursors.decode(in.Cursor); err != nil {
+			return statusError(err, badRequest("cursor", err.Error()))
+		}
 
-	if out.Error != nil {
-		entry.Warn().Err(out.Error).Msg("unsuccessful search")
+		if c.Order != in.Order || c.Desc != in.Descending {
+			return statusError(ErrInvalidCursor, badRequest("cursor", "cursor does not match the listing order"))
+		}
+		start = &c
+	}
+
+	var sent uint32
+	send := func(vasp *pb.VASP, c cursor) (err error) {
+		if vasp.VerificationStatus == pb.VerificationState_DELISTED {
+			vasp = tombstone(*vasp)
+		}
+
+		redact(vasp)
+		if err = stream.Send(&pb.ListReply{Vasp: vasp, Cursor: s.cursors.encode(c)}); err != nil {
+			log.Warn().Err(err).Uint32("sent", sent).Msg("could not stream VASP listing")
+			return err
+		}
+		sent++
+		return nil
+	}
+
+	if in.Order == pb.ListOrder_ORDER_BY_ID && !in.Descending {
+		var after uint64
+		if start != nil {
+			after = start.ID
+		}
+
+		for in.Limit == 0 || sent < in.Limit {
+			size := listBatchSize
+			if in.Limit > 0 && in.Limit-sent < uint32(size) {
+				size = int(in.Limit - sent)
+			}
+
+			var batch []pb.VASP
+			if batch, err = s.db.ListAfter(after, size); err != nil {
+				log.Error().Err(err).Msg("could not list VASPs")
+				return statusError(err)
+			}
+
+			for i := range batch {
+				if err = send(&batch[i], listCursor(&batch[i], in.Order, in.Descending)); err != nil {
+					return err
+				}
+			}
+
+			if len(batch) < size {
+				break
+			}
+			after = batch[len(batch)-1].Id
+		}
 	} else {
-		entry.Info().Msg("search succeeded")
+		var page []listed
+		if page, err = listAfter(s.db, start, in.Order, in.Descending, in.Limit); err != nil {
+			log.Error().Err(err).Msg("could not list VASPs")
+			return statusError(err)
+		}
+
+		for i := range page {
+			if err = send(&page[i].vasp, page[i].cursor); err != nil {
+				return err
+			}
+		}
 	}
-	return out, nil
+
+	log.Info().Uint32("sent", sent).Str("order", in.Order.String()).Msg("VASP listing succeeded")
+	return nil
+}
+
+// converts the countries of the query and its nested queries to the alpha-2 codes that
+// registered VASPs are stored with, so that e.g. "USA" matches VASPs in "US".
+func normalizeCountries(query *pb.Query) {
+	for i, country := range query.Country {
+		query.Country[i] = validate.NormalizeCountry(country)
+	}
+
+	for _, q := range query.Queries {
+		normalizeCountries(q)
+	}
+}
+
+// removes secrets from the VASP record before it is returned to clients or emailed.
+func redact(vasp *pb.VASP) {
+	vasp.VerificationToken = ""
+	vasp.Pkcs12Password = ""
 }
@@ -0,0 +1,170 @@
+package trisads
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/bbengfort/trisads/store"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Actor of the changes made to VASP records by the tests.
+var testActor = store.Actor{Name: "test", RPC: "Test"}
+
+// creates a server backed by an in-memory store without the external API clients.
+func testServer(t *testing.T) *Server {
+	db, err := store.Open("memory://")
+	require.NoError(t, err)
+
+	conf := &Settings{
+		ServiceEmail: "admin@vaspdirectory.net",
+		AdminEmail:   "admin@trisa.io",
+		VerifyURL:    "https://vaspdirectory.net/verify",
+	}
+	cursors := cursorCodec{key: []byte("testing"), ttl: time.Hour}
+	return &Server{db: db, conf: conf, cursors: cursors, stop: make(chan struct{}), outbox: make(chan struct{}, 1)}
+}
+
+// returns the content of the email with the specified MIME type.
+func emailContent(message *mail.SGMailV3, mime string) string {
+	for _, content := range message.Content {
+		if content.Type == mime {
+			return content.Value
+		}
+	}
+	return ""
+}
+
+// racingStore runs the race function once, immediately after the next VASP is
+// retrieved, to simulate an update made concurrently with the request being tested.
+type racingStore struct {
+	store.Store
+	race func()
+}
+
+func (r *racingStore) Retrieve(id uint64) (pb.VASP, error) {
+	vasp, err := r.Store.Retrieve(id)
+	if r.race != nil {
+		race := r.race
+		r.race = nil
+		race()
+	}
+	return vasp, err
+}
+
+// brokenStore fails to create VASPs with an error that is not a store error.
+type brokenStore struct {
+	store.Store
+}
+
+func (brokenStore) Create(pb.VASP, store.Actor) (uint64, error) {
+	return 0, errors.New("disk full")
+}
+
+func (brokenStore) CreateWithEmail(pb.VASP, pb.Email, store.Actor) (uint64, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestRegisterErrors(t *testing.T) {
+	s := testServer(t)
+	ctx := context.Background()
+	entity := &pb.Entity{VaspFullLegalName: "Duplicate Exchange", VaspContactEmail: "admin@duplicate.io", VaspURL: "https://duplicate.io"}
+
+	_, err := s.Register(ctx, &pb.RegisterRequest{Entity: entity})
+	require.NoError(t, err)
+
+	_, err = s.Register(ctx, &pb.RegisterRequest{Entity: entity})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	// Unknown store errors are internal errors rather than invalid requests
+	s.db = brokenStore{s.db}
+	for _, verify := range []bool{false, true} {
+		_, err := s.Register(ctx, &pb.RegisterRequest{Entity: &pb.Entity{VaspFullLegalName: "Broken Exchange", VaspContactEmail: "admin@broken.io", VaspURL: "https://broken.io"}, Verify: verify})
+		require.Equal(t, codes.Internal, status.Code(err))
+	}
+}
+
+func TestVerifyEmailConflict(t *testing.T) {
+	s := testServer(t)
+	ctx := context.Background()
+
+	rep, err := s.Register(ctx, &pb.RegisterRequest{
+		Entity: &pb.Entity{
+			VaspFullLegalName: "Racing Exchange",
+			VaspContactEmail:  "admin@racing.io",
+			VaspURL:           "https://racing.io",
+		},
+		Verify: true,
+	})
+	require.NoError(t, err)
+
+	vasp, err := s.db.Retrieve(rep.Id)
+	require.NoError(t, err)
+	vasp.VerificationStatus = pb.VerificationState_EMAILED
+	require.NoError(t, s.db.Update(vasp, testActor))
+
+	vasp, err = s.db.Retrieve(rep.Id)
+	require.NoError(t, err)
+	token := vasp.VerificationToken
+
+	// An admin edits the VASP while the email is being verified
+	db := &racingStore{Store: s.db}
+	db.race = func() {
+		edit, err := db.Store.Retrieve(rep.Id)
+		require.NoError(t, err)
+		edit.VaspEntity.VaspURL = "https://racing.example.com"
+		require.NoError(t, db.Store.Update(edit, store.Actor{Name: "admin"}))
+	}
+	s.db = db
+
+	_, err = s.VerifyEmail(ctx, &pb.VerifyEmailRequest{Id: rep.Id, Token: token})
+	require.Error(t, err)
+	require.Equal(t, codes.Aborted, status.Code(err))
+
+	// The request can be retried with the latest version of the VASP
+	_, err = s.VerifyEmail(ctx, &pb.VerifyEmailRequest{Id: rep.Id, Token: token})
+	require.NoError(t, err)
+
+	vasp, err = s.db.Retrieve(rep.Id)
+	require.NoError(t, err)
+	require.Equal(t, pb.VerificationState_PENDING_REVIEW, vasp.VerificationStatus)
+	require.Equal(t, "https://racing.example.com", vasp.VaspEntity.VaspURL)
+}
+
+func TestRegisterNormalizes(t *testing.T) {
+	s := testServer(t)
+	ctx := context.Background()
+
+	rep, err := s.Register(ctx, &pb.RegisterRequest{Entity: &pb.Entity{
+		VaspFullLegalName: "Normal Exchange",
+		VaspLEINumber:     "5067-00ge-1g29-325q-x363",
+		VaspCountry:       "usa",
+	}})
+	require.NoError(t, err)
+
+	vasp, err := s.db.Retrieve(rep.Id)
+	require.NoError(t, err)
+	require.Equal(t, "506700GE1G29325QX363", vasp.VaspEntity.VaspLEINumber)
+	require.Equal(t, "US", vasp.VaspEntity.VaspCountry)
+
+	lookup, err := s.Lookup(ctx, &pb.LookupRequest{Query: &pb.LookupRequest_Lei{Lei: "506700ge1g29325qx363"}})
+	require.NoError(t, err)
+	require.Equal(t, rep.Id, lookup.Vasp.Id)
+
+	// Alpha-2 and alpha-3 country codes find the same VASPs
+	for _, country := range []string{"US", "USA", "usa"} {
+		results, err := s.Search(ctx, &pb.SearchRequest{Country: []string{country}})
+		require.NoError(t, err)
+		require.Len(t, results.Vasps, 1, country)
+
+		results, err = s.Search(ctx, &pb.SearchRequest{Query: &pb.Query{Country: []string{country}}})
+		require.NoError(t, err)
+		require.Len(t, results.Vasps, 1, country)
+	}
+}
@@ -0,0 +1,260 @@
+/*
+Package validate checks that the VASP entity records submitted to the TRISA directory
+service are well formed before they are stored, returning field-level violations.
+*/
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bbengfort/trisads/pb"
+	"golang.org/x/text/language"
+)
+
+// Categories is the vocabulary of VASP categories accepted by the directory service.
+var Categories = []string{"ATM", "EXCHANGE", "HIGH RISK EXCHANGE"}
+
+// Date formats accepted for the VASP incorporation date (ISO 8601).
+var dateFormats = []string{"2006-01-02", time.RFC3339}
+
+// Violation describes a field of the entity that is invalid.
+type Violation struct {
+	Field       string
+	Description string
+}
+
+// Violations are returned as an error when the entity is invalid.
+type Violations []Violation
+
+// Error implements the error interface.
+func (v Violations) Error() string {
+	msgs := make([]string, 0, len(v))
+	for _, violation := range v {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", violation.Field, violation.Description))
+	}
+	return "invalid entity: " + strings.Join(msgs, "; ")
+}
+
+// add a violation for the specified field.
+func (v *Violations) add(field, format string, args ...interface{}) {
+	*v = append(*v, Violation{Field: field, Description: fmt.Sprintf(format, args...)})
+}
+
+// returns the violations as an error or nil if there are no violations.
+func (v Violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
+// Normalize converts the fields of a VASP entity that have more than one accepted form
+// into a canonical form, so that the stored entity is indexed and matched consistently;
+// it should be called before the entity is validated. LEIs are upper-cased without spaces
+// or dashes and countries are converted to their ISO 3166-1 alpha-2 code.
+func Normalize(e *pb.Entity) {
+	if e == nil {
+		return
+	}
+	e.VaspLEINumber = NormalizeLEI(e.VaspLEINumber)
+	e.VaspCountry = NormalizeCountry(e.VaspCountry)
+}
+
+// NormalizeLEI returns the LEI upper-cased without the spaces or dashes it is often
+// formatted with, e.g. "5493-001k-jtii-gc8y-1r12" is "5493001KJTIIGC8Y1R12".
+func NormalizeLEI(value string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(value))
+}
+
+// NormalizeCountry returns the ISO 3166-1 alpha-2 code of an alpha-2 or alpha-3 country
+// code in any case, e.g. "usa" is "US". Values that are not country codes are returned
+// without surrounding whitespace so that they are reported by Country.
+func NormalizeCountry(value string) string {
+	value = strings.TrimSpace(value)
+	if Country(value) != nil {
+		return value
+	}
+
+	region, _ := language.ParseRegion(value)
+	return region.String()
+}
+
+// Entity checks that the fields of a VASP entity are valid. The full legal name is
+// required, all other fields are optional but must be well formed if they are set.
+// If the entity is invalid, the returned error is a Violations.
+func Entity(e *pb.Entity) error {
+	return entity(e).err()
+}
+
+// Verifiable checks that a VASP entity is valid and has the fields that are required for
+// verification: a contact email to verify and a URL to issue the certificate for.
+func Verifiable(e *pb.Entity) error {
+	violations := entity(e)
+	if e != nil {
+		if strings.TrimSpace(e.VaspContactEmail) == "" {
+			violations.add("vaspContactEmail", "a contact email is required for verification")
+		}
+
+		if strings.TrimSpace(e.VaspURL) == "" {
+			violations.add("vaspURL", "a url is required for verification")
+		}
+	}
+	return violations.err()
+}
+
+// performs the validation of each of the entity fields.
+func entity(e *pb.Entity) (violations Violations) {
+	if e == nil {
+		violations.add("entity", "an entity is required")
+		return violations
+	}
+
+	if strings.TrimSpace(e.VaspFullLegalName) == "" {
+		violations.add("vaspFullLegalName", "the full legal name is required")
+	}
+
+	if e.VaspIncorporationDate != "" {
+		if err := Date(e.VaspIncorporationDate); err != nil {
+			violations.add("vaspIncorporationDate", err.Error())
+		}
+	}
+
+	if e.VaspLEINumber != "" {
+		if err := LEI(e.VaspLEINumber); err != nil {
+			violations.add("vaspLEINumber", err.Error())
+		}
+	}
+
+	if e.VaspContactEmail != "" {
+		if err := Email(e.VaspContactEmail); err != nil {
+			violations.add("vaspContactEmail", err.Error())
+		}
+	}
+
+	if e.VaspURL != "" {
+		if err := URL(e.VaspURL); err != nil {
+			violations.add("vaspURL", err.Error())
+		}
+	}
+
+	if e.VaspCategory != "" {
+		if err := Category(e.VaspCategory); err != nil {
+			violations.add("vaspCategory", err.Error())
+		}
+	}
+
+	if e.VaspCountry != "" {
+		if err := Country(e.VaspCountry); err != nil {
+			violations.add("vaspCountry", err.Error())
+		}
+	}
+
+	return violations
+}
+
+// LEI checks that the value is a 20 character ISO 17442 legal entity identifier whose
+// last two digits are a valid ISO 7064 MOD 97-10 checksum.
+func LEI(value string) error {
+	if len(value) != 20 {
+		return fmt.Errorf("LEI must be 20 characters, not %d", len(value))
+	}
+
+	// Compute the remainder of the number formed by converting letters to 10-35
+	var remainder int
+	for i, c := range value {
+		switch {
+		case c >= '0' && c <= '9':
+			remainder = (remainder*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z' && i < 18:
+			remainder = (remainder*100 + int(c-'A') + 10) % 97
+		default:
+			return fmt.Errorf("invalid LEI character %q", c)
+		}
+	}
+
+	if remainder != 1 {
+		return fmt.Errorf("invalid LEI checksum")
+	}
+	return nil
+}
+
+// Country checks that the value is an ISO 3166-1 alpha-2 or alpha-3 country code.
+func Country(value string) error {
+	if (len(value) != 2 && len(value) != 3) || strings.IndexFunc(value, notLetter) >= 0 {
+		return fmt.Errorf("%q is not an ISO 3166-1 alpha-2 or alpha-3 country code", value)
+	}
+
+	region, err := language.ParseRegion(value)
+	if err != nil || !region.IsCountry() || region.ISO3() == "ZZZ" {
+		return fmt.Errorf("unknown ISO 3166-1 country code %q", value)
+	}
+	return nil
+}
+
+// Email checks that the value is a single RFC 5322 email address without a display name.
+func Email(value string) error {
+	addr, err := mail.ParseAddress(value)
+	if err != nil {
+		return fmt.Errorf("invalid email address: %s", err)
+	}
+
+	if addr.Address != value {
+		return fmt.Errorf("%q should be only an email address", value)
+	}
+	return nil
+}
+
+// URL checks that the value is a parseable http or https URL with a host. The scheme may
+// be omitted, in which case https is assumed.
+func URL(value string) error {
+	if !strings.Contains(value, "://") {
+		value = "https://" + value
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid url: %s", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported url scheme %q", u.Scheme)
+	}
+
+	if u.Hostname() == "" {
+		return fmt.Errorf("url has no host")
+	}
+	return nil
+}
+
+// Date checks that the value is an ISO 8601 date (YYYY-MM-DD) or timestamp that is not
+// in the future.
+func Date(value string) error {
+	for _, layout := range dateFormats {
+		if date, err := time.Parse(layout, value); err == nil {
+			if date.After(time.Now()) {
+				return fmt.Errorf("date cannot be in the future")
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not an ISO 8601 date (YYYY-MM-DD)", value)
+}
+
+// Category checks that the value is in the vocabulary of known VASP categories.
+func Category(value string) error {
+	for _, category := range Categories {
+		if strings.EqualFold(value, category) {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown category %q, expected one of %s", value, strings.Join(Categories, ", "))
+}
+
+// returns true if the rune is not an ASCII letter.
+func notLetter(c rune) bool {
+	return !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z')
+}
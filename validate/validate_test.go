@@ -0,0 +1,106 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLEI(t *testing.T) {
+	require.NoError(t, LEI("506700GE1G29325QX363"))
+	require.NoError(t, LEI("HWUPKR0MPOU8FGXBT394"))
+	require.Error(t, LEI("506700GE1G29325QX364"), "bad checksum")
+	require.Error(t, LEI("506700GE1G29325QX36"), "too short")
+	require.Error(t, LEI("506700ge1g29325qx363"), "lowercase")
+	require.Error(t, LEI("506700GE1G29325QXA63"), "letter in check digits")
+}
+
+func TestCountry(t *testing.T) {
+	for _, code := range []string{"US", "USA", "GB", "GBR", "DE", "DEU", "SG", "ALA"} {
+		require.NoError(t, Country(code), code)
+	}
+
+	for _, code := range []string{"", "U", "UK", "ZZ", "EU", "840", "United States", "AAA"} {
+		require.Error(t, Country(code), code)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	entity := &pb.Entity{VaspLEINumber: "5067-00ge-1g29-325q-x363", VaspCountry: " usa "}
+	Normalize(entity)
+	require.Equal(t, "506700GE1G29325QX363", entity.VaspLEINumber)
+	require.Equal(t, "US", entity.VaspCountry)
+	require.NoError(t, LEI(entity.VaspLEINumber))
+
+	for code, expected := range map[string]string{"US": "US", "usa": "US", "GBR": "GB", "gb": "GB", "ALA": "AX", "": "", "Singapore": "Singapore", "UK": "UK"} {
+		require.Equal(t, expected, NormalizeCountry(code), code)
+	}
+
+	Normalize(nil)
+}
+
+func TestEmail(t *testing.T) {
+	require.NoError(t, Email("compliance@example.com"))
+	require.Error(t, Email("compliance"))
+	require.Error(t, Email("Compliance <compliance@example.com>"))
+	require.Error(t, Email("a@example.com, b@example.com"))
+}
+
+func TestURL(t *testing.T) {
+	require.NoError(t, URL("https://example.com/about"))
+	require.NoError(t, URL("example.com"))
+	require.Error(t, URL("ftp://example.com"))
+	require.Error(t, URL("https://"))
+	require.Error(t, URL("https://exa mple.com"))
+}
+
+func TestDate(t *testing.T) {
+	require.NoError(t, Date("2018-04-21"))
+	require.NoError(t, Date("2018-04-21T12:00:00Z"))
+	require.Error(t, Date("04/21/2018"))
+	require.Error(t, Date("2999-01-01"))
+}
+
+func TestCategory(t *testing.T) {
+	require.NoError(t, Category("EXCHANGE"))
+	require.NoError(t, Category("High Risk Exchange"))
+	require.Error(t, Category("Bank"))
+}
+
+func TestEntity(t *testing.T) {
+	entity := &pb.Entity{
+		VaspFullLegalName:     "Example VASP, Ltd.",
+		VaspIncorporationDate: "2018-04-21",
+		VaspLEINumber:         "506700GE1G29325QX363",
+		VaspContactEmail:      "compliance@example.com",
+		VaspURL:               "https://example.com",
+		VaspCategory:          "EXCHANGE",
+		VaspCountry:           "SG",
+	}
+	require.NoError(t, Entity(entity))
+	require.NoError(t, Verifiable(entity))
+
+	// Optional fields are not required unless the VASP is being verified
+	require.NoError(t, Entity(&pb.Entity{VaspFullLegalName: "Example VASP, Ltd."}))
+	err := Verifiable(&pb.Entity{VaspFullLegalName: "Example VASP, Ltd."})
+	require.Len(t, err, 2)
+
+	// All field violations are returned
+	entity = &pb.Entity{VaspLEINumber: "12345", VaspCountry: "Singapore"}
+	err = Entity(entity)
+	require.Error(t, err)
+	violations, ok := err.(Violations)
+	require.True(t, ok)
+	require.Equal(t, []string{"vaspFullLegalName", "vaspLEINumber", "vaspCountry"}, fields(violations))
+
+	require.Error(t, Entity(nil))
+}
+
+func fields(violations Violations) []string {
+	names := make([]string, 0, len(violations))
+	for _, v := range violations {
+		names = append(names, v.Field)
+	}
+	return names
+}
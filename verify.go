@@ -1,29 +1,130 @@
 package trisads
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
 
 	"github.com/bbengfort/trisads/pb"
 	"github.com/sendgrid/rest"
 	"github.com/sendgrid/sendgrid-go/helpers/mail"
 )
 
-// SendVerificationEmail is a shortcut for iComply verification in which we simply send
-// an email to the TRISA admins and have them manually verify registrations.
-func (s *Server) SendVerificationEmail(vasp pb.VASP) (err error) {
+// Length of the random verification token in bytes (before base64 encoding).
+const verificationTokenLength = 32
+
+// Errors that may occur during the email verification process.
+var (
+	ErrNoContactEmail = errors.New("vasp record has no contact email to verify")
+	ErrInvalidToken   = errors.New("invalid verification token")
+)
+
+// creates the email sent to the VASP contact address with a link that contains the
+// VASP's verification token. Following the link (or submitting the token via the
+// VerifyEmail RPC) confirms that the registrant controls the contact address.
+func (s *Server) verificationEmail(vasp pb.VASP) (_ *mail.SGMailV3, err error) {
+	if vasp.VaspEntity == nil || vasp.VaspEntity.VaspContactEmail == "" {
+		return nil, ErrNoContactEmail
+	}
+
+	var link string
+	if link, err = s.verificationLink(vasp); err != nil {
+		return nil, err
+	}
+
+	from := mail.NewEmail("TRISA Directory Service", s.conf.ServiceEmail)
+	subject := "TRISA Directory Service Email Verification"
+	to := mail.NewEmail(vasp.VaspEntity.VaspFullLegalName, vasp.VaspEntity.VaspContactEmail)
+
+	plainTextContent := fmt.Sprintf("Thank you for registering %s with the TRISA Directory Service. "+
+		"Please verify your contact email address by visiting the following link:\n\n%s\n",
+		vasp.VaspEntity.VaspFullLegalName, link)
+	link = html.EscapeString(link)
+	htmlContent := fmt.Sprintf("<p>Thank you for registering %s with the TRISA Directory Service.</p>"+
+		"<p>Please verify your contact email address by visiting the following link:</p>"+
+		"<p><a href=\"%s\">%s</a></p>",
+		html.EscapeString(vasp.VaspEntity.VaspFullLegalName), link, link)
+
+	return mail.NewSingleEmail(from, subject, to, plainTextContent, htmlContent), nil
+}
+
+// creates the email to the TRISA admins requesting review of a registration once the
+// VASP contact email address has been verified. This is a shortcut for iComply
+// verification in which the TRISA admins manually review registrations.
+func (s *Server) reviewRequestEmail(vasp pb.VASP) (_ *mail.SGMailV3, err error) {
 	from := mail.NewEmail("TRISA Directory Service", s.conf.ServiceEmail)
 	subject := "TRISA Test Net Verification Request"
 	to := mail.NewEmail("TRISA Admins", s.conf.AdminEmail)
 
+	// Do not send secrets to the admins
+	redact(&vasp)
+
 	var data []byte
 	if data, err = json.MarshalIndent(vasp, "", "  "); err != nil {
-		return err
+		return nil, err
 	}
 	plainTextContent := string(data)
-	htmlContent := "<pre>" + plainTextContent + "</pre>"
+	htmlContent := "<pre>" + html.EscapeString(plainTextContent) + "</pre>"
+
+	return mail.NewSingleEmail(from, subject, to, plainTextContent, htmlContent), nil
+}
+
+// creates the email notifying the VASP contact that their registration was rejected
+// by the TRISA admins during review, including the reason for the rejection if given.
+func (s *Server) rejectionEmail(vasp pb.VASP, reason string) (_ *mail.SGMailV3, err error) {
+	if vasp.VaspEntity == nil || vasp.VaspEntity.VaspContactEmail == "" {
+		return nil, ErrNoContactEmail
+	}
+
+	from := mail.NewEmail("TRISA Directory Service", s.conf.ServiceEmail)
+	subject := "TRISA Directory Service Registration Rejected"
+	to := mail.NewEmail(vasp.VaspEntity.VaspFullLegalName, vasp.VaspEntity.VaspContactEmail)
+
+	plainTextContent := fmt.Sprintf("The registration of %s with the TRISA Directory Service "+
+		"has been rejected by the TRISA admins.\n", vasp.VaspEntity.VaspFullLegalName)
+	if reason != "" {
+		plainTextContent += fmt.Sprintf("\nReason: %s\n", reason)
+	}
+	htmlContent := "<pre>" + html.EscapeString(plainTextContent) + "</pre>"
+
+	return mail.NewSingleEmail(from, subject, to, plainTextContent, htmlContent), nil
+}
+
+// creates the email to the VASP contact with the ZIP file containing the VASP's PKCS12
+// encrypted TRISA certificates attached. The certificates can be decrypted with the
+// password that was returned to the VASP when it registered.
+func (s *Server) certificatesEmail(vasp pb.VASP, name string, data []byte) (_ *mail.SGMailV3, err error) {
+	if vasp.VaspEntity == nil || vasp.VaspEntity.VaspContactEmail == "" {
+		return nil, ErrNoContactEmail
+	}
+
+	from := mail.NewEmail("TRISA Directory Service", s.conf.ServiceEmail)
+	subject := "TRISA Directory Service Certificates"
+	to := mail.NewEmail(vasp.VaspEntity.VaspFullLegalName, vasp.VaspEntity.VaspContactEmail)
+
+	plainTextContent := fmt.Sprintf("%s has been verified by the TRISA Directory Service. "+
+		"Your TRISA certificates are attached; decrypt them using the PKCS12 password that "+
+		"was returned when you registered.\n", vasp.VaspEntity.VaspFullLegalName)
+	htmlContent := "<pre>" + html.EscapeString(plainTextContent) + "</pre>"
+
+	attachment := mail.NewAttachment()
+	attachment.SetContent(base64.StdEncoding.EncodeToString(data))
+	attachment.SetType("application/zip")
+	attachment.SetFilename(name)
+	attachment.SetDisposition("attachment")
 
 	message := mail.NewSingleEmail(from, subject, to, plainTextContent, htmlContent)
+	message.AddAttachment(attachment)
+	return message, nil
+}
+
+// send an email using the SendGrid client, converting non-200 responses into errors.
+func (s *Server) sendEmail(message *mail.SGMailV3) (err error) {
 	var rep *rest.Response
 	if rep, err = s.email.Send(message); err != nil {
 		return err
@@ -35,3 +136,26 @@ func (s *Server) SendVerificationEmail(vasp pb.VASP) (err error) {
 
 	return nil
 }
+
+// creates the link the VASP contact must follow to verify their email address.
+func (s *Server) verificationLink(vasp pb.VASP) (_ string, err error) {
+	var link *url.URL
+	if link, err = url.Parse(s.conf.VerifyURL); err != nil {
+		return "", fmt.Errorf("could not parse verify url: %s", err)
+	}
+
+	params := link.Query()
+	params.Set("vaspID", strconv.FormatUint(vasp.Id, 10))
+	params.Set("token", vasp.VerificationToken)
+	link.RawQuery = params.Encode()
+	return link.String(), nil
+}
+
+// creates a cryptographically random, url safe verification token.
+func createToken() (_ string, err error) {
+	buf := make([]byte, verificationTokenLength)
+	if _, err = rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,51 @@
+package trisads
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bbengfort/trisads/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerificationEmailEscapesName(t *testing.T) {
+	s := testServer(t)
+	name := `<a href="https://evil.example.com">Evil Exchange</a>`
+
+	rep, err := s.Register(context.Background(), &pb.RegisterRequest{
+		Entity: &pb.Entity{
+			VaspFullLegalName: name,
+			VaspContactEmail:  "admin@evil.example.com",
+			VaspURL:           "https://evil.example.com",
+		},
+		Verify: true,
+	})
+	require.NoError(t, err)
+
+	vasp, err := s.db.Retrieve(rep.Id)
+	require.NoError(t, err)
+
+	message, err := s.verificationEmail(vasp)
+	require.NoError(t, err)
+
+	body := emailContent(message, "text/html")
+	require.Contains(t, body, "&lt;a href=&#34;https://evil.example.com&#34;&gt;Evil Exchange&lt;/a&gt;")
+	require.NotContains(t, body, name)
+
+	// The plain text body is not escaped
+	require.Contains(t, emailContent(message, "text/plain"), name)
+}
+
+func TestCertificatesEmailEscapesName(t *testing.T) {
+	s := testServer(t)
+	vasp := pb.VASP{
+		VaspEntity: &pb.Entity{VaspFullLegalName: "<b>Bold Exchange</b>", VaspContactEmail: "admin@bold.io"},
+	}
+
+	message, err := s.certificatesEmail(vasp, "certs.zip", []byte("zip"))
+	require.NoError(t, err)
+
+	body := emailContent(message, "text/html")
+	require.Contains(t, body, "&lt;b&gt;Bold Exchange&lt;/b&gt;")
+	require.NotContains(t, body, "<b>")
+}
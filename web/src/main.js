@@ -71,6 +71,12 @@ $(document).ready(function() {
     }
 
     client.search(req, {}, (err, response) => {
+      // Errors other than UNAVAILABLE (14) are returned by the directory service
+      if (err && err.code !== 14) {
+        alert("warning", "search error:", err.message);
+        return
+      }
+
       if (err || !response) {
         console.log(err);
         alert("danger", "connection error:", "no response from directory service");
@@ -120,6 +126,12 @@ $(document).ready(function() {
     }
 
     client.lookup(req, {}, (err, response) => {
+      // Errors other than UNAVAILABLE (14) are returned by the directory service
+      if (err && err.code !== 14) {
+        alert("warning", "could not lookup VASP:", err.message);
+        return
+      }
+
       if (err || !response) {
         console.log(err);
         alert("danger", "connection error:", "no response from directory service");